@@ -0,0 +1,727 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Avro schema types.
+const (
+	AvroNull    = "null"
+	AvroBoolean = "boolean"
+	AvroInt     = "int"
+	AvroLong    = "long"
+	AvroFloat   = "float"
+	AvroDouble  = "double"
+	AvroBytes   = "bytes"
+	AvroString  = "string"
+	AvroRecord  = "record"
+	AvroEnum    = "enum"
+	AvroArray   = "array"
+	AvroMap     = "map"
+	AvroUnion   = "union"
+)
+
+// avroName matches valid Avro names and enum symbols.
+var avroName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AvroSchema is an Avro schema. Named schemas (records and enums) are shared by pointers,
+// so recursive types refer to themselves.
+type AvroSchema struct {
+	// Type is a primitive type name, "record", "enum", "array", "map" or "union".
+	Type        string
+	LogicalType string
+
+	// Name, Namespace and Doc are set for records and enums.
+	Name      string
+	Namespace string
+	Doc       string
+
+	Fields  []*AvroField
+	Symbols []string
+	Items   *AvroSchema
+	Values  *AvroSchema
+	// Branches are members of the union.
+	Branches []*AvroSchema
+}
+
+type AvroField struct {
+	Name       string
+	Doc        string
+	Type       *AvroSchema
+	Default    any
+	HasDefault bool
+}
+
+// FullName returns the namespace-qualified name of the named schema.
+func (s *AvroSchema) FullName() string {
+	if s.Namespace == "" {
+		return s.Name
+	}
+	return s.Namespace + "." + s.Name
+}
+
+func (s *AvroSchema) isNamed() bool {
+	return s.Type == AvroRecord || s.Type == AvroEnum
+}
+
+// MarshalJSON encodes the schema in the Avro JSON format. Named schemas are defined on the first occurrence
+// and referenced by the full name afterwards.
+func (s *AvroSchema) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.encode(&buf, make(map[*AvroSchema]struct{})); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *AvroSchema) encode(buf *bytes.Buffer, defined map[*AvroSchema]struct{}) error {
+	writeJSON := func(v any) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+	if s.isNamed() {
+		if _, ok := defined[s]; ok {
+			return writeJSON(s.FullName())
+		}
+		defined[s] = struct{}{}
+	}
+	switch s.Type {
+	case AvroUnion:
+		buf.WriteByte('[')
+		for i, b := range s.Branches {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := b.encode(buf, defined); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case AvroNull, AvroBoolean, AvroInt, AvroLong, AvroFloat, AvroDouble, AvroBytes, AvroString:
+		if s.LogicalType == "" {
+			return writeJSON(s.Type)
+		}
+	}
+
+	buf.WriteString(`{"type":`)
+	if err := writeJSON(s.Type); err != nil {
+		return err
+	}
+	attr := func(key string, v any) error {
+		buf.WriteString(`,"` + key + `":`)
+		return writeJSON(v)
+	}
+	if s.LogicalType != "" {
+		if err := attr("logicalType", s.LogicalType); err != nil {
+			return err
+		}
+	}
+	if s.isNamed() {
+		if err := attr("name", s.Name); err != nil {
+			return err
+		}
+		if s.Namespace != "" {
+			if err := attr("namespace", s.Namespace); err != nil {
+				return err
+			}
+		}
+		if s.Doc != "" {
+			if err := attr("doc", s.Doc); err != nil {
+				return err
+			}
+		}
+	}
+	switch s.Type {
+	case AvroRecord:
+		buf.WriteString(`,"fields":[`)
+		for i, f := range s.Fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := f.encode(buf, defined); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case AvroEnum:
+		if err := attr("symbols", s.Symbols); err != nil {
+			return err
+		}
+	case AvroArray:
+		buf.WriteString(`,"items":`)
+		if err := s.Items.encode(buf, defined); err != nil {
+			return err
+		}
+	case AvroMap:
+		buf.WriteString(`,"values":`)
+		if err := s.Values.encode(buf, defined); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func (f *AvroField) encode(buf *bytes.Buffer, defined map[*AvroSchema]struct{}) error {
+	name, err := json.Marshal(f.Name)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(`{"name":`)
+	buf.Write(name)
+	if f.Doc != "" {
+		doc, errDoc := json.Marshal(f.Doc)
+		if errDoc != nil {
+			return errDoc
+		}
+		buf.WriteString(`,"doc":`)
+		buf.Write(doc)
+	}
+	buf.WriteString(`,"type":`)
+	if err = f.Type.encode(buf, defined); err != nil {
+		return err
+	}
+	if f.HasDefault {
+		def, errDef := json.Marshal(f.Default)
+		if errDef != nil {
+			return errDef
+		}
+		buf.WriteString(`,"default":`)
+		buf.Write(def)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+type AvroNamespaceFunc func(location string) string
+
+type AvroConverterOptions struct {
+	namespace AvroNamespaceFunc
+}
+
+type AvroConverterOpt interface {
+	apply(*AvroConverterOptions)
+}
+
+type optAvroNamespace struct{ f AvroNamespaceFunc }
+
+func (o optAvroNamespace) apply(c *AvroConverterOptions) { c.namespace = o.f }
+
+// WithAvroNamespace sets the function that derives the namespace of named schemas from the location
+// of the library the type is defined in. By default, the file name without the extension is used.
+func WithAvroNamespace(f AvroNamespaceFunc) AvroConverterOpt {
+	return optAvroNamespace{f}
+}
+
+// AvroConverter converts unwrapped RAML types to Avro schemas.
+//
+// Objects become records, optional properties become unions with null that default to null, string enums
+// become enums, datetime becomes long with the timestamp-millis logical type, date-only becomes int with
+// the date logical type. Named types become named schemas in namespaces derived from library locations.
+type AvroConverter struct {
+	ShapeVisitor[*AvroSchema]
+
+	opts AvroConverterOptions
+
+	resolver *namedTypeResolver
+	named    map[int64]*AvroSchema
+	// hint is the name and namespace of the next anonymous record or enum.
+	hint      string
+	namespace string
+	err       error
+}
+
+func NewAvroConverter(opt ...AvroConverterOpt) *AvroConverter {
+	c := &AvroConverter{opts: AvroConverterOptions{namespace: avroNamespaceFromLocation}}
+	for _, o := range opt {
+		o.apply(&c.opts)
+	}
+	return c
+}
+
+func avroNamespaceFromLocation(location string) string {
+	base := filepath.Base(location)
+	return avroIdent(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// avroIdent converts the name to a valid Avro name.
+func avroIdent(name string) string {
+	ident := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if ident == "" || ident[0] >= '0' && ident[0] <= '9' {
+		ident = "_" + ident
+	}
+	return ident
+}
+
+// Convert converts the named type to the Avro schema. The shape must be unwrapped.
+func (c *AvroConverter) Convert(base *BaseShape) (*AvroSchema, error) {
+	if !base.IsUnwrapped() {
+		return nil, fmt.Errorf("shape must be unwrapped")
+	}
+	c.reset(nil)
+	return c.convert(base)
+}
+
+// ConvertLibrary converts the types of the library to named schemas in the order of declaration. Types that do not
+// convert to records or enums, e.g. arrays and primitives, are inlined where they are referenced and not returned.
+// Named schemas are shared between the returned schemas. The library must be unwrapped.
+func (c *AvroConverter) ConvertLibrary(lib *Library) ([]*AvroSchema, error) {
+	c.reset(lib)
+	var schemas []*AvroSchema
+	if lib.Types == nil {
+		return schemas, nil
+	}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.IsUnwrapped() {
+			return nil, fmt.Errorf("shape must be unwrapped")
+		}
+		schema, err := c.convert(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("convert type %s: %w", pair.Key, err)
+		}
+		if schema.isNamed() {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas, nil
+}
+
+func (c *AvroConverter) reset(lib *Library) {
+	c.resolver = newNamedTypeResolver(lib)
+	c.named = make(map[int64]*AvroSchema)
+}
+
+func (c *AvroConverter) convert(base *BaseShape) (*AvroSchema, error) {
+	if s, ok := c.named[base.ID]; ok {
+		return s, nil
+	}
+	c.err = nil
+	c.hint, c.namespace = avroIdent(base.Name), c.opts.namespace(base.Location)
+	schema := c.Visit(base.Shape)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return schema, nil
+}
+
+func (c *AvroConverter) fail(format string, args ...any) *AvroSchema {
+	if c.err == nil {
+		c.err = fmt.Errorf(format, args...)
+	}
+	return &AvroSchema{Type: AvroNull}
+}
+
+// schema returns the schema of the shape. References to named types produce the named schema
+// in the namespace of the library the type is defined in.
+func (c *AvroConverter) schema(base *BaseShape, hint string) *AvroSchema {
+	if s, ok := c.named[base.ID]; ok {
+		return s
+	}
+	prevHint, prevNamespace := c.hint, c.namespace
+	defer func() {
+		c.hint, c.namespace = prevHint, prevNamespace
+	}()
+	c.hint = hint
+	if nt, ok := c.resolver.reference(base); ok {
+		if s, found := c.named[nt.shape.ID]; found {
+			return s
+		}
+		c.hint, c.namespace = avroIdent(nt.name), c.opts.namespace(nt.location)
+		return c.Visit(nt.shape.Shape)
+	}
+	return c.Visit(base.Shape)
+}
+
+func (c *AvroConverter) Visit(s Shape) *AvroSchema {
+	switch shapeType := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(shapeType)
+	case *ArrayShape:
+		return c.VisitArrayShape(shapeType)
+	case *StringShape:
+		return c.VisitStringShape(shapeType)
+	case *NumberShape:
+		return c.VisitNumberShape(shapeType)
+	case *IntegerShape:
+		return c.VisitIntegerShape(shapeType)
+	case *BooleanShape:
+		return c.VisitBooleanShape(shapeType)
+	case *FileShape:
+		return c.VisitFileShape(shapeType)
+	case *UnionShape:
+		return c.VisitUnionShape(shapeType)
+	case *NilShape:
+		return c.VisitNilShape(shapeType)
+	case *AnyShape:
+		return c.VisitAnyShape(shapeType)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(shapeType)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(shapeType)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(shapeType)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(shapeType)
+	case *JSONShape:
+		return c.VisitJSONShape(shapeType)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(shapeType)
+	default:
+		return c.fail("unsupported shape %T", s)
+	}
+}
+
+func (c *AvroConverter) VisitObjectShape(s *ObjectShape) *AvroSchema {
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		if s.PatternProperties == nil || s.PatternProperties.Len() != 1 {
+			return c.fail("%s: object without properties must have a single pattern property", c.hint)
+		}
+		pp := s.PatternProperties.Oldest().Value
+		return &AvroSchema{Type: AvroMap, Values: c.schema(pp.Base, c.hint+"Value")}
+	}
+	record := &AvroSchema{Type: AvroRecord, Name: c.hint, Namespace: c.namespace, Doc: avroDoc(s.Base())}
+	c.named[s.ID] = record
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		if !avroName.MatchString(prop.Name) {
+			return c.fail("%s: property name %q is not a valid Avro name", record.Name, prop.Name)
+		}
+		field := &AvroField{
+			Name: prop.Name,
+			Doc:  avroDoc(prop.Base),
+			Type: c.schema(prop.Base, record.Name+goIdent(prop.Name)),
+		}
+		switch {
+		case !prop.Required:
+			field.Type = avroNullable(field.Type)
+			field.Default, field.HasDefault = nil, true
+		case prop.Base.Default != nil && field.Type.Type != AvroUnion && field.Type.LogicalType == "":
+			field.Default, field.HasDefault = prop.Base.Default.Value, true
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record
+}
+
+// avroNullable returns the union of null and the schema with null as the first branch,
+// so that the field can default to null.
+func avroNullable(s *AvroSchema) *AvroSchema {
+	branches := []*AvroSchema{{Type: AvroNull}}
+	if s.Type != AvroUnion {
+		return &AvroSchema{Type: AvroUnion, Branches: append(branches, s)}
+	}
+	for _, b := range s.Branches {
+		if b.Type != AvroNull {
+			branches = append(branches, b)
+		}
+	}
+	return &AvroSchema{Type: AvroUnion, Branches: branches}
+}
+
+func (c *AvroConverter) VisitArrayShape(s *ArrayShape) *AvroSchema {
+	if s.Items == nil {
+		return c.fail("%s: array items must be defined", c.hint)
+	}
+	return &AvroSchema{Type: AvroArray, Items: c.schema(s.Items, c.hint+"Item")}
+}
+
+// VisitUnionShape converts the union. Nested unions are flattened since Avro does not allow them.
+func (c *AvroConverter) VisitUnionShape(s *UnionShape) *AvroSchema {
+	union := &AvroSchema{Type: AvroUnion}
+	seen := make(map[string]*AvroSchema)
+	add := func(b *AvroSchema) {
+		key := b.Type
+		if b.isNamed() {
+			key = b.FullName()
+		}
+		prev, ok := seen[key]
+		if !ok {
+			seen[key] = b
+			union.Branches = append(union.Branches, b)
+			return
+		}
+		// NOTE: Avro allows a single branch per unnamed type, so only identical branches can be merged.
+		if !b.isNamed() && !avroSameSchema(prev, b) {
+			c.fail("%s: union has several %s branches that Avro cannot distinguish", c.hint, b.Type)
+		}
+	}
+	for _, item := range s.AnyOf {
+		hint := c.hint
+		if item.TypeLabel != "" {
+			hint = avroIdent(item.TypeLabel)
+		}
+		branch := c.schema(item, hint)
+		if branch.Type != AvroUnion {
+			add(branch)
+			continue
+		}
+		for _, b := range branch.Branches {
+			add(b)
+		}
+	}
+	return union
+}
+
+// avroSameSchema reports whether the schemas have the same JSON encoding.
+func avroSameSchema(a, b *AvroSchema) bool {
+	aData, errA := json.Marshal(a)
+	bData, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aData, bData)
+}
+
+func (c *AvroConverter) VisitStringShape(s *StringShape) *AvroSchema {
+	if len(s.Enum) == 0 {
+		return &AvroSchema{Type: AvroString}
+	}
+	enum := &AvroSchema{Type: AvroEnum, Name: c.hint, Namespace: c.namespace, Doc: avroDoc(s.Base())}
+	for _, e := range s.Enum {
+		symbol := fmt.Sprint(e.Value)
+		if !avroName.MatchString(symbol) {
+			return c.fail("%s: enum value %q is not a valid Avro symbol", enum.Name, symbol)
+		}
+		enum.Symbols = append(enum.Symbols, symbol)
+	}
+	c.named[s.ID] = enum
+	return enum
+}
+
+func (c *AvroConverter) VisitIntegerShape(s *IntegerShape) *AvroSchema {
+	if s.Format != nil && SetOfIntegerFormats[*s.Format] <= SetOfIntegerFormats["int32"] {
+		return &AvroSchema{Type: AvroInt}
+	}
+	return &AvroSchema{Type: AvroLong}
+}
+
+func (c *AvroConverter) VisitNumberShape(s *NumberShape) *AvroSchema {
+	if s.Format != nil && *s.Format == "float" {
+		return &AvroSchema{Type: AvroFloat}
+	}
+	return &AvroSchema{Type: AvroDouble}
+}
+
+func (c *AvroConverter) VisitBooleanShape(_ *BooleanShape) *AvroSchema {
+	return &AvroSchema{Type: AvroBoolean}
+}
+
+func (c *AvroConverter) VisitFileShape(_ *FileShape) *AvroSchema {
+	return &AvroSchema{Type: AvroBytes}
+}
+
+func (c *AvroConverter) VisitNilShape(_ *NilShape) *AvroSchema {
+	return &AvroSchema{Type: AvroNull}
+}
+
+func (c *AvroConverter) VisitAnyShape(_ *AnyShape) *AvroSchema {
+	return c.fail("%s: any type cannot be represented in Avro", c.hint)
+}
+
+func (c *AvroConverter) VisitDateTimeShape(_ *DateTimeShape) *AvroSchema {
+	return &AvroSchema{Type: AvroLong, LogicalType: "timestamp-millis"}
+}
+
+func (c *AvroConverter) VisitDateTimeOnlyShape(_ *DateTimeOnlyShape) *AvroSchema {
+	return &AvroSchema{Type: AvroLong, LogicalType: "local-timestamp-millis"}
+}
+
+func (c *AvroConverter) VisitDateOnlyShape(_ *DateOnlyShape) *AvroSchema {
+	return &AvroSchema{Type: AvroInt, LogicalType: "date"}
+}
+
+func (c *AvroConverter) VisitTimeOnlyShape(_ *TimeOnlyShape) *AvroSchema {
+	return &AvroSchema{Type: AvroInt, LogicalType: "time-millis"}
+}
+
+func (c *AvroConverter) VisitJSONShape(_ *JSONShape) *AvroSchema {
+	return c.fail("%s: JSON schema type cannot be represented in Avro", c.hint)
+}
+
+func (c *AvroConverter) VisitRecursiveShape(s *RecursiveShape) *AvroSchema {
+	return c.schema(s.Head, c.hint)
+}
+
+func avroDoc(base *BaseShape) string {
+	if base.Description == nil {
+		return ""
+	}
+	return *base.Description
+}
+
+// AvroIncompatibility describes a change that prevents the reader schema from reading data
+// written with the writer schema.
+type AvroIncompatibility struct {
+	// Path is the path to the incompatible schema, e.g. "Order.items[]".
+	Path    string
+	Message string
+}
+
+func (i AvroIncompatibility) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// CheckAvroCompatibility returns incompatibilities that prevent the reader schema from reading data
+// written with the writer schema according to the Avro schema resolution rules.
+// No incompatibilities mean that the reader schema is backward compatible with the writer schema.
+func CheckAvroCompatibility(reader, writer *AvroSchema) []AvroIncompatibility {
+	chk := &avroCompatibilityChecker{checked: make(map[[2]*AvroSchema]struct{})}
+	chk.check(reader, writer, avroPathName(reader))
+	return chk.issues
+}
+
+// AvroBackwardCompatible converts two versions of the type and reports whether the Avro schema of the new
+// version can read data written with the Avro schema of the old version.
+func AvroBackwardCompatible(oldType, newType *BaseShape, opts ...AvroConverterOpt) ([]AvroIncompatibility, error) {
+	writer, err := NewAvroConverter(opts...).Convert(oldType)
+	if err != nil {
+		return nil, fmt.Errorf("convert old type: %w", err)
+	}
+	reader, err := NewAvroConverter(opts...).Convert(newType)
+	if err != nil {
+		return nil, fmt.Errorf("convert new type: %w", err)
+	}
+	return CheckAvroCompatibility(reader, writer), nil
+}
+
+type avroCompatibilityChecker struct {
+	checked map[[2]*AvroSchema]struct{}
+	issues  []AvroIncompatibility
+}
+
+func avroPathName(s *AvroSchema) string {
+	if s.isNamed() {
+		return s.Name
+	}
+	return "$"
+}
+
+func (chk *avroCompatibilityChecker) report(path, format string, args ...any) {
+	chk.issues = append(chk.issues, AvroIncompatibility{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (chk *avroCompatibilityChecker) check(reader, writer *AvroSchema, path string) {
+	key := [2]*AvroSchema{reader, writer}
+	if _, ok := chk.checked[key]; ok {
+		return
+	}
+	chk.checked[key] = struct{}{}
+
+	if writer.Type == AvroUnion {
+		// Every branch the data may be written with must be readable.
+		for _, b := range writer.Branches {
+			if !chk.resolvable(reader, b) {
+				chk.report(path, "writer type %s cannot be read", avroTypeName(b))
+				continue
+			}
+			chk.check(reader, b, path)
+		}
+		return
+	}
+	if reader.Type == AvroUnion {
+		for _, b := range reader.Branches {
+			if avroMatches(b, writer) {
+				chk.check(b, writer, path)
+				return
+			}
+		}
+		chk.report(path, "reader union does not contain writer type %s", avroTypeName(writer))
+		return
+	}
+	if !avroMatches(reader, writer) {
+		chk.report(path, "writer type %s cannot be read as %s", avroTypeName(writer), avroTypeName(reader))
+		return
+	}
+
+	switch reader.Type {
+	case AvroRecord:
+		chk.checkRecord(reader, writer, path)
+	case AvroEnum:
+		symbols := make(map[string]struct{}, len(reader.Symbols))
+		for _, s := range reader.Symbols {
+			symbols[s] = struct{}{}
+		}
+		for _, s := range writer.Symbols {
+			if _, ok := symbols[s]; !ok {
+				chk.report(path, "enum symbol %s was removed", s)
+			}
+		}
+	case AvroArray:
+		chk.check(reader.Items, writer.Items, path+"[]")
+	case AvroMap:
+		chk.check(reader.Values, writer.Values, path+"{}")
+	}
+}
+
+func (chk *avroCompatibilityChecker) checkRecord(reader, writer *AvroSchema, path string) {
+	writerFields := make(map[string]*AvroField, len(writer.Fields))
+	for _, f := range writer.Fields {
+		writerFields[f.Name] = f
+	}
+	for _, f := range reader.Fields {
+		wf, ok := writerFields[f.Name]
+		if !ok {
+			if !f.HasDefault {
+				chk.report(path+"."+f.Name, "field was added without a default value")
+			}
+			continue
+		}
+		chk.check(f.Type, wf.Type, path+"."+f.Name)
+	}
+}
+
+// resolvable reports whether the data written with the non-union writer schema can be read by the reader.
+func (chk *avroCompatibilityChecker) resolvable(reader, writer *AvroSchema) bool {
+	if reader.Type != AvroUnion {
+		return avroMatches(reader, writer)
+	}
+	for _, b := range reader.Branches {
+		if avroMatches(b, writer) {
+			return true
+		}
+	}
+	return false
+}
+
+// avroMatches reports whether the schemas match according to the Avro schema resolution rules,
+// including promotions of primitive types.
+func avroMatches(reader, writer *AvroSchema) bool {
+	if reader.isNamed() || writer.isNamed() {
+		return reader.Type == writer.Type && reader.Name == writer.Name
+	}
+	if reader.Type == writer.Type {
+		return true
+	}
+	switch writer.Type {
+	case AvroInt:
+		return reader.Type == AvroLong || reader.Type == AvroFloat || reader.Type == AvroDouble
+	case AvroLong:
+		return reader.Type == AvroFloat || reader.Type == AvroDouble
+	case AvroFloat:
+		return reader.Type == AvroDouble
+	case AvroString:
+		return reader.Type == AvroBytes
+	case AvroBytes:
+		return reader.Type == AvroString
+	}
+	return false
+}
+
+func avroTypeName(s *AvroSchema) string {
+	if s.isNamed() {
+		return s.FullName()
+	}
+	return s.Type
+}
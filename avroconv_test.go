@@ -0,0 +1,235 @@
+package raml
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvroConverter_Convert(t *testing.T) {
+	lib := parseTestLibrary(t, map[string]string{
+		"common.raml": `#%RAML 1.0 Library
+types:
+  Status:
+    type: string
+    enum: [active, disabled]
+`,
+		"library.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+types:
+  Node:
+    properties:
+      value: string
+      next?: Node
+  Event:
+    description: An event.
+    properties:
+      id:
+        type: integer
+        format: int32
+      status: common.Status
+      at: datetime
+      day: date-only
+      count:
+        type: integer
+        default: 1
+      note?: string
+      payload: string | integer
+      tags: string[]
+      labels:
+        properties:
+          /.*/: number
+      node: Node
+`,
+	}, "library.raml", OptWithUnwrap())
+	event, ok := lib.Types.Get("Event")
+	require.True(t, ok)
+
+	schema, err := NewAvroConverter().Convert(event)
+	require.NoError(t, err)
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+	want := `{"type":"record","name":"Event","namespace":"library","doc":"An event.","fields":[
+		{"name":"id","type":"int"},
+		{"name":"status","type":{"type":"enum","name":"Status","namespace":"common","symbols":["active","disabled"]}},
+		{"name":"at","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"day","type":{"type":"int","logicalType":"date"}},
+		{"name":"count","type":"long","default":1},
+		{"name":"note","type":["null","string"],"default":null},
+		{"name":"payload","type":["string","long"]},
+		{"name":"tags","type":{"type":"array","items":"string"}},
+		{"name":"labels","type":{"type":"map","values":"double"}},
+		{"name":"node","type":{"type":"record","name":"Node","namespace":"library","fields":[
+			{"name":"value","type":"string"},
+			{"name":"next","type":["null","library.Node"],"default":null}
+		]}}
+	]}`
+	require.JSONEq(t, want, string(got))
+
+	t.Run("namespace option", func(t *testing.T) {
+		s, errConv := NewAvroConverter(WithAvroNamespace(func(string) string { return "com.acme" })).Convert(event)
+		require.NoError(t, errConv)
+		require.Equal(t, "com.acme.Event", s.FullName())
+	})
+}
+
+func TestAvroConverter_ConvertErrors(t *testing.T) {
+	lib := parseTestLibrary(t, map[string]string{
+		"library.raml": `#%RAML 1.0 Library
+types:
+  Any:
+    properties:
+      a: any
+  Name:
+    properties:
+      x-id: string
+  Symbol:
+    type: string
+    enum: [a-b]
+  Lists:
+    properties:
+      value: string[] | integer[]
+  Times:
+    properties:
+      value: integer | datetime
+`,
+	}, "library.raml", OptWithUnwrap())
+	for _, name := range []string{"Any", "Name", "Symbol", "Lists", "Times"} {
+		t.Run(name, func(t *testing.T) {
+			shape, ok := lib.Types.Get(name)
+			require.True(t, ok)
+			_, err := NewAvroConverter().Convert(shape)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAvroConverter_ConvertLibrary(t *testing.T) {
+	lib := parseTestLibrary(t, map[string]string{
+		"library.raml": `#%RAML 1.0 Library
+types:
+  Status:
+    type: string
+    enum: [active, disabled]
+  Tags: string[]
+  Event:
+    properties:
+      status: Status
+      tags: Tags
+      names: string | Status
+`,
+	}, "library.raml", OptWithUnwrap())
+
+	schemas, err := NewAvroConverter().ConvertLibrary(lib)
+	require.NoError(t, err)
+	require.Len(t, schemas, 2)
+	require.Equal(t, "library.Status", schemas[0].FullName())
+	require.Equal(t, "library.Event", schemas[1].FullName())
+	require.Same(t, schemas[0], schemas[1].Fields[0].Type, "named schemas must be shared")
+	got, err := json.Marshal(schemas[1])
+	require.NoError(t, err)
+	want := `{"type":"record","name":"Event","namespace":"library","fields":[
+		{"name":"status","type":{"type":"enum","name":"Status","namespace":"library","symbols":["active","disabled"]}},
+		{"name":"tags","type":{"type":"array","items":"string"}},
+		{"name":"names","type":["string","library.Status"]}
+	]}`
+	require.JSONEq(t, want, string(got))
+}
+
+func TestAvroBackwardCompatible(t *testing.T) {
+	lib := parseTestLibrary(t, map[string]string{
+		"library.raml": `#%RAML 1.0 Library
+types:
+  V1:
+    properties:
+      id:
+        type: integer
+        format: int32
+      status:
+        type: string
+        enum: [a, b]
+      name: string
+  AddedOptional:
+    properties:
+      id:
+        type: integer
+        format: int32
+      status:
+        type: string
+        enum: [a, b, c]
+      name: string
+      note?: string
+  Promoted:
+    properties:
+      id: integer
+      status:
+        type: string
+        enum: [a, b]
+  AddedRequired:
+    properties:
+      id:
+        type: integer
+        format: int32
+      status:
+        type: string
+        enum: [a, b]
+      name: string
+      note: string
+  RemovedSymbol:
+    properties:
+      id:
+        type: integer
+        format: int32
+      status:
+        type: string
+        enum: [a]
+      name: string
+  ChangedType:
+    properties:
+      id: string
+      status:
+        type: string
+        enum: [a, b]
+      name: string
+`,
+	}, "library.raml", OptWithUnwrap())
+	v1, ok := lib.Types.Get("V1")
+	require.True(t, ok)
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "AddedOptional"},
+		{name: "Promoted"},
+		{name: "AddedRequired", want: []string{"V1.note: field was added without a default value"}},
+		{name: "RemovedSymbol", want: []string{"V1.status: enum symbol b was removed"}},
+		{name: "ChangedType", want: []string{"V1.id: writer type int cannot be read as string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, found := lib.Types.Get(tt.name)
+			require.True(t, found)
+			// Record names must match, so the new version is converted under the name of the old one.
+			issues, err := AvroBackwardCompatible(v1, renamedShape(next, "V1"))
+			require.NoError(t, err)
+			got := make([]string, 0, len(issues))
+			for _, i := range issues {
+				got = append(got, i.String())
+			}
+			if tt.want == nil {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func renamedShape(s *BaseShape, name string) *BaseShape {
+	c := *s
+	c.Name = name
+	return &c
+}
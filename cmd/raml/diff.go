@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+const (
+	DiffFormatText = "text"
+	DiffFormatJSON = "json"
+)
+
+type DiffOptions struct {
+	Format string
+}
+
+type DiffCommand struct {
+	Opts    DiffOptions
+	OldPath string
+	NewPath string
+}
+
+func NewDiffCmd(opts DiffOptions, oldPath, newPath string) *DiffCommand {
+	return &DiffCommand{
+		Opts:    opts,
+		OldPath: oldPath,
+		NewPath: newPath,
+	}
+}
+
+func (d DiffCommand) Execute(ctx context.Context) error {
+	if d.Opts.Format != DiffFormatText && d.Opts.Format != DiffFormatJSON {
+		return fmt.Errorf("unsupported output format: %s", d.Opts.Format)
+	}
+	oldFrag, err := parseDiffFragment(ctx, d.OldPath)
+	if err != nil {
+		return err
+	}
+	newFrag, err := parseDiffFragment(ctx, d.NewPath)
+	if err != nil {
+		return err
+	}
+
+	var changes []raml.Change
+	switch oldF := oldFrag.(type) {
+	case *raml.Library:
+		newF, ok := newFrag.(*raml.Library)
+		if !ok {
+			return fmt.Errorf("%s is not a RAML library", d.NewPath)
+		}
+		changes = raml.DiffLibraries(oldF, newF)
+	case *raml.DataType:
+		newF, ok := newFrag.(*raml.DataType)
+		if !ok {
+			return fmt.Errorf("%s is not a RAML data type", d.NewPath)
+		}
+		changes = raml.DiffShapes(oldF.Shape, newF.Shape, "$")
+	default:
+		return fmt.Errorf("%s is not a RAML library or data type", d.OldPath)
+	}
+
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking() {
+			breaking++
+		}
+	}
+	if d.Opts.Format == DiffFormatJSON {
+		err = writeDiffJSON(os.Stdout, changes, breaking > 0)
+	} else {
+		err = writeDiffText(os.Stdout, changes)
+	}
+	if err != nil {
+		return fmt.Errorf("write changes: %w", err)
+	}
+	if breaking > 0 {
+		return fmt.Errorf("%d breaking changes have been found", breaking)
+	}
+	slog.Debug("No breaking changes have been found", slog.Int("changes", len(changes)))
+	return nil
+}
+
+func parseDiffFragment(ctx context.Context, path string) (raml.Fragment, error) {
+	slog.Debug("Parsing RAML...", slog.String("path", path))
+	r, err := raml.ParseFromPathCtx(ctx, path, raml.OptWithUnwrap())
+	if err != nil {
+		return nil, fmt.Errorf("parse raml %s: %w", path, err)
+	}
+	return r.EntryPoint(), nil
+}
+
+func writeDiffJSON(w io.Writer, changes []raml.Change, breaking bool) error {
+	if changes == nil {
+		changes = []raml.Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Breaking bool          `json:"breaking"`
+		Changes  []raml.Change `json:"changes"`
+	}{Breaking: breaking, Changes: changes})
+}
+
+func writeDiffText(w io.Writer, changes []raml.Change) error {
+	for _, c := range changes {
+		severity := "non-breaking"
+		switch {
+		case c.BreaksProducers && c.BreaksConsumers:
+			severity = "breaking"
+		case c.BreaksProducers:
+			severity = "breaking for producers"
+		case c.BreaksConsumers:
+			severity = "breaking for consumers"
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s]\n", c, severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
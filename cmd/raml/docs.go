@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+type DocsOptions struct {
+	Format    string
+	Output    string
+	Templates string
+}
+
+type DocsCommand struct {
+	Opts DocsOptions
+	Arg  string
+}
+
+func NewDocsCmd(opts DocsOptions, arg string) *DocsCommand {
+	return &DocsCommand{
+		Opts: opts,
+		Arg:  arg,
+	}
+}
+
+func (d DocsCommand) Execute(ctx context.Context) error {
+	slog.Debug("Parsing RAML...", slog.String("path", d.Arg))
+	r, err := raml.ParseFromPathCtx(ctx, d.Arg, raml.OptWithUnwrap())
+	if err != nil {
+		return fmt.Errorf("parse raml: %w", err)
+	}
+	lib, ok := r.EntryPoint().(*raml.Library)
+	if !ok {
+		return fmt.Errorf("%s is not a RAML library", d.Arg)
+	}
+
+	opts := []raml.DocsGeneratorOpt{raml.WithDocsFormat(raml.DocsFormat(d.Opts.Format))}
+	if d.Opts.Templates != "" {
+		opts = append(opts, raml.WithDocsTemplates(os.DirFS(d.Opts.Templates), "*.tmpl"))
+	}
+	files, err := raml.NewDocsGenerator(opts...).Generate(lib)
+	if err != nil {
+		return fmt.Errorf("generate docs: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(d.Opts.Output, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+		if err = os.WriteFile(path, files[name], 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	slog.Info("Documentation has been generated", slog.String("output", d.Opts.Output), slog.Int("files", len(files)))
+	return nil
+}
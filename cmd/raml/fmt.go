@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+type FmtOptions struct {
+	Check bool
+}
+
+type FmtCommand struct {
+	Opts  FmtOptions
+	Paths []string
+}
+
+func NewFmtCmd(opts FmtOptions, paths []string) *FmtCommand {
+	return &FmtCommand{
+		Opts:  opts,
+		Paths: paths,
+	}
+}
+
+func (f FmtCommand) Execute(_ context.Context) error {
+	files, err := collectRAMLFiles(f.Paths)
+	if err != nil {
+		return err
+	}
+	unformatted := 0
+	for _, path := range files {
+		data, errRead := os.ReadFile(path)
+		if errRead != nil {
+			return fmt.Errorf("read %s: %w", path, errRead)
+		}
+		out, errFormat := raml.Format(data)
+		if errFormat != nil {
+			return fmt.Errorf("format %s: %w", path, errFormat)
+		}
+		if bytes.Equal(data, out) {
+			continue
+		}
+		unformatted++
+		if f.Opts.Check {
+			fmt.Println(path)
+			continue
+		}
+		if errWrite := os.WriteFile(path, out, 0o600); errWrite != nil {
+			return fmt.Errorf("write %s: %w", path, errWrite)
+		}
+		slog.Debug("File has been formatted", slog.String("path", path))
+	}
+	if f.Opts.Check && unformatted > 0 {
+		return fmt.Errorf("%d files are not formatted", unformatted)
+	}
+	return nil
+}
+
+// collectRAMLFiles returns the files and the *.raml files of the directories. Files of the directories that are
+// not fragments supported by the formatter, such as API definitions, are skipped with a warning.
+func collectRAMLFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, errWalk error) error {
+			if errWalk != nil {
+				return errWalk
+			}
+			if d.IsDir() || filepath.Ext(p) != ".raml" {
+				return nil
+			}
+			head, errHead := readFragmentHead(p)
+			if errHead != nil {
+				return errHead
+			}
+			if _, errKind := raml.IdentifyFragment(head); errKind != nil {
+				slog.Warn("Skipping file", slog.String("path", p), slog.String("reason", errKind.Error()))
+				return nil
+			}
+			files = append(files, p)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walk %s: %w", path, err)
+		}
+	}
+	return files, nil
+}
+
+// readFragmentHead returns the first line of the file.
+func readFragmentHead(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	head, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return strings.TrimRight(head, "\r\n "), nil
+}
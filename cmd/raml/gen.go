@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+type GenGoOptions struct {
+	Package     string
+	Output      string
+	LibraryPath string
+}
+
+type GenGoCommand struct {
+	Opts GenGoOptions
+	Arg  string
+}
+
+func NewGenGoCmd(opts GenGoOptions, arg string) *GenGoCommand {
+	return &GenGoCommand{
+		Opts: opts,
+		Arg:  arg,
+	}
+}
+
+func (g GenGoCommand) Execute(ctx context.Context) error {
+	slog.Debug("Parsing RAML...", slog.String("path", g.Arg))
+	r, err := raml.ParseFromPathCtx(ctx, g.Arg, raml.OptWithUnwrap())
+	if err != nil {
+		return fmt.Errorf("parse raml: %w", err)
+	}
+	lib, ok := r.EntryPoint().(*raml.Library)
+	if !ok {
+		return fmt.Errorf("%s is not a RAML library", g.Arg)
+	}
+	libraryPath := g.Opts.LibraryPath
+	if libraryPath == "" {
+		libraryPath = g.Arg
+	}
+	out, err := raml.NewGoGenerator(
+		raml.WithGoPackage(g.Opts.Package),
+		raml.WithGoLibraryPath(libraryPath),
+	).Generate(lib)
+	if err != nil {
+		return fmt.Errorf("generate go: %w", err)
+	}
+	return writeGenOutput(g.Opts.Output, out)
+}
+
+type GenTSOptions struct {
+	Output string
+}
+
+type GenTSCommand struct {
+	Opts GenTSOptions
+	Arg  string
+}
+
+func NewGenTSCmd(opts GenTSOptions, arg string) *GenTSCommand {
+	return &GenTSCommand{
+		Opts: opts,
+		Arg:  arg,
+	}
+}
+
+func (g GenTSCommand) Execute(ctx context.Context) error {
+	slog.Debug("Parsing RAML...", slog.String("path", g.Arg))
+	r, err := raml.ParseFromPathCtx(ctx, g.Arg, raml.OptWithUnwrap())
+	if err != nil {
+		return fmt.Errorf("parse raml: %w", err)
+	}
+	lib, ok := r.EntryPoint().(*raml.Library)
+	if !ok {
+		return fmt.Errorf("%s is not a RAML library", g.Arg)
+	}
+	out, err := raml.NewTSGenerator().Generate(lib)
+	if err != nil {
+		return fmt.Errorf("generate typescript: %w", err)
+	}
+	return writeGenOutput(g.Opts.Output, out)
+}
+
+// writeGenOutput writes generated code to the file or to stdout if the path is empty or "-".
+func writeGenOutput(path string, out []byte) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	slog.Info("Code has been generated", slog.String("path", path))
+	return nil
+}
+
+type GenProtoOptions struct {
+	Package string
+	Output  string
+}
+
+type GenProtoCommand struct {
+	Opts GenProtoOptions
+	Arg  string
+}
+
+func NewGenProtoCmd(opts GenProtoOptions, arg string) *GenProtoCommand {
+	return &GenProtoCommand{
+		Opts: opts,
+		Arg:  arg,
+	}
+}
+
+func (g GenProtoCommand) Execute(ctx context.Context) error {
+	slog.Debug("Parsing RAML...", slog.String("path", g.Arg))
+	r, err := raml.ParseFromPathCtx(ctx, g.Arg, raml.OptWithUnwrap())
+	if err != nil {
+		return fmt.Errorf("parse raml: %w", err)
+	}
+	lib, ok := r.EntryPoint().(*raml.Library)
+	if !ok {
+		return fmt.Errorf("%s is not a RAML library", g.Arg)
+	}
+	out, diags, err := raml.NewProtoConverter(raml.WithProtoPackage(g.Opts.Package)).Convert(lib)
+	if err != nil {
+		return fmt.Errorf("generate proto: %w", err)
+	}
+	for _, d := range diags {
+		slog.Warn(d.Message, slog.String("path", d.Path),
+			slog.String("position", fmt.Sprintf("%s:%d:%d", d.Location, d.Line, d.Column)))
+	}
+	return writeGenOutput(g.Opts.Output, out)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatJSON    = "json"
+)
+
+type GraphOptions struct {
+	Format string
+	Type   string
+	Depth  int
+}
+
+type GraphCommand struct {
+	Opts GraphOptions
+	Path string
+}
+
+func NewGraphCmd(opts GraphOptions, path string) *GraphCommand {
+	return &GraphCommand{
+		Opts: opts,
+		Path: path,
+	}
+}
+
+func (g GraphCommand) Execute(ctx context.Context) error {
+	var write func(graph *raml.TypeGraph) error
+	switch g.Opts.Format {
+	case GraphFormatDOT:
+		write = func(graph *raml.TypeGraph) error { return graph.WriteDOT(os.Stdout) }
+	case GraphFormatMermaid:
+		write = func(graph *raml.TypeGraph) error { return graph.WriteMermaid(os.Stdout) }
+	case GraphFormatJSON:
+		write = func(graph *raml.TypeGraph) error { return graph.WriteJSON(os.Stdout) }
+	default:
+		return fmt.Errorf("unsupported output format: %s", g.Opts.Format)
+	}
+
+	slog.Debug("Parsing RAML...", slog.String("path", g.Path))
+	r, err := raml.ParseFromPathCtx(ctx, g.Path, raml.OptWithUnwrap())
+	if err != nil {
+		return fmt.Errorf("parse raml %s: %w", g.Path, err)
+	}
+	lib, ok := r.EntryPoint().(*raml.Library)
+	if !ok {
+		return fmt.Errorf("%s is not a RAML library", g.Path)
+	}
+
+	graph := raml.BuildTypeGraph(lib)
+	for _, cycle := range graph.LibraryCycles() {
+		slog.Warn("Cycle between libraries", slog.String("libraries", strings.Join(cycle, ", ")))
+	}
+	if g.Opts.Type != "" {
+		graph, err = graph.Neighborhood(g.Opts.Type, g.Opts.Depth)
+		if err != nil {
+			return fmt.Errorf("neighborhood: %w", err)
+		}
+	}
+	if err = write(graph); err != nil {
+		return fmt.Errorf("write graph: %w", err)
+	}
+	return nil
+}
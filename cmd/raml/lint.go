@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+const (
+	LintFormatText = "text"
+	LintFormatJSON = "json"
+)
+
+type LintOptions struct {
+	Config string
+	Format string
+}
+
+type LintCommand struct {
+	Opts  LintOptions
+	Paths []string
+}
+
+func NewLintCmd(opts LintOptions, paths []string) *LintCommand {
+	return &LintCommand{
+		Opts:  opts,
+		Paths: paths,
+	}
+}
+
+func (l LintCommand) Execute(ctx context.Context) error {
+	if l.Opts.Format != LintFormatText && l.Opts.Format != LintFormatJSON {
+		return fmt.Errorf("unsupported output format: %s", l.Opts.Format)
+	}
+	var lintOpts []raml.LintOpt
+	if l.Opts.Config != "" {
+		data, err := os.ReadFile(l.Opts.Config)
+		if err != nil {
+			return fmt.Errorf("read lint config: %w", err)
+		}
+		config, err := raml.ParseLintConfig(data)
+		if err != nil {
+			return fmt.Errorf("parse lint config %s: %w", l.Opts.Config, err)
+		}
+		lintOpts = append(lintOpts, raml.OptLintConfig(config))
+	}
+	linter := raml.NewLinter(lintOpts...)
+
+	diagnostics := []raml.LintDiagnostic{}
+	for _, path := range l.Paths {
+		slog.Debug("Parsing RAML...", slog.String("path", path))
+		r, err := raml.ParseFromPathCtx(ctx, path, raml.OptWithUnwrap())
+		if err != nil {
+			return fmt.Errorf("parse raml %s: %w", path, err)
+		}
+		lib, ok := r.EntryPoint().(*raml.Library)
+		if !ok {
+			return fmt.Errorf("%s is not a RAML library", path)
+		}
+		diagnostics = append(diagnostics, linter.Lint(lib)...)
+	}
+
+	errs := 0
+	for _, d := range diagnostics {
+		if d.Severity == raml.LintSeverityError {
+			errs++
+		}
+	}
+	var err error
+	if l.Opts.Format == LintFormatJSON {
+		err = writeLintJSON(os.Stdout, diagnostics)
+	} else {
+		err = writeLintText(os.Stdout, diagnostics)
+	}
+	if err != nil {
+		return fmt.Errorf("write diagnostics: %w", err)
+	}
+	if errs > 0 {
+		return fmt.Errorf("%d lint errors have been found", errs)
+	}
+	slog.Debug("No lint errors have been found", slog.Int("diagnostics", len(diagnostics)))
+	return nil
+}
+
+func writeLintJSON(w io.Writer, diagnostics []raml.LintDiagnostic) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diagnostics)
+}
+
+func writeLintText(w io.Writer, diagnostics []raml.LintDiagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
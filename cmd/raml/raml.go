@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"time"
 
+	"github.com/acronis/go-raml/v2"
 	"github.com/acronis/go-stacktrace"
 	"github.com/acronis/go-stacktrace/slogex"
 	"github.com/spf13/cobra"
@@ -68,6 +70,172 @@ func mainFn() int {
 				return InitLoggingAndRun(ctx, verbosity, NewValidateCmd(opts, args))
 			},
 		}
+		cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false,
+			"re-validate files when any file they use or include changes")
+		cmd.Flags().BoolVar(&opts.Poll, "poll", false, "poll files instead of using filesystem notifications")
+		cmd.Flags().DurationVar(&opts.PollInterval, "poll-interval", time.Second, "interval of polling files")
+
+		return cmd
+	}()
+
+	cmdValidateData := func() *cobra.Command {
+		opts := ValidateDataOptions{}
+		cmd := &cobra.Command{
+			Use:   "validate-data --type <file.raml#Type> <data>...",
+			Short: "validate json, yaml and ndjson documents against a raml type, fails on violations",
+			Args:  cobra.MinimumNArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewValidateDataCmd(opts, args))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Type, "type", "t", "",
+			"type to validate against, e.g. library.raml#Order or datatype.raml for data type fragments")
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", ValidateDataFormatText, "output format: text or json")
+		_ = cmd.MarkFlagRequired("type")
+
+		return cmd
+	}()
+
+	cmdGen := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:   "gen",
+			Short: "generate code from raml types",
+		}
+
+		var goOpts GenGoOptions
+		cmdGo := &cobra.Command{
+			Use:   "go <library.raml>",
+			Short: "generate go types from a raml library",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewGenGoCmd(goOpts, args[0]))
+			},
+		}
+		cmdGo.Flags().StringVarP(&goOpts.Package, "package", "p", raml.DefaultGoPackage, "package name of generated code")
+		cmdGo.Flags().StringVarP(&goOpts.Output, "output", "o", "", "output file, stdout by default")
+		cmdGo.Flags().StringVar(&goOpts.LibraryPath, "library-path", "",
+			"path of the raml library parsed by generated Validate methods, the input path by default")
+
+		var tsOpts GenTSOptions
+		cmdTS := &cobra.Command{
+			Use:   "ts <library.raml>",
+			Short: "generate typescript type definitions from a raml library",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewGenTSCmd(tsOpts, args[0]))
+			},
+		}
+		cmdTS.Flags().StringVarP(&tsOpts.Output, "output", "o", "", "output .ts or .d.ts file, stdout by default")
+
+		var protoOpts GenProtoOptions
+		cmdProto := &cobra.Command{
+			Use:   "proto <library.raml>",
+			Short: "generate a proto3 schema from a raml library",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewGenProtoCmd(protoOpts, args[0]))
+			},
+		}
+		cmdProto.Flags().StringVarP(&protoOpts.Package, "package", "p", "", "package of the proto file")
+		cmdProto.Flags().StringVarP(&protoOpts.Output, "output", "o", "", "output .proto file, stdout by default")
+
+		cmd.AddCommand(cmdGo, cmdTS, cmdProto)
+		return cmd
+	}()
+
+	cmdDiff := func() *cobra.Command {
+		opts := DiffOptions{}
+		cmd := &cobra.Command{
+			Use:   "diff <old.raml> <new.raml>",
+			Short: "report changes between two versions of raml types, fails on breaking changes",
+			Args:  cobra.ExactArgs(2),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewDiffCmd(opts, args[0], args[1]))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", DiffFormatText, "output format: text or json")
+
+		return cmd
+	}()
+
+	cmdLint := func() *cobra.Command {
+		opts := LintOptions{}
+		cmd := &cobra.Command{
+			Use:   "lint <file.raml>...",
+			Short: "check raml libraries against style rules, fails on error diagnostics",
+			Args:  cobra.MinimumNArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewLintCmd(opts, args))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "path to the lint configuration file")
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", LintFormatText, "output format: text or json")
+
+		return cmd
+	}()
+
+	cmdUnused := func() *cobra.Command {
+		opts := UnusedOptions{}
+		cmd := &cobra.Command{
+			Use:   "unused <entrypoint.raml>...",
+			Short: "report types and uses unreachable from entrypoints, fails if any are found",
+			Args:  cobra.MinimumNArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewUnusedCmd(opts, args))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", UnusedFormatText, "output format: text or json")
+
+		return cmd
+	}()
+
+	cmdGraph := func() *cobra.Command {
+		opts := GraphOptions{}
+		cmd := &cobra.Command{
+			Use:   "graph <file.raml>",
+			Short: "export the graph of type relationships as graphviz dot, mermaid or json",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewGraphCmd(opts, args[0]))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", GraphFormatDOT, "output format: dot, mermaid or json")
+		cmd.Flags().StringVarP(&opts.Type, "type", "t", "", "limit the graph to the neighborhood of the type")
+		cmd.Flags().IntVarP(&opts.Depth, "depth", "d", 1, "number of relationships from the type to include")
+
+		return cmd
+	}()
+
+	cmdDocs := func() *cobra.Command {
+		opts := DocsOptions{}
+		cmd := &cobra.Command{
+			Use:   "docs <file.raml>",
+			Short: "render documentation of a raml library as a static html site or markdown files",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewDocsCmd(opts, args[0]))
+			},
+		}
+		cmd.Flags().StringVarP(&opts.Format, "format", "f", string(raml.DocsFormatHTML), "output format: html or markdown")
+		cmd.Flags().StringVarP(&opts.Output, "output", "o", "docs", "output directory")
+		cmd.Flags().StringVarP(&opts.Templates, "templates", "t", "",
+			"directory with *.tmpl files overriding the default templates")
+
+		return cmd
+	}()
+
+	cmdFmt := func() *cobra.Command {
+		opts := FmtOptions{}
+		cmd := &cobra.Command{
+			Use:   "fmt <file.raml|dir>...",
+			Short: "rewrite raml files into the canonical layout",
+			Args:  cobra.MinimumNArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				return InitLoggingAndRun(ctx, verbosity, NewFmtCmd(opts, args))
+			},
+		}
+		cmd.Flags().BoolVarP(&opts.Check, "check", "c", false,
+			"list files that are not formatted and fail instead of rewriting them")
 
 		return cmd
 	}()
@@ -89,6 +257,14 @@ func mainFn() int {
 
 		cmd.AddCommand(
 			cmdValidate,
+			cmdValidateData,
+			cmdGen,
+			cmdDiff,
+			cmdLint,
+			cmdUnused,
+			cmdGraph,
+			cmdDocs,
+			cmdFmt,
 		)
 		return cmd
 	}()
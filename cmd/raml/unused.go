@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/acronis/go-raml/v2"
+)
+
+const (
+	UnusedFormatText = "text"
+	UnusedFormatJSON = "json"
+)
+
+type UnusedOptions struct {
+	Format string
+}
+
+type UnusedCommand struct {
+	Opts  UnusedOptions
+	Paths []string
+}
+
+func NewUnusedCmd(opts UnusedOptions, paths []string) *UnusedCommand {
+	return &UnusedCommand{
+		Opts:  opts,
+		Paths: paths,
+	}
+}
+
+func (u UnusedCommand) Execute(ctx context.Context) error {
+	if u.Opts.Format != UnusedFormatText && u.Opts.Format != UnusedFormatJSON {
+		return fmt.Errorf("unsupported output format: %s", u.Opts.Format)
+	}
+	entrypoints := make([]*raml.Library, 0, len(u.Paths))
+	for _, path := range u.Paths {
+		slog.Debug("Parsing RAML...", slog.String("path", path))
+		r, err := raml.ParseFromPathCtx(ctx, path)
+		if err != nil {
+			return fmt.Errorf("parse raml %s: %w", path, err)
+		}
+		lib, ok := r.EntryPoint().(*raml.Library)
+		if !ok {
+			return fmt.Errorf("%s is not a RAML library", path)
+		}
+		entrypoints = append(entrypoints, lib)
+	}
+
+	report := raml.FindUnused(entrypoints...)
+	var err error
+	if u.Opts.Format == UnusedFormatJSON {
+		err = writeUnusedJSON(os.Stdout, report)
+	} else {
+		err = writeUnusedText(os.Stdout, report)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	if !report.Empty() {
+		return fmt.Errorf("%d unused types and %d unused uses have been found", len(report.Types), len(report.Uses))
+	}
+	slog.Debug("No unused types and uses have been found")
+	return nil
+}
+
+func writeUnusedJSON(w io.Writer, report *raml.UnusedReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeUnusedText(w io.Writer, report *raml.UnusedReport) error {
+	for _, t := range report.Types {
+		kind := "type"
+		if t.AnnotationType {
+			kind = "annotation type"
+		}
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: unused %s %q\n", t.Location, t.Line, t.Column, kind, t.Name); err != nil {
+			return err
+		}
+	}
+	for _, u := range report.Uses {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: unused uses alias %q of %s\n",
+			u.Location, u.Line, u.Column, u.Alias, u.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/acronis/go-raml/v2"
 	"github.com/acronis/go-stacktrace"
@@ -12,6 +16,11 @@ import (
 
 type ValidateOptions struct {
 	EnsureDuplicates bool
+	// Watch re-validates files when any file of their dependency closure changes.
+	Watch bool
+	// Poll forces polling instead of filesystem notifications in the watch mode.
+	Poll         bool
+	PollInterval time.Duration
 }
 
 type ValidateCommand struct {
@@ -27,16 +36,15 @@ func NewValidateCmd(opts ValidateOptions, args []string) *ValidateCommand {
 }
 
 func (v ValidateCommand) Execute(ctx context.Context) error {
-	var err error
-	var stOpts []stacktrace.TracesOpt
-	if v.Opts.EnsureDuplicates {
-		stOpts = append(stOpts, stacktrace.WithEnsureDuplicates())
+	if v.Opts.Watch {
+		return v.watch(ctx)
 	}
+	var err error
 	for _, arg := range v.Args {
 		slog.Info("Validating RAML...", slog.String("path", arg))
 		_, err = raml.ParseFromPathCtx(ctx, arg, raml.OptWithUnwrap(), raml.OptWithValidate())
 		if err != nil {
-			slog.Error("RAML is invalid", slogex.ErrToSlogAttr(err, stOpts...))
+			slog.Error("RAML is invalid", slogex.ErrToSlogAttr(err, v.tracesOpts()...))
 		} else {
 			slog.Info("RAML is valid", slog.String("path", arg))
 		}
@@ -46,3 +54,138 @@ func (v ValidateCommand) Execute(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (v ValidateCommand) tracesOpts() []stacktrace.TracesOpt {
+	var stOpts []stacktrace.TracesOpt
+	if v.Opts.EnsureDuplicates {
+		stOpts = append(stOpts, stacktrace.WithEnsureDuplicates())
+	}
+	return stOpts
+}
+
+// validationState is the result of the last validation of a file in the watch mode.
+type validationState struct {
+	// files is the dependency closure of the file.
+	files map[string]struct{}
+	// errors is the set of reported errors.
+	errors map[string]struct{}
+}
+
+// watch validates the files and re-validates a file every time a file of its dependency closure changes.
+// Only new and resolved errors are reported. The command runs until the context is canceled.
+func (v ValidateCommand) watch(ctx context.Context) error {
+	watcher := newFileWatcher(v.Opts.Poll, v.Opts.PollInterval)
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			slog.Warn("Failed to close the watcher", slog.String("error", err.Error()))
+		}
+	}()
+
+	states := make([]validationState, len(v.Args))
+	for i, arg := range v.Args {
+		states[i] = v.validate(ctx, arg, validationState{})
+	}
+	for {
+		files := make(map[string]struct{})
+		for _, state := range states {
+			for path := range state.files {
+				files[path] = struct{}{}
+			}
+		}
+		slog.Info("Watching for changes...", slog.Int("files", len(files)))
+		changed, err := watcher.Wait(ctx, sortedSet(files))
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return fmt.Errorf("watch files: %w", err)
+		}
+		for i, arg := range v.Args {
+			// Files that do not depend on the changed files are not parsed again.
+			if dependsOn(states[i], changed) {
+				states[i] = v.validate(ctx, arg, states[i])
+			}
+		}
+	}
+}
+
+func dependsOn(state validationState, changed []string) bool {
+	for _, path := range changed {
+		if _, ok := state.files[path]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validate parses and validates the file and reports errors that are not in the previous state
+// and errors of the previous state that are resolved.
+func (v ValidateCommand) validate(ctx context.Context, arg string, prev validationState) validationState {
+	slog.Info("Validating RAML...", slog.String("path", arg))
+	state := validationState{files: make(map[string]struct{}), errors: make(map[string]struct{})}
+	if abs, err := filepath.Abs(arg); err == nil {
+		state.files[abs] = struct{}{}
+	}
+	r, err := raml.ParseFromPathCtx(ctx, arg, raml.OptWithUnwrap(), raml.OptWithValidate())
+	if r != nil {
+		for _, path := range r.GetFiles() {
+			state.files[path] = struct{}{}
+		}
+	}
+	if err != nil {
+		for _, msg := range errorMessages(err, v.tracesOpts()) {
+			state.errors[msg] = struct{}{}
+		}
+	}
+
+	for _, msg := range sortedSet(prev.errors) {
+		if _, ok := state.errors[msg]; !ok {
+			slog.Info("Resolved error", slog.String("path", arg), slog.String("error", msg))
+		}
+	}
+	for _, msg := range sortedSet(state.errors) {
+		if _, ok := prev.errors[msg]; !ok {
+			slog.Error("New error", slog.String("path", arg), slog.String("error", msg))
+		}
+	}
+	if len(state.errors) == 0 {
+		slog.Info("RAML is valid", slog.String("path", arg))
+	} else {
+		slog.Error("RAML is invalid", slog.String("path", arg), slog.Int("errors", len(state.errors)))
+	}
+	return state
+}
+
+// errorMessages returns a message for every trace of the error. The message contains the position and the message
+// of the innermost error of the trace.
+func errorMessages(err error, stOpts []stacktrace.TracesOpt) []string {
+	st, ok := stacktrace.Unwrap(err)
+	if !ok {
+		return []string{err.Error()}
+	}
+	var messages []string
+	for _, trace := range st.GetTraces(stOpts...) {
+		if len(trace.Stack) == 0 {
+			continue
+		}
+		last := trace.Stack[len(trace.Stack)-1]
+		if last.LinePos != nil {
+			messages = append(messages, *last.LinePos+": "+last.Message)
+		} else {
+			messages = append(messages, last.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return []string{err.Error()}
+	}
+	return messages
+}
+
+func sortedSet(set map[string]struct{}) []string {
+	res := make([]string, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/acronis/go-raml/v2"
+	"github.com/acronis/go-stacktrace"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ValidateDataFormatText = "text"
+	ValidateDataFormatJSON = "json"
+)
+
+type ValidateDataOptions struct {
+	// Type is the type to validate data against, e.g. "library.raml#Order". Types of libraries used by
+	// the library are referenced by alias, e.g. "library.raml#common.Id". The type name is omitted for
+	// data type fragments.
+	Type   string
+	Format string
+}
+
+type ValidateDataCommand struct {
+	Opts ValidateDataOptions
+	// Args are paths or glob patterns of data files.
+	Args []string
+}
+
+func NewValidateDataCmd(opts ValidateDataOptions, args []string) *ValidateDataCommand {
+	return &ValidateDataCommand{
+		Opts: opts,
+		Args: args,
+	}
+}
+
+// DataViolation describes a value of a data file that does not conform to the type.
+type DataViolation struct {
+	File string `json:"file"`
+	// Record is the zero-based index of the document in NDJSON and multi-document YAML files.
+	Record int `json:"record"`
+	// Line and Column are zero if the position is unknown, e.g. if the file cannot be read.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Path is the JSON path of the value, e.g. "$.items[2].name".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (d DataViolation) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Path, d.Message)
+}
+
+func (v ValidateDataCommand) Execute(ctx context.Context) error {
+	if v.Opts.Format != ValidateDataFormatText && v.Opts.Format != ValidateDataFormatJSON {
+		return fmt.Errorf("unsupported output format: %s", v.Opts.Format)
+	}
+	shape, err := loadDataType(ctx, v.Opts.Type)
+	if err != nil {
+		return err
+	}
+	paths, err := expandDataPaths(v.Args)
+	if err != nil {
+		return err
+	}
+
+	violations := []DataViolation{}
+	for _, path := range paths {
+		slog.Debug("Validating data...", slog.String("path", path))
+		violations = append(violations, validateDataFile(shape, path)...)
+	}
+	if v.Opts.Format == ValidateDataFormatJSON {
+		err = writeDataViolationsJSON(os.Stdout, violations)
+	} else {
+		err = writeDataViolationsText(os.Stdout, violations)
+	}
+	if err != nil {
+		return fmt.Errorf("write violations: %w", err)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d violations have been found", len(violations))
+	}
+	slog.Info("Data is valid", slog.String("type", v.Opts.Type), slog.Int("files", len(paths)))
+	return nil
+}
+
+// loadDataType parses the RAML file and returns the unwrapped type referenced as "file.raml#TypeName".
+func loadDataType(ctx context.Context, ref string) (*raml.BaseShape, error) {
+	path, name, _ := strings.Cut(ref, "#")
+	r, err := raml.ParseFromPathCtx(ctx, path, raml.OptWithUnwrap(), raml.OptWithValidate())
+	if err != nil {
+		return nil, fmt.Errorf("parse raml %s: %w", path, err)
+	}
+	switch frag := r.EntryPoint().(type) {
+	case *raml.DataType:
+		if name != "" {
+			return nil, fmt.Errorf("%s is a data type fragment, type name must be omitted", path)
+		}
+		return frag.Shape, nil
+	case *raml.Library:
+		if name == "" {
+			return nil, fmt.Errorf("type name is required, e.g. %s#TypeName", path)
+		}
+		lib := frag
+		typeName := name
+		if alias, rest, ok := strings.Cut(name, "."); ok {
+			var link *raml.LibraryLink
+			if lib.Uses != nil {
+				link, _ = lib.Uses.Get(alias)
+			}
+			if link == nil || link.Link == nil {
+				return nil, fmt.Errorf("library %s is not used in %s", alias, path)
+			}
+			lib, typeName = link.Link, rest
+		}
+		var shape *raml.BaseShape
+		if lib.Types != nil {
+			shape, _ = lib.Types.Get(typeName)
+		}
+		if shape == nil {
+			return nil, fmt.Errorf("type %s is not found in %s", name, path)
+		}
+		return shape, nil
+	default:
+		return nil, fmt.Errorf("%s is neither a RAML library nor a data type fragment", path)
+	}
+}
+
+// expandDataPaths expands glob patterns. Arguments without glob meta characters are kept as is, so that
+// missing files are reported.
+func expandDataPaths(args []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]struct{})
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("expand %s: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			if strings.ContainsAny(arg, "*?[") {
+				return nil, fmt.Errorf("no files match %s", arg)
+			}
+			matches = []string{arg}
+		}
+		for _, path := range matches {
+			if _, ok := seen[path]; !ok {
+				seen[path] = struct{}{}
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// dataDocument is a document of a data file. The value is validated in the JSON encoding, the node is used to
+// find positions of violations.
+type dataDocument struct {
+	value []byte
+	// node is nil if the document cannot be parsed as YAML.
+	node *yaml.Node
+	// lineOffset is the number of lines in the file before the node.
+	lineOffset int
+}
+
+// position returns the line and column of the value at the JSON path.
+func (d dataDocument) position(path string) (int, int) {
+	node := findDataNode(d.node, path)
+	if node == nil || node.Line == 0 {
+		return d.lineOffset + 1, 1
+	}
+	return d.lineOffset + node.Line, node.Column
+}
+
+// readDataDocuments reads documents of the data file. The format is chosen by the extension: ".json" for JSON,
+// ".ndjson" and ".jsonl" for newline-delimited JSON, YAML otherwise.
+func readDataDocuments(path string) ([]dataDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return []dataDocument{{value: data, node: parseDataNode(data)}}, nil
+	case ".ndjson", ".jsonl":
+		var docs []dataDocument
+		for i, line := range bytes.Split(data, []byte("\n")) {
+			line = bytes.TrimRight(line, "\r")
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			docs = append(docs, dataDocument{value: line, node: parseDataNode(line), lineOffset: i})
+		}
+		return docs, nil
+	}
+
+	var docs []dataDocument
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		node := &yaml.Node{}
+		if err = dec.Decode(node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode document %d: %w", len(docs), err)
+		}
+		var v any
+		if err = node.Decode(&v); err != nil {
+			return nil, fmt.Errorf("decode document %d: %w", len(docs), err)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("convert document %d to JSON: %w", len(docs), err)
+		}
+		docs = append(docs, dataDocument{value: value, node: node})
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents found")
+	}
+	return docs, nil
+}
+
+// parseDataNode parses JSON as YAML to get positions of values. It returns nil if the data cannot be parsed.
+func parseDataNode(data []byte) *yaml.Node {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil
+	}
+	return node
+}
+
+// findDataNode returns the node of the value at the JSON path, e.g. "$.items[2].name". If the value is not
+// found, the closest found ancestor is returned.
+func findDataNode(node *yaml.Node, path string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		for node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+		var child *yaml.Node
+		child, rest = findDataChild(node, rest)
+		if child == nil {
+			break
+		}
+		node = child
+	}
+	return node
+}
+
+// findDataChild returns the child node referenced by the first segment of the path and the rest of the path.
+func findDataChild(node *yaml.Node, path string) (*yaml.Node, string) {
+	switch {
+	case strings.HasPrefix(path, "[") && node.Kind == yaml.SequenceNode:
+		end := strings.IndexByte(path, ']')
+		if end < 0 {
+			return nil, path
+		}
+		i, err := strconv.Atoi(path[1:end])
+		if err != nil || i < 0 || i >= len(node.Content) {
+			return nil, path
+		}
+		return node.Content[i], path[end+1:]
+	case strings.HasPrefix(path, ".") && node.Kind == yaml.MappingNode:
+		// NOTE: Keys may contain dots and brackets, so the longest key followed by a segment separator wins.
+		name := path[1:]
+		var child *yaml.Node
+		keyLen := -1
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if len(key) <= keyLen || !strings.HasPrefix(name, key) {
+				continue
+			}
+			if tail := name[len(key):]; tail != "" && tail[0] != '.' && tail[0] != '[' {
+				continue
+			}
+			child, keyLen = node.Content[i+1], len(key)
+		}
+		if child == nil {
+			return nil, path
+		}
+		return child, name[keyLen:]
+	}
+	return nil, path
+}
+
+// validateDataFile validates all documents of the data file and returns violations sorted by position.
+func validateDataFile(shape *raml.BaseShape, path string) []DataViolation {
+	docs, err := readDataDocuments(path)
+	if err != nil {
+		return []DataViolation{{File: path, Message: err.Error()}}
+	}
+	var violations []DataViolation
+	for i, doc := range docs {
+		err = shape.ValidateStream(bytes.NewReader(doc.value))
+		if err == nil {
+			continue
+		}
+		var verr *raml.StreamValidationError
+		if !errors.As(err, &verr) {
+			violations = append(violations, DataViolation{
+				File: path, Record: i, Line: doc.lineOffset + 1, Column: 1, Path: "$", Message: err.Error(),
+			})
+			continue
+		}
+		for _, se := range verr.Errors {
+			line, column := doc.position(se.Path)
+			violations = append(violations, DataViolation{
+				File: path, Record: i, Line: line, Column: column, Path: se.Path, Message: dataViolationMessage(se.Err),
+			})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Line != violations[j].Line {
+			return violations[i].Line < violations[j].Line
+		}
+		return violations[i].Column < violations[j].Column
+	})
+	return violations
+}
+
+// dataViolationMessage returns the message of the violation. Violations of unions are stack traces with an entry
+// per union member, the message lists the errors of the members.
+func dataViolationMessage(err error) string {
+	st, ok := stacktrace.Unwrap(err)
+	if !ok {
+		return err.Error()
+	}
+	return stackTraceMessage(st)
+}
+
+func stackTraceMessage(st *stacktrace.StackTrace) string {
+	messages := make([]string, 0, len(st.List))
+	for _, member := range st.List {
+		switch {
+		case member.Wrapped != nil:
+			messages = append(messages, stackTraceMessage(member.Wrapped))
+		case member.Err != nil:
+			messages = append(messages, member.Err.Error())
+		default:
+			messages = append(messages, member.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return st.Message
+	}
+	return st.Message + ": " + strings.Join(messages, "; ")
+}
+
+func writeDataViolationsJSON(w io.Writer, violations []DataViolation) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(violations)
+}
+
+func writeDataViolationsText(w io.Writer, violations []DataViolation) error {
+	for _, d := range violations {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDataNode(t *testing.T) {
+	doc := `{
+  "items": [
+    {"name": "a"},
+    {"name": "b", "tags": ["x", "y"]}
+  ],
+  "a.b": {"c": 1},
+  "a": {"b": 2},
+  "x[0]": 3
+}`
+	tests := []struct {
+		path         string
+		line, column int
+	}{
+		{path: "$", line: 1, column: 1},
+		{path: "$.items", line: 2, column: 12},
+		{path: "$.items[1].name", line: 4, column: 14},
+		{path: "$.items[1].tags[1]", line: 4, column: 33},
+		{path: "$.a.b.c", line: 6, column: 16},
+		{path: "$.a.b", line: 6, column: 10},
+		{path: "$.x[0]", line: 8, column: 11},
+		// The closest found ancestor is returned for missing values.
+		{path: "$.items[5].name", line: 2, column: 12},
+		{path: "$.items[1].missing", line: 4, column: 5},
+	}
+	node := parseDataNode([]byte(doc))
+	require.NotNil(t, node)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			found := findDataNode(node, tt.path)
+			require.NotNil(t, found)
+			require.Equal(t, tt.line, found.Line)
+			require.Equal(t, tt.column, found.Column)
+		})
+	}
+
+	require.Nil(t, findDataNode(nil, "$.a"))
+	require.Nil(t, parseDataNode([]byte("{")))
+}
+
+func TestFindDataChild(t *testing.T) {
+	node := parseDataNode([]byte("list: [1, 2]\nmap: {k: v}\n")).Content[0]
+	list := findDataNode(node, "$.list")
+	mapping := findDataNode(node, "$.map")
+
+	tests := []struct {
+		name     string
+		path     string
+		isList   bool
+		found    bool
+		wantRest string
+	}{
+		{name: "index", path: "[1].x", isList: true, found: true, wantRest: ".x"},
+		{name: "index out of range", path: "[2]", isList: true, wantRest: "[2]"},
+		{name: "negative index", path: "[-1]", isList: true, wantRest: "[-1]"},
+		{name: "unterminated index", path: "[1", isList: true, wantRest: "[1"},
+		{name: "key of a sequence", path: ".k", isList: true, wantRest: ".k"},
+		{name: "key", path: ".k[0]", found: true, wantRest: "[0]"},
+		{name: "key prefix", path: ".kk", wantRest: ".kk"},
+		{name: "index of a mapping", path: "[0]", wantRest: "[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := mapping
+			if tt.isList {
+				parent = list
+			}
+			child, rest := findDataChild(parent, tt.path)
+			require.Equal(t, tt.found, child != nil)
+			require.Equal(t, tt.wantRest, rest)
+		})
+	}
+}
+
+func TestReadDataDocuments(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("ndjson", func(t *testing.T) {
+		path := writeTestFile(t, dir, "data.ndjson", "{\"a\": 1}\r\n\n{\"a\": 2,\n{\"b\": {\"c\": 3}}\n")
+		docs, err := readDataDocuments(path)
+		require.NoError(t, err)
+		require.Len(t, docs, 3)
+		require.Equal(t, `{"a": 1}`, string(docs[0].value))
+		require.Equal(t, 0, docs[0].lineOffset)
+		require.Equal(t, 2, docs[1].lineOffset)
+		// Invalid lines are kept, so that they are reported.
+		require.Nil(t, docs[1].node)
+		line, column := docs[1].position("$.a")
+		require.Equal(t, []int{3, 1}, []int{line, column})
+		line, column = docs[2].position("$.b.c")
+		require.Equal(t, []int{4, 13}, []int{line, column})
+	})
+
+	t.Run("multi-document yaml", func(t *testing.T) {
+		path := writeTestFile(t, dir, "data.yaml", "a: 1\n---\n# comment\nb:\n  c: [1, 2]\n")
+		docs, err := readDataDocuments(path)
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		require.JSONEq(t, `{"a": 1}`, string(docs[0].value))
+		require.JSONEq(t, `{"b": {"c": [1, 2]}}`, string(docs[1].value))
+		line, column := docs[1].position("$.b.c[1]")
+		require.Equal(t, []int{5, 10}, []int{line, column})
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := writeTestFile(t, dir, "data.json", "[\n  1,\n  \"x\"\n]")
+		docs, err := readDataDocuments(path)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		line, column := docs[0].position("$[1]")
+		require.Equal(t, []int{3, 3}, []int{line, column})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := readDataDocuments(filepath.Join(dir, "missing.json"))
+		require.ErrorContains(t, err, "read file")
+		_, err = readDataDocuments(writeTestFile(t, dir, "empty.yaml", ""))
+		require.ErrorContains(t, err, "no documents found")
+		_, err = readDataDocuments(writeTestFile(t, dir, "invalid.yaml", "a: 1\n---\na: [\n"))
+		require.ErrorContains(t, err, "decode document 1")
+	})
+}
+
+func TestExpandDataPaths(t *testing.T) {
+	dir := t.TempDir()
+	a := writeTestFile(t, dir, "a.json", "{}")
+	b := writeTestFile(t, dir, "b.json", "{}")
+	writeTestFile(t, dir, "c.yaml", "{}")
+	missing := filepath.Join(dir, "missing.json")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "glob", args: []string{filepath.Join(dir, "*.json")}, want: []string{a, b}},
+		{name: "duplicates", args: []string{b, filepath.Join(dir, "*.json")}, want: []string{b, a}},
+		{name: "missing file is kept", args: []string{missing}, want: []string{missing}},
+		{name: "no matches", args: []string{filepath.Join(dir, "*.ndjson")}, wantErr: "no files match"},
+		{name: "bad pattern", args: []string{filepath.Join(dir, "[")}, wantErr: "syntax error in pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandDataPaths(tt.args)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// watchDebounce is the time to wait for more changes after the first one, since editors often save files in
+// several steps.
+const watchDebounce = 100 * time.Millisecond
+
+// fileWatcher waits for changes of files.
+type fileWatcher interface {
+	// Wait blocks until any of the files is changed, created or removed and returns the changed files.
+	// Changes made between calls are not lost.
+	Wait(ctx context.Context, files []string) ([]string, error)
+	Close() error
+}
+
+// newFileWatcher returns a watcher based on filesystem notifications, falling back to polling if notifications
+// are unavailable or polling is requested.
+func newFileWatcher(poll bool, interval time.Duration) fileWatcher {
+	if !poll {
+		w, err := newNotifyWatcher()
+		if err == nil {
+			return w
+		}
+		slog.Warn("Filesystem notifications are unavailable, falling back to polling",
+			slog.String("error", err.Error()))
+	}
+	return newPollWatcher(interval)
+}
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// pollWatcher detects changes by comparing sizes and modification times of files periodically.
+type pollWatcher struct {
+	interval time.Duration
+	states   map[string]fileState
+}
+
+func newPollWatcher(interval time.Duration) *pollWatcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &pollWatcher{interval: interval, states: make(map[string]fileState)}
+}
+
+func (w *pollWatcher) Wait(ctx context.Context, files []string) ([]string, error) {
+	for _, path := range files {
+		if _, ok := w.states[path]; !ok {
+			w.states[path] = statFile(path)
+		}
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		var changed []string
+		for _, path := range files {
+			state := statFile(path)
+			if state != w.states[path] {
+				w.states[path] = state
+				changed = append(changed, path)
+			}
+		}
+		if len(changed) > 0 {
+			return changed, nil
+		}
+	}
+}
+
+func (w *pollWatcher) Close() error {
+	return nil
+}
@@ -0,0 +1,136 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_TO |
+	syscall.IN_MOVED_FROM | syscall.IN_ATTRIB
+
+// notifyWatcher watches directories of files with inotify, so that files replaced by editors on save and files
+// created later are noticed too.
+type notifyWatcher struct {
+	fd   int
+	file *os.File
+
+	mu      sync.Mutex
+	dirs    map[int32]string
+	watches map[string]int32
+
+	events chan string
+	errs   chan error
+}
+
+func newNotifyWatcher() (fileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("init inotify: %w", err)
+	}
+	w := &notifyWatcher{
+		// NOTE: The non-blocking descriptor is handled by the runtime poller, so Close interrupts Read.
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		dirs:    make(map[int32]string),
+		watches: make(map[string]int32),
+		events:  make(chan string, 64),
+		errs:    make(chan error, 1),
+	}
+	go w.read()
+	return w, nil
+}
+
+// read decodes inotify events and sends paths of changed files.
+func (w *notifyWatcher) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			w.errs <- fmt.Errorf("read inotify events: %w", err)
+			close(w.events)
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			start := offset + syscall.SizeofInotifyEvent
+			offset = start + nameLen
+			if offset > n {
+				break
+			}
+			name := string(bytes.TrimRight(buf[start:offset], "\x00"))
+			w.mu.Lock()
+			dir, ok := w.dirs[wd]
+			w.mu.Unlock()
+			if ok && name != "" {
+				w.events <- filepath.Join(dir, name)
+			}
+		}
+	}
+}
+
+func (w *notifyWatcher) watch(dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.watches[dir]; ok {
+		return nil
+	}
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, inotifyMask)
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", dir, err)
+	}
+	w.watches[dir] = int32(wd) //nolint:gosec // watch descriptors are small positive numbers
+	w.dirs[int32(wd)] = dir    //nolint:gosec // watch descriptors are small positive numbers
+	return nil
+}
+
+func (w *notifyWatcher) Wait(ctx context.Context, files []string) ([]string, error) {
+	wanted := make(map[string]struct{}, len(files))
+	for _, path := range files {
+		wanted[path] = struct{}{}
+		if err := w.watch(filepath.Dir(path)); err != nil {
+			slog.Debug("Skipping directory", slog.String("error", err.Error()))
+		}
+	}
+	changed := make(map[string]struct{})
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-w.errs:
+			return nil, err
+		case path, ok := <-w.events:
+			if !ok {
+				return nil, fmt.Errorf("inotify watcher is closed")
+			}
+			if _, ok = wanted[path]; !ok {
+				continue
+			}
+			changed[path] = struct{}{}
+			if debounce == nil {
+				debounce = time.After(watchDebounce)
+			}
+		case <-debounce:
+			res := make([]string, 0, len(changed))
+			for path := range changed {
+				res = append(res, path)
+			}
+			return res, nil
+		}
+	}
+}
+
+func (w *notifyWatcher) Close() error {
+	return w.file.Close()
+}
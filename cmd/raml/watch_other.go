@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+func newNotifyWatcher() (fileWatcher, error) {
+	return nil, errors.New("filesystem notifications are not supported on this platform")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestPollWatcher_Wait(t *testing.T) {
+	dir := t.TempDir()
+	modified := writeTestFile(t, dir, "modified.raml", "a")
+	removed := writeTestFile(t, dir, "removed.raml", "a")
+	untouched := writeTestFile(t, dir, "untouched.raml", "a")
+	created := filepath.Join(dir, "created.raml")
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T)
+		want   []string
+	}{
+		{name: "modified", change: func(t *testing.T) {
+			require.NoError(t, os.WriteFile(modified, []byte("ab"), 0o600))
+		}, want: []string{modified}},
+		{name: "created", change: func(t *testing.T) {
+			writeTestFile(t, dir, "created.raml", "a")
+		}, want: []string{created}},
+		{name: "removed", change: func(t *testing.T) {
+			require.NoError(t, os.Remove(removed))
+		}, want: []string{removed}},
+		{name: "several", change: func(t *testing.T) {
+			require.NoError(t, os.WriteFile(modified, []byte("abc"), 0o600))
+			require.NoError(t, os.Remove(created))
+		}, want: []string{modified, created}},
+	}
+
+	files := []string{modified, removed, untouched, created}
+	w := newPollWatcher(10 * time.Millisecond)
+	defer w.Close()
+	// The first call records the states of the files, later calls detect the changes made between them.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, err := w.Wait(ctx, files)
+	cancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			changed, err := w.Wait(ctx, files)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, changed)
+		})
+	}
+}
+
+func TestPollWatcher_DefaultInterval(t *testing.T) {
+	require.Equal(t, time.Second, newPollWatcher(0).interval)
+}
@@ -0,0 +1,170 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParameterError describes an HTTP parameter that cannot be coerced to or does not conform to its shape.
+type ParameterError struct {
+	// Name is the name of the query, URI or header parameter.
+	Name string
+	Err  error
+}
+
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("parameter \"%s\": %v", e.Name, e.Err)
+}
+
+func (e *ParameterError) Unwrap() error {
+	return e.Err
+}
+
+// CoerceParameter converts raw string values of an HTTP parameter to a value that matches the shape.
+//
+// Integers, numbers and booleans are parsed from their string representation, "null" and an empty string
+// are converted to nil for nil shapes, and objects are decoded from JSON. Date and time values stay
+// strings since the date shapes validate their string representation. Array values are built from
+// the repeated parameter values, a single value is split by commas. Members of union shapes are tried
+// in order, the first member the coerced value conforms to is used.
+//
+// The shape must be unwrapped. Errors are returned as *ParameterError.
+func (s *BaseShape) CoerceParameter(name string, values []string) (any, error) {
+	v, err := s.coerce(values)
+	if err != nil {
+		return nil, &ParameterError{Name: name, Err: err}
+	}
+	return v, nil
+}
+
+// ValidateParameter coerces raw string values of an HTTP parameter and validates the result against the shape.
+// The coerced value is returned on success. Errors are returned as *ParameterError.
+func (s *BaseShape) ValidateParameter(name string, values []string) (any, error) {
+	v, err := s.CoerceParameter(name, values)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Validate(v); err != nil {
+		return nil, &ParameterError{Name: name, Err: fmt.Errorf("validate value: %w", err)}
+	}
+	return v, nil
+}
+
+func (s *BaseShape) coerce(values []string) (any, error) {
+	switch ss := s.Shape.(type) {
+	case *RecursiveShape:
+		return ss.Head.coerce(values)
+	case *ArrayShape:
+		return ss.coerce(values)
+	case *UnionShape:
+		return ss.coerce(values)
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("expected single value, got %d", len(values))
+	}
+	return s.coerceScalar(values[0])
+}
+
+func (s *BaseShape) coerceScalar(value string) (any, error) {
+	switch s.Shape.(type) {
+	case *IntegerShape:
+		return coerceInteger(value)
+	case *NumberShape:
+		return coerceNumber(value)
+	case *BooleanShape:
+		b, err := strconv.ParseBool(value)
+		if err != nil || (value != "true" && value != "false") {
+			return nil, fmt.Errorf("parse boolean \"%s\": expected true or false", value)
+		}
+		return b, nil
+	case *NilShape:
+		if value != "" && value != "null" {
+			return nil, fmt.Errorf("parse nil \"%s\": expected empty value or null", value)
+		}
+		return nil, nil
+	case *ObjectShape, *JSONShape:
+		dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("decode json \"%s\": %w", value, err)
+		}
+		return v, nil
+	default:
+		// String, file, date and time shapes validate the string representation.
+		return value, nil
+	}
+}
+
+// coerceInteger parses the integer value. Values out of int range are returned as *big.Int.
+// jsonNumberPattern matches decimal number literals as defined by JSON.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// coerceNumber parses a decimal JSON number literal. Values accepted by strconv.ParseFloat only,
+// such as NaN, Inf, hexadecimal floats and underscore separators, are rejected. Literals out of the float64 range
+// are rejected by strconv.ParseFloat, so the result is always finite.
+func coerceNumber(value string) (any, error) {
+	if !jsonNumberPattern.MatchString(value) {
+		return nil, fmt.Errorf("parse number \"%s\": expected decimal number", value)
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse number \"%s\": %w", value, err)
+	}
+	return f, nil
+}
+
+func coerceInteger(value string) (any, error) {
+	i, err := strconv.ParseInt(value, 10, 0)
+	if err == nil {
+		return int(i), nil
+	}
+	bi, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("parse integer \"%s\": %w", value, err)
+	}
+	return bi, nil
+}
+
+func (s *ArrayShape) coerce(values []string) (any, error) {
+	if len(values) == 1 {
+		if values[0] == "" {
+			values = nil
+		} else {
+			values = strings.Split(values[0], ",")
+		}
+	}
+	items := make([]any, len(values))
+	for i, value := range values {
+		if s.Items == nil {
+			items[i] = value
+			continue
+		}
+		item, err := s.Items.coerce([]string{value})
+		if err != nil {
+			return nil, fmt.Errorf("coerce array item [%d]: %w", i, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func (s *UnionShape) coerce(values []string) (any, error) {
+	var errs []string
+	for _, item := range s.AnyOf {
+		v, err := item.coerce(values)
+		if err == nil {
+			err = item.Shape.validate(v, "$")
+		}
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", item.Type, err))
+	}
+	return nil, fmt.Errorf("value does not match any type: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,107 @@
+package raml
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseShape_CoerceParameter(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Count:
+    type: integer
+    maximum: 100
+  Ratio: number
+  Flag: boolean
+  Day: date-only
+  Tags: string[]
+  Ids:
+    type: array
+    items: integer
+  IntOrBool: integer | boolean
+  Nullable: nil | integer
+  Filter:
+    properties:
+      name: string
+`
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	r, err := ParseFromString(content, "library.raml", workDir, OptWithUnwrap())
+	require.NoError(t, err)
+	lib, ok := r.EntryPoint().(*Library)
+	require.True(t, ok)
+
+	bigInt, _ := new(big.Int).SetString("100000000000000000000", 10)
+	tests := []struct {
+		name    string
+		typ     string
+		values  []string
+		want    any
+		wantErr bool
+	}{
+		{name: "integer", typ: "Count", values: []string{"42"}, want: 42},
+		{name: "big integer", typ: "Count", values: []string{"100000000000000000000"}, want: bigInt},
+		{name: "invalid integer", typ: "Count", values: []string{"4.2"}, wantErr: true},
+		{name: "repeated scalar", typ: "Count", values: []string{"1", "2"}, wantErr: true},
+		{name: "number", typ: "Ratio", values: []string{"0.5"}, want: 0.5},
+		{name: "number with exponent", typ: "Ratio", values: []string{"-1.5e2"}, want: -150.0},
+		{name: "NaN", typ: "Ratio", values: []string{"NaN"}, wantErr: true},
+		{name: "infinity", typ: "Ratio", values: []string{"Inf"}, wantErr: true},
+		{name: "negative infinity", typ: "Ratio", values: []string{"-Infinity"}, wantErr: true},
+		{name: "hexadecimal float", typ: "Ratio", values: []string{"0x1p3"}, wantErr: true},
+		{name: "underscore separators", typ: "Ratio", values: []string{"1_000"}, wantErr: true},
+		{name: "number out of range", typ: "Ratio", values: []string{"1e400"}, wantErr: true},
+		{name: "boolean", typ: "Flag", values: []string{"true"}, want: true},
+		{name: "invalid boolean", typ: "Flag", values: []string{"1"}, wantErr: true},
+		{name: "date", typ: "Day", values: []string{"2024-01-01"}, want: "2024-01-01"},
+		{name: "comma-separated", typ: "Tags", values: []string{"a,b"}, want: []any{"a", "b"}},
+		{name: "empty array", typ: "Tags", values: []string{""}, want: []any{}},
+		{name: "repeated", typ: "Ids", values: []string{"1", "2"}, want: []any{1, 2}},
+		{name: "invalid array item", typ: "Ids", values: []string{"1,a"}, wantErr: true},
+		{name: "union integer", typ: "IntOrBool", values: []string{"1"}, want: 1},
+		{name: "union boolean", typ: "IntOrBool", values: []string{"false"}, want: false},
+		{name: "union mismatch", typ: "IntOrBool", values: []string{"a"}, wantErr: true},
+		{name: "nil", typ: "Nullable", values: []string{""}, want: nil},
+		{name: "object", typ: "Filter", values: []string{`{"name": "a"}`}, want: map[string]any{"name": "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape, found := lib.Types.Get(tt.typ)
+			require.True(t, found)
+			got, err := shape.CoerceParameter("param", tt.values)
+			if tt.wantErr {
+				var perr *ParameterError
+				require.ErrorAs(t, err, &perr)
+				require.Equal(t, "param", perr.Name)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBaseShape_ValidateParameter(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Count:
+    type: integer
+    maximum: 100
+`
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	r, err := ParseFromString(content, "library.raml", workDir, OptWithUnwrap())
+	require.NoError(t, err)
+	shape, ok := r.EntryPoint().(*Library).Types.Get("Count")
+	require.True(t, ok)
+
+	got, err := shape.ValidateParameter("limit", []string{"10"})
+	require.NoError(t, err)
+	require.Equal(t, 10, got)
+
+	_, err = shape.ValidateParameter("limit", []string{"101"})
+	require.ErrorContains(t, err, `parameter "limit"`)
+}
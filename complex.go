@@ -99,7 +99,11 @@ func (s *ArrayShape) validate(v interface{}, ctxPath string) error {
 	for ii, item := range i {
 		ctxPathA := ctxPath + "[" + strconv.Itoa(ii) + "]"
 		if s.Items != nil {
-			if err := s.Items.Shape.validate(item, ctxPathA); err != nil {
+			val, err := toValidationValue(item)
+			if err != nil {
+				return fmt.Errorf("convert array item %s: %w", ctxPathA, err)
+			}
+			if err = s.Items.Shape.validate(val, ctxPathA); err != nil {
 				return fmt.Errorf("validate array item %s: %w", ctxPathA, err)
 			}
 		}
@@ -454,7 +458,11 @@ func (s *ObjectShape) validateProperties(ctxPath string, props map[string]interf
 		// Explicitly defined properties have priority over pattern properties.
 		ctxPathK := ctxPath + "." + k
 
-		found, err := s.validateProperty(k, item, ctxPathK)
+		val, err := toValidationValue(item)
+		if err != nil {
+			return fmt.Errorf("convert property %s: %w", ctxPathK, err)
+		}
+		found, err := s.validateProperty(k, val, ctxPathK)
 		if err != nil {
 			return fmt.Errorf("validate property %s: %w", ctxPathK, err)
 		}
@@ -465,7 +473,7 @@ func (s *ObjectShape) validateProperties(ctxPath string, props map[string]interf
 			return fmt.Errorf("unexpected additional property \"%s\"", k)
 		}
 
-		_, err = s.validatePatternProperty(k, item, ctxPathK)
+		_, err = s.validatePatternProperty(k, val, ctxPathK)
 		if err != nil {
 			return fmt.Errorf("validate pattern property %s: %w", ctxPathK, err)
 		}
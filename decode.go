@@ -0,0 +1,501 @@
+package raml
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	typeBigIntPtr       = reflect.PointerTo(typeBigInt)
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type decodeOptions struct {
+	implementations map[reflect.Type]map[string]reflect.Type
+}
+
+type DecodeOpt interface {
+	Apply(*decodeOptions)
+}
+
+type decodeOptImplementation struct {
+	iface              reflect.Type
+	discriminatorValue string
+	impl               reflect.Type
+}
+
+func (o decodeOptImplementation) Apply(opt *decodeOptions) {
+	if opt.implementations == nil {
+		opt.implementations = make(map[reflect.Type]map[string]reflect.Type)
+	}
+	impls, ok := opt.implementations[o.iface]
+	if !ok {
+		impls = make(map[string]reflect.Type)
+		opt.implementations[o.iface] = impls
+	}
+	impls[o.discriminatorValue] = o.impl
+}
+
+// OptDecodeImplementation registers the Go implementation of the interface I for the discriminator value.
+// Objects with the discriminator value are decoded into a new value of the impl type when the target is
+// of type I. If impl is a pointer, the interface is set to a pointer to the decoded value.
+//
+//	raml.OptDecodeImplementation[Pet]("Cat", &Cat{})
+func OptDecodeImplementation[I any](discriminatorValue string, impl I) DecodeOpt {
+	return decodeOptImplementation{
+		iface:              reflect.TypeOf((*I)(nil)).Elem(),
+		discriminatorValue: discriminatorValue,
+		impl:               reflect.TypeOf(impl),
+	}
+}
+
+// Decode validates the data against the shape and populates the Go value pointed to by out.
+//
+// Struct fields are matched by their "json" tags, the same way as in Validate. The shape guides
+// the conversion of values:
+//   - datetime is decoded to time.Time using the format declared by the shape (rfc3339 or rfc2616),
+//     date-only, datetime-only and time-only are decoded to time.Time. Date and time values are also
+//     decoded to types implementing encoding.TextUnmarshaler (e.g. civil.Date) and date-only is decoded
+//     to structs with Year, Month and Day fields.
+//   - integer is decoded to Go integers with overflow check, to big.Int, or to the sized Go integer chosen by
+//     the format facet when the target is an empty interface.
+//   - Discriminated union members and objects with discriminator are decoded to implementations registered
+//     with OptDecodeImplementation when the target is an interface.
+//
+// The shape must be unwrapped.
+func (s *BaseShape) Decode(data any, out any, opts ...DecodeOpt) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("out must be a non-nil pointer, got %T", out)
+	}
+	dOpts := &decodeOptions{}
+	for _, opt := range opts {
+		opt.Apply(dOpts)
+	}
+	for iface := range dOpts.implementations {
+		if iface.Kind() != reflect.Interface {
+			return fmt.Errorf("implementations can be registered only for interfaces, got %s", iface)
+		}
+	}
+	if err := s.Validate(data); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	d := &decoder{implementations: dOpts.implementations}
+	return d.decode(s, data, rv.Elem(), "$")
+}
+
+type decoder struct {
+	implementations map[reflect.Type]map[string]reflect.Type
+}
+
+//nolint:gocyclo,cyclop // Contains a flat switch over shape types.
+func (d *decoder) decode(shape *BaseShape, v any, rv reflect.Value, ctxPath string) error {
+	val, err := toValidationValue(v)
+	if err != nil {
+		return fmt.Errorf("convert value %s: %w", ctxPath, err)
+	}
+	if rv.Kind() == reflect.Pointer && rv.Type() != typeBigIntPtr {
+		if val == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return d.decode(shape, val, rv.Elem(), ctxPath)
+	}
+
+	switch ss := shape.Shape.(type) {
+	case *RecursiveShape:
+		return d.decode(ss.Head, val, rv, ctxPath)
+	case *UnionShape:
+		return d.decodeUnion(ss, val, rv, ctxPath)
+	}
+	if val == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	if rv.Kind() == reflect.Interface && rv.NumMethod() > 0 {
+		return d.decodeImplementation(shape, val, rv, ctxPath)
+	}
+
+	switch ss := shape.Shape.(type) {
+	case *ObjectShape:
+		return d.decodeObject(ss, val, rv, ctxPath)
+	case *ArrayShape:
+		return d.decodeArray(ss, val, rv, ctxPath)
+	case *IntegerShape:
+		return decodeInteger(ss, val, rv)
+	case *NumberShape:
+		return decodeNumber(ss, val, rv)
+	case *DateTimeShape:
+		layout := time.RFC3339
+		if ss.Format != nil && *ss.Format == DateTimeFormatRFC2616 {
+			layout = RFC2616
+		}
+		return decodeTime(val, layout, rv)
+	case *DateTimeOnlyShape:
+		return decodeTime(val, DateTime, rv)
+	case *DateOnlyShape:
+		return decodeTime(val, time.DateOnly, rv)
+	case *TimeOnlyShape:
+		return decodeTime(val, time.TimeOnly, rv)
+	default:
+		return assignValue(val, rv)
+	}
+}
+
+func (d *decoder) decodeUnion(s *UnionShape, v any, rv reflect.Value, ctxPath string) error {
+	member, discriminated := s.findDiscriminatedMember(v)
+	if !discriminated {
+		for _, item := range s.AnyOf {
+			if err := item.Shape.validate(v, ctxPath); err == nil {
+				member = item
+				break
+			}
+		}
+	}
+	if member == nil {
+		return fmt.Errorf("value %s does not match any union member", ctxPath)
+	}
+	return d.decode(member, v, rv, ctxPath)
+}
+
+// decodeImplementation decodes the object into the implementation of the interface
+// registered for its discriminator value.
+func (d *decoder) decodeImplementation(shape *BaseShape, v any, rv reflect.Value, ctxPath string) error {
+	impls, ok := d.implementations[rv.Type()]
+	if !ok {
+		return fmt.Errorf("no implementations registered for interface %s at %s", rv.Type(), ctxPath)
+	}
+	objShape, ok := shape.Shape.(*ObjectShape)
+	if !ok || objShape.Discriminator == nil {
+		return fmt.Errorf("shape of %s must be an object with discriminator to decode into interface %s",
+			ctxPath, rv.Type())
+	}
+	props, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("invalid type of %s, got %T, expected map[string]interface{}", ctxPath, v)
+	}
+	discriminatorValue := fmt.Sprint(props[*objShape.Discriminator])
+	implType, ok := impls[discriminatorValue]
+	if !ok {
+		return fmt.Errorf("no implementation of %s registered for discriminator value \"%s\" at %s",
+			rv.Type(), discriminatorValue, ctxPath)
+	}
+	var impl reflect.Value
+	if implType.Kind() == reflect.Pointer {
+		impl = reflect.New(implType.Elem())
+		if err := d.decodeObject(objShape, v, impl.Elem(), ctxPath); err != nil {
+			return err
+		}
+	} else {
+		impl = reflect.New(implType).Elem()
+		if err := d.decodeObject(objShape, v, impl, ctxPath); err != nil {
+			return err
+		}
+	}
+	rv.Set(impl)
+	return nil
+}
+
+func (d *decoder) decodeObject(s *ObjectShape, v any, rv reflect.Value, ctxPath string) error {
+	props, ok := v.(map[string]any)
+	if !ok {
+		return assignValue(v, rv)
+	}
+	switch {
+	case rv.Kind() == reflect.Struct:
+		si := getStructInfo(rv.Type())
+		for _, f := range si.fields {
+			item, present := props[f.name]
+			if !present {
+				continue
+			}
+			if err := d.decodeProperty(s, f.name, item, fieldByIndexAlloc(rv, f.index), ctxPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(props)))
+		}
+		for k, item := range props {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := d.decodeProperty(s, k, item, elem, ctxPath); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+		}
+		return nil
+	case rv.Kind() == reflect.Interface && rv.NumMethod() == 0:
+		m := make(map[string]any, len(props))
+		for k, item := range props {
+			var elem any
+			if err := d.decodeProperty(s, k, item, reflect.ValueOf(&elem).Elem(), ctxPath); err != nil {
+				return err
+			}
+			m[k] = elem
+		}
+		rv.Set(reflect.ValueOf(m))
+		return nil
+	default:
+		return assignValue(v, rv)
+	}
+}
+
+func (d *decoder) decodeProperty(s *ObjectShape, k string, item any, rv reflect.Value, ctxPath string) error {
+	ctxPathK := ctxPath + "." + k
+	propShape := s.findPropertyShape(k)
+	if propShape == nil {
+		val, err := toValidationValue(item)
+		if err != nil {
+			return fmt.Errorf("convert property %s: %w", ctxPathK, err)
+		}
+		if err = assignValue(val, rv); err != nil {
+			return fmt.Errorf("decode property %s: %w", ctxPathK, err)
+		}
+		return nil
+	}
+	if err := d.decode(propShape, item, rv, ctxPathK); err != nil {
+		return fmt.Errorf("decode property %s: %w", ctxPathK, err)
+	}
+	return nil
+}
+
+func (d *decoder) decodeArray(s *ArrayShape, v any, rv reflect.Value, ctxPath string) error {
+	items, ok := v.([]any)
+	if !ok || s.Items == nil {
+		return assignValue(v, rv)
+	}
+	var target reflect.Value
+	switch {
+	case rv.Kind() == reflect.Slice:
+		target = reflect.MakeSlice(rv.Type(), len(items), len(items))
+	case rv.Kind() == reflect.Array:
+		if rv.Len() != len(items) {
+			return fmt.Errorf("cannot decode %d items into %s", len(items), rv.Type())
+		}
+		target = rv
+	case rv.Kind() == reflect.Interface && rv.NumMethod() == 0:
+		target = reflect.ValueOf(make([]any, len(items)))
+	default:
+		return assignValue(v, rv)
+	}
+	for i, item := range items {
+		ctxPathA := ctxPath + "[" + strconv.Itoa(i) + "]"
+		if err := d.decode(s.Items, item, target.Index(i), ctxPathA); err != nil {
+			return fmt.Errorf("decode array item %s: %w", ctxPathA, err)
+		}
+	}
+	if target != rv {
+		rv.Set(target)
+	}
+	return nil
+}
+
+//nolint:gocyclo,cyclop // Contains a flat switch over reflect kinds.
+func decodeInteger(s *IntegerShape, v any, rv reflect.Value) error {
+	n, err := toBigInt(v)
+	if err != nil {
+		return err
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			return fmt.Errorf("value %s overflows %s", n, rv.Type())
+		}
+		rv.SetInt(n.Int64())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("value %s overflows %s", n, rv.Type())
+		}
+		rv.SetUint(n.Uint64())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		rv.SetFloat(f)
+		return nil
+	case reflect.Pointer:
+		// Only *big.Int pointers are left unresolved.
+		rv.Set(reflect.ValueOf(n))
+		return nil
+	}
+	if rv.Type() == typeBigInt {
+		rv.Set(reflect.ValueOf(n).Elem())
+		return nil
+	}
+	if rv.Kind() != reflect.Interface || rv.NumMethod() != 0 {
+		return assignValue(json.Number(n.String()), rv)
+	}
+	format := ""
+	if s.Format != nil {
+		format = *s.Format
+	}
+	// Choose the sized Go integer by format.
+	var sized reflect.Value
+	switch format {
+	case "int8":
+		sized = reflect.New(reflect.TypeOf(int8(0))).Elem()
+	case "int16":
+		sized = reflect.New(reflect.TypeOf(int16(0))).Elem()
+	case "int32", "int":
+		sized = reflect.New(reflect.TypeOf(int32(0))).Elem()
+	case "int64", "long":
+		sized = reflect.New(reflect.TypeOf(int64(0))).Elem()
+	default:
+		if !n.IsInt64() {
+			rv.Set(reflect.ValueOf(n))
+			return nil
+		}
+		sized = reflect.New(reflect.TypeOf(0)).Elem()
+	}
+	if !n.IsInt64() || sized.OverflowInt(n.Int64()) {
+		return fmt.Errorf("value %s overflows format %s", n, format)
+	}
+	sized.SetInt(n.Int64())
+	rv.Set(sized)
+	return nil
+}
+
+func decodeNumber(s *NumberShape, v any, rv reflect.Value) error {
+	f, err := toFloat64(v)
+	if err != nil {
+		return err
+	}
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if rv.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", f, rv.Type())
+		}
+		rv.SetFloat(f)
+		return nil
+	case reflect.Interface:
+		if rv.NumMethod() == 0 {
+			if s.Format != nil && *s.Format == "float" {
+				rv.Set(reflect.ValueOf(float32(f)))
+			} else {
+				rv.Set(reflect.ValueOf(f))
+			}
+			return nil
+		}
+	}
+	return assignValue(v, rv)
+}
+
+// decodeTime decodes the date or time value using the layout of its string representation.
+func decodeTime(v any, layout string, rv reflect.Value) error {
+	var t time.Time
+	switch val := v.(type) {
+	case time.Time:
+		t = val
+	case string:
+		parsed, err := time.Parse(layout, val)
+		if err != nil {
+			return fmt.Errorf("parse time: %w", err)
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
+	}
+	if layout == RFC2616 {
+		// The format always uses GMT.
+		t = t.UTC()
+	}
+	switch {
+	case rv.Type() == typeTime || (rv.Kind() == reflect.Interface && rv.NumMethod() == 0):
+		rv.Set(reflect.ValueOf(t))
+		return nil
+	case rv.Kind() == reflect.String:
+		rv.SetString(t.Format(layout))
+		return nil
+	case reflect.PointerTo(rv.Type()).Implements(typeTextUnmarshaler):
+		if err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(t.Format(layout))); err != nil {
+			return fmt.Errorf("unmarshal text: %w", err)
+		}
+		return nil
+	case layout == time.DateOnly && rv.Kind() == reflect.Struct:
+		return setDateFields(t, rv)
+	default:
+		return fmt.Errorf("cannot decode time into %s", rv.Type())
+	}
+}
+
+// setDateFields populates the date-like struct with Year, Month and Day integer fields.
+func setDateFields(t time.Time, rv reflect.Value) error {
+	year, month, day := t.Date()
+	values := map[string]int{"Year": year, "Month": int(month), "Day": day}
+	for name, value := range values {
+		f := rv.FieldByName(name)
+		if !f.IsValid() || !f.CanSet() {
+			return fmt.Errorf("cannot decode date into %s: field %s not found", rv.Type(), name)
+		}
+		// time.Month is an integer kind as well.
+		if f.Kind() < reflect.Int || f.Kind() > reflect.Int64 {
+			return fmt.Errorf("cannot decode date into %s: field %s must be an integer", rv.Type(), name)
+		}
+		f.SetInt(int64(value))
+	}
+	return nil
+}
+
+// assignValue assigns the generic value to the Go value. Values of incompatible types are converted
+// by marshaling to JSON.
+func assignValue(v any, rv reflect.Value) error {
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		if v == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(v))
+		}
+		return nil
+	}
+	if v == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	vv := reflect.ValueOf(v)
+	switch {
+	case vv.Type().AssignableTo(rv.Type()):
+		rv.Set(vv)
+		return nil
+	case vv.Kind() == reflect.String && rv.Kind() != reflect.String &&
+		reflect.PointerTo(rv.Type()).Implements(typeTextUnmarshaler):
+		if err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(vv.String())); err != nil {
+			return fmt.Errorf("unmarshal text: %w", err)
+		}
+		return nil
+	case (vv.Kind() == reflect.String || vv.Kind() == reflect.Bool) && vv.Kind() == rv.Kind():
+		rv.Set(vv.Convert(rv.Type()))
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	if err = json.Unmarshal(data, rv.Addr().Interface()); err != nil {
+		return fmt.Errorf("unmarshal json into %s: %w", rv.Type(), err)
+	}
+	return nil
+}
+
+// fieldByIndexAlloc returns the nested field by index allocating nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,225 @@
+package raml
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type decodeTestDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+type decodeTestTimeText struct {
+	text string
+}
+
+func (t *decodeTestTimeText) UnmarshalText(data []byte) error {
+	t.text = string(data)
+	return nil
+}
+
+type decodeTestPet interface {
+	Kind() string
+}
+
+type decodeTestCat struct {
+	Name  string `json:"name"`
+	Lives int8   `json:"lives"`
+}
+
+func (decodeTestCat) Kind() string { return "cat" }
+
+type decodeTestDog struct {
+	Name string `json:"name"`
+	Good bool   `json:"good"`
+}
+
+func (*decodeTestDog) Kind() string { return "dog" }
+
+type decodeTestOrder struct {
+	ID        int16              `json:"id"`
+	Big       *big.Int           `json:"big"`
+	Created   time.Time          `json:"created"`
+	Modified  *time.Time         `json:"modified,omitempty"`
+	Day       decodeTestDate     `json:"day"`
+	DayText   decodeTestTimeText `json:"dayText"`
+	Alarm     time.Time          `json:"alarm"`
+	Ratio     float32            `json:"ratio"`
+	Tags      []string           `json:"tags"`
+	Pets      []decodeTestPet    `json:"pets"`
+	Extra     map[string]any     `json:"extra"`
+	Anything  any                `json:"anything"`
+	Undefined string             `json:"undefined"`
+}
+
+func TestBaseShape_Decode(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Pet:
+    discriminator: kind
+    properties:
+      kind: string
+      name: string
+  Cat:
+    type: Pet
+    properties:
+      lives:
+        type: integer
+        format: int8
+  Dog:
+    type: Pet
+    discriminatorValue: doggo
+    properties:
+      good: boolean
+  Order:
+    properties:
+      id:
+        type: integer
+        format: int16
+      big: integer
+      created: datetime
+      modified?:
+        type: datetime
+        format: rfc2616
+      day: date-only
+      dayText: date-only
+      alarm: time-only
+      ratio:
+        type: number
+        format: float
+      tags: string[]
+      pets:
+        type: array
+        items: Cat | Dog
+      extra:
+        type: object
+        properties:
+          count:
+            type: integer
+            format: int32
+          at: datetime-only
+      anything?: any
+`
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	r, err := ParseFromString(content, "library.raml", workDir, OptWithUnwrap())
+	require.NoError(t, err)
+	lib, ok := r.EntryPoint().(*Library)
+	require.True(t, ok)
+	order, ok := lib.Types.Get("Order")
+	require.True(t, ok)
+	opts := []DecodeOpt{
+		OptDecodeImplementation[decodeTestPet]("Cat", decodeTestCat{}),
+		OptDecodeImplementation[decodeTestPet]("doggo", &decodeTestDog{}),
+	}
+
+	var data map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 12,
+		"big": 100000000000000000000,
+		"created": "2024-01-02T03:04:05Z",
+		"modified": "Tue, 02 Jan 2024 03:04:05 GMT",
+		"day": "2024-01-02",
+		"dayText": "2024-01-02",
+		"alarm": "10:30:00",
+		"ratio": 0.5,
+		"tags": ["a", "b"],
+		"pets": [{"kind": "Cat", "name": "Tom", "lives": 9}, {"kind": "doggo", "name": "Rex", "good": true}],
+		"extra": {"count": 1, "at": "2024-01-02T03:04:05"},
+		"anything": {"a": [1]},
+		"undefined": "a"
+	}`), &data))
+
+	var got decodeTestOrder
+	require.NoError(t, order.Decode(data, &got, opts...))
+
+	bigInt, _ := new(big.Int).SetString("100000000000000000000", 10)
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.Equal(t, decodeTestOrder{
+		ID:       12,
+		Big:      bigInt,
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Modified: &modified,
+		Day:      decodeTestDate{Year: 2024, Month: time.January, Day: 2},
+		DayText:  decodeTestTimeText{text: "2024-01-02"},
+		Alarm:    time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC),
+		Ratio:    0.5,
+		Tags:     []string{"a", "b"},
+		Pets: []decodeTestPet{
+			decodeTestCat{Name: "Tom", Lives: 9},
+			&decodeTestDog{Name: "Rex", Good: true},
+		},
+		Extra: map[string]any{
+			"count": int32(1),
+			"at":    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Anything:  map[string]any{"a": []any{float64(1)}},
+		Undefined: "a",
+	}, got)
+
+	t.Run("empty interface uses sized integers", func(t *testing.T) {
+		cat, found := lib.Types.Get("Cat")
+		require.True(t, found)
+		var v any
+		require.NoError(t, cat.Decode(map[string]any{"kind": "Cat", "name": "Tom", "lives": 3}, &v))
+		require.Equal(t, map[string]any{"kind": "Cat", "name": "Tom", "lives": int8(3)}, v)
+	})
+
+	t.Run("object with discriminator into interface", func(t *testing.T) {
+		cat, found := lib.Types.Get("Cat")
+		require.True(t, found)
+		var v decodeTestPet
+		require.NoError(t, cat.Decode(map[string]any{"kind": "Cat", "name": "Tom", "lives": 3}, &v, opts...))
+		require.Equal(t, decodeTestCat{Name: "Tom", Lives: 3}, v)
+	})
+
+	errTests := []struct {
+		name string
+		typ  string
+		data any
+		out  any
+		opts []DecodeOpt
+	}{
+		{
+			name: "invalid data",
+			typ:  "Order",
+			data: map[string]any{"id": "1"},
+			out:  &decodeTestOrder{},
+		},
+		{
+			name: "non-pointer target",
+			typ:  "Order",
+			data: data,
+			out:  decodeTestOrder{},
+		},
+		{
+			name: "integer overflow",
+			typ:  "Cat",
+			data: map[string]any{"kind": "Cat", "name": "Tom", "lives": 300},
+			out: &struct {
+				Lives uint8 `json:"lives"`
+			}{},
+		},
+		{
+			name: "missing implementation",
+			typ:  "Order",
+			data: data,
+			out:  &decodeTestOrder{},
+			opts: opts[:1],
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape, found := lib.Types.Get(tt.typ)
+			require.True(t, found)
+			require.Error(t, shape.Decode(tt.data, tt.out, tt.opts...))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package raml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ApplyDefaults returns a copy of the value with missing optional properties filled with their default values.
+//
+// Defaults are applied recursively to nested objects, array items and union members. A member of a union
+// is selected by the discriminator value if the member defines a discriminator, otherwise the first member
+// that the value conforms to is used. Values that do not match the shape are copied as is, the method
+// does not validate the value. The value is converted the same way as in Validate, so Go structs
+// are returned as generic maps. The shape must be unwrapped.
+func (s *BaseShape) ApplyDefaults(v any) (any, error) {
+	return s.applyDefaults(v, "$")
+}
+
+func (s *BaseShape) applyDefaults(v any, ctxPath string) (any, error) {
+	val, err := toValidationValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("convert value %s: %w", ctxPath, err)
+	}
+	switch ss := s.Shape.(type) {
+	case *RecursiveShape:
+		return ss.Head.applyDefaults(val, ctxPath)
+	case *ObjectShape:
+		return ss.applyDefaults(val, ctxPath)
+	case *ArrayShape:
+		return ss.applyDefaults(val, ctxPath)
+	case *UnionShape:
+		return ss.applyDefaults(val, ctxPath)
+	default:
+		return copyValue(val), nil
+	}
+}
+
+func (s *ObjectShape) applyDefaults(v any, ctxPath string) (any, error) {
+	props, ok := v.(map[string]any)
+	if !ok {
+		return copyValue(v), nil
+	}
+	out := make(map[string]any, len(props))
+	for k, item := range props {
+		ctxPathK := ctxPath + "." + k
+		propShape := s.findPropertyShape(k)
+		if propShape == nil {
+			val, err := toValidationValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("convert property %s: %w", ctxPathK, err)
+			}
+			out[k] = copyValue(val)
+			continue
+		}
+		val, err := propShape.applyDefaults(item, ctxPathK)
+		if err != nil {
+			return nil, fmt.Errorf("apply defaults to property %s: %w", ctxPathK, err)
+		}
+		out[k] = val
+	}
+	if s.Properties == nil {
+		return out, nil
+	}
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		k, prop := pair.Key, pair.Value
+		if _, present := props[k]; present || prop.Required || prop.Base.Default == nil {
+			continue
+		}
+		// Default value may be a partial object that has defaults on its own.
+		ctxPathK := ctxPath + "." + k
+		val, err := prop.Base.applyDefaults(prop.Base.Default.Value, ctxPathK)
+		if err != nil {
+			return nil, fmt.Errorf("apply defaults to default value of property %s: %w", ctxPathK, err)
+		}
+		out[k] = val
+	}
+	return out, nil
+}
+
+// findPropertyShape returns the shape of the property or the first pattern property that matches the name.
+func (s *ObjectShape) findPropertyShape(k string) *BaseShape {
+	if s.Properties != nil {
+		if p, present := s.Properties.Get(k); present {
+			return p.Base
+		}
+	}
+	if s.PatternProperties != nil {
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			if pair.Value.Pattern.MatchString(k) {
+				return pair.Value.Base
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ArrayShape) applyDefaults(v any, ctxPath string) (any, error) {
+	items, ok := v.([]any)
+	if !ok || s.Items == nil {
+		return copyValue(v), nil
+	}
+	out := make([]any, len(items))
+	for i, item := range items {
+		ctxPathA := ctxPath + "[" + strconv.Itoa(i) + "]"
+		val, err := s.Items.applyDefaults(item, ctxPathA)
+		if err != nil {
+			return nil, fmt.Errorf("apply defaults to array item %s: %w", ctxPathA, err)
+		}
+		out[i] = val
+	}
+	return out, nil
+}
+
+func (s *UnionShape) applyDefaults(v any, ctxPath string) (any, error) {
+	member, discriminated := s.findDiscriminatedMember(v)
+	if !discriminated {
+		for _, item := range s.AnyOf {
+			if err := item.Shape.validate(v, ctxPath); err == nil {
+				member = item
+				break
+			}
+		}
+	}
+	if member == nil {
+		return copyValue(v), nil
+	}
+	return member.applyDefaults(v, ctxPath)
+}
+
+// findDiscriminatedMember returns the union member that the discriminator value of the object refers to.
+// The second value reports whether the object has a discriminator property of any member.
+func (s *UnionShape) findDiscriminatedMember(v any) (*BaseShape, bool) {
+	props, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	discriminated := false
+	for _, item := range s.AnyOf {
+		member := item
+		if rs, isRecursive := member.Shape.(*RecursiveShape); isRecursive {
+			member = rs.Head
+		}
+		objShape, isObject := member.Shape.(*ObjectShape)
+		if !isObject || objShape.Discriminator == nil {
+			continue
+		}
+		value, present := props[*objShape.Discriminator]
+		if !present {
+			continue
+		}
+		discriminated = true
+		if reflect.DeepEqual(value, discriminatorValueOf(member, objShape)) {
+			return item, true
+		}
+	}
+	return nil, discriminated
+}
+
+// discriminatorValueOf returns the explicit discriminator value of the object shape or the name of its type.
+func discriminatorValueOf(base *BaseShape, s *ObjectShape) any {
+	if s.DiscriminatorValue != nil {
+		return s.DiscriminatorValue
+	}
+	if base.Name != "" {
+		return base.Name
+	}
+	// Unwrapped union members are anonymous, the referenced type is kept in the type label.
+	label := base.TypeLabel
+	if i := strings.LastIndexByte(label, '.'); i >= 0 {
+		label = label[i+1:]
+	}
+	return label
+}
+
+// copyValue returns a deep copy of generic maps and slices. Other values are returned as is.
+func copyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[k] = copyValue(item)
+		}
+		return m
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = copyValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
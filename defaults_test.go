@@ -0,0 +1,146 @@
+package raml
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseShape_ApplyDefaults(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Settings:
+    properties:
+      enabled:
+        type: boolean
+        default: true
+      retries?:
+        type: integer
+        default: 3
+  Pet:
+    discriminator: kind
+    properties:
+      kind: string
+  Cat:
+    type: Pet
+    properties:
+      lives?:
+        type: integer
+        default: 9
+  Dog:
+    type: Pet
+    discriminatorValue: doggo
+    properties:
+      good?:
+        type: boolean
+        default: true
+  Config:
+    properties:
+      name: string
+      mode?:
+        type: string
+        default: auto
+      settings?:
+        type: Settings
+        default: {}
+      items?:
+        type: Settings[]
+      pets?:
+        type: array
+        items: Cat | Dog
+`
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	r, err := ParseFromString(content, "library.raml", workDir, OptWithUnwrap())
+	require.NoError(t, err)
+	lib, ok := r.EntryPoint().(*Library)
+	require.True(t, ok)
+	config, ok := lib.Types.Get("Config")
+	require.True(t, ok)
+
+	type settings struct {
+		Enabled bool `json:"enabled"`
+		Retries *int `json:"retries,omitempty"`
+	}
+
+	tests := []struct {
+		name    string
+		v       any
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "missing optional properties",
+			v:    map[string]any{"name": "a"},
+			want: map[string]any{
+				"name":     "a",
+				"mode":     "auto",
+				"settings": map[string]any{"retries": 3},
+			},
+		},
+		{
+			name: "present properties are kept",
+			v:    map[string]any{"name": "a", "mode": "manual", "settings": map[string]any{"retries": 1}},
+			want: map[string]any{
+				"name":     "a",
+				"mode":     "manual",
+				"settings": map[string]any{"retries": 1},
+			},
+		},
+		{
+			name: "array items and discriminated union",
+			v: map[string]any{
+				"name":  "a",
+				"mode":  "auto",
+				"items": []settings{{Enabled: false}},
+				"pets": []any{
+					map[string]any{"kind": "Cat"},
+					map[string]any{"kind": "doggo", "good": false},
+					map[string]any{"kind": "unknown"},
+				},
+			},
+			want: map[string]any{
+				"name":     "a",
+				"mode":     "auto",
+				"settings": map[string]any{"retries": 3},
+				"items":    []any{map[string]any{"enabled": false, "retries": 3}},
+				"pets": []any{
+					map[string]any{"kind": "Cat", "lives": 9},
+					map[string]any{"kind": "doggo", "good": false},
+					map[string]any{"kind": "unknown"},
+				},
+			},
+		},
+		{
+			name: "non-object value is returned as is",
+			v:    "string",
+			want: "string",
+		},
+		{
+			name:    "unsupported value",
+			v:       map[string]any{"name": "a", "items": []any{make(chan int)}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := config.ApplyDefaults(tt.v)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("default value is copied", func(t *testing.T) {
+		got, err := config.ApplyDefaults(map[string]any{"name": "a"})
+		require.NoError(t, err)
+		got.(map[string]any)["settings"].(map[string]any)["retries"] = 5
+		got, err = config.ApplyDefaults(map[string]any{"name": "a"})
+		require.NoError(t, err)
+		require.Equal(t, 3, got.(map[string]any)["settings"].(map[string]any)["retries"])
+	})
+}
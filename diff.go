@@ -0,0 +1,536 @@
+package raml
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind is the kind of a change between two versions of a type.
+type ChangeKind string
+
+const (
+	ChangeTypeAdded                   ChangeKind = "type-added"
+	ChangeTypeRemoved                 ChangeKind = "type-removed"
+	ChangeTypeChanged                 ChangeKind = "type-changed"
+	ChangePropertyAdded               ChangeKind = "property-added"
+	ChangePropertyRemoved             ChangeKind = "property-removed"
+	ChangePropertyRequired            ChangeKind = "property-required"
+	ChangePropertyOptional            ChangeKind = "property-optional"
+	ChangeEnumValueAdded              ChangeKind = "enum-value-added"
+	ChangeEnumValueRemoved            ChangeKind = "enum-value-removed"
+	ChangeUnionMemberAdded            ChangeKind = "union-member-added"
+	ChangeUnionMemberRemoved          ChangeKind = "union-member-removed"
+	ChangeAdditionalPropertiesChanged ChangeKind = "additional-properties-changed"
+	ChangeFacetTightened              ChangeKind = "facet-tightened"
+	ChangeFacetRelaxed                ChangeKind = "facet-relaxed"
+	ChangeFacetChanged                ChangeKind = "facet-changed"
+)
+
+// Change describes a difference between the old and the new version of a type.
+//
+// Changes are classified from both sides of a data exchange. Producers create values of the type,
+// e.g. clients that send requests, and break if the new type rejects values that the old type accepted.
+// Consumers read values of the type, e.g. clients that receive responses, and break if the new type
+// allows values that the old type rejected.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path is the path to the changed shape, e.g. "Order.items[].name".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// BreaksProducers is true if values of the old type may be invalid for the new type.
+	BreaksProducers bool `json:"breaksProducers"`
+	// BreaksConsumers is true if values of the new type may be invalid for the old type.
+	BreaksConsumers bool `json:"breaksConsumers"`
+}
+
+// Breaking reports whether the change breaks producers or consumers.
+func (c Change) Breaking() bool {
+	return c.BreaksProducers || c.BreaksConsumers
+}
+
+func (c Change) String() string {
+	return c.Path + ": " + c.Message
+}
+
+// DiffLibraries compares types of two versions of the library type by type.
+// Types are matched by name, the libraries must be unwrapped.
+func DiffLibraries(oldLib, newLib *Library) []Change {
+	d := newDiffer()
+	for pair := oldLib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		name, oldType := pair.Key, pair.Value
+		newType, ok := newLib.Types.Get(name)
+		if !ok {
+			d.report(ChangeTypeRemoved, name, true, true, "type removed")
+			continue
+		}
+		d.diff(oldType, newType, name)
+	}
+	for pair := newLib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := oldLib.Types.Get(pair.Key); !ok {
+			d.report(ChangeTypeAdded, pair.Key, false, false, "type added")
+		}
+	}
+	return d.changes
+}
+
+// DiffShapes compares two versions of the type. The path is used as a prefix of change paths.
+// The shapes must be unwrapped.
+func DiffShapes(oldShape, newShape *BaseShape, path string) []Change {
+	d := newDiffer()
+	d.diff(oldShape, newShape, path)
+	return d.changes
+}
+
+type differ struct {
+	// inProgress prevents infinite recursion on recursive shapes.
+	inProgress map[[2]int64]struct{}
+	changes    []Change
+}
+
+func newDiffer() *differ {
+	return &differ{inProgress: make(map[[2]int64]struct{})}
+}
+
+func (d *differ) report(kind ChangeKind, path string, producers, consumers bool, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Kind:            kind,
+		Path:            path,
+		Message:         fmt.Sprintf(format, args...),
+		BreaksProducers: producers,
+		BreaksConsumers: consumers,
+	})
+}
+
+func (d *differ) diff(oldBase, newBase *BaseShape, path string) {
+	if rs, ok := oldBase.Shape.(*RecursiveShape); ok {
+		oldBase = rs.Head
+	}
+	if rs, ok := newBase.Shape.(*RecursiveShape); ok {
+		newBase = rs.Head
+	}
+	key := [2]int64{oldBase.ID, newBase.ID}
+	if _, ok := d.inProgress[key]; ok {
+		return
+	}
+	d.inProgress[key] = struct{}{}
+	defer delete(d.inProgress, key)
+
+	oldUnion, isOldUnion := oldBase.Shape.(*UnionShape)
+	newUnion, isNewUnion := newBase.Shape.(*UnionShape)
+	if isOldUnion || isNewUnion {
+		// A type may become a union member or a union may shrink to a single type, e.g. "nil | string".
+		d.diffUnionMembers(unionMembers(oldBase, oldUnion, isOldUnion),
+			unionMembers(newBase, newUnion, isNewUnion), path)
+		if isOldUnion && isNewUnion {
+			d.diffEnum(oldUnion.Enum, newUnion.Enum, path)
+		}
+		return
+	}
+
+	switch oldShape := oldBase.Shape.(type) {
+	case *ObjectShape:
+		if newShape, ok := newBase.Shape.(*ObjectShape); ok {
+			d.diffObject(oldBase, oldShape, newBase, newShape, path)
+			return
+		}
+	case *ArrayShape:
+		if newShape, ok := newBase.Shape.(*ArrayShape); ok {
+			d.diffArray(oldShape, newShape, path)
+			return
+		}
+	case *StringShape:
+		if newShape, ok := newBase.Shape.(*StringShape); ok {
+			d.diffLength(oldShape.LengthFacets, newShape.LengthFacets, path)
+			d.diffPattern(oldShape, newShape, path)
+			d.diffEnum(oldShape.Enum, newShape.Enum, path)
+			return
+		}
+	case *IntegerShape:
+		switch newShape := newBase.Shape.(type) {
+		case *IntegerShape:
+			d.diffInteger(oldShape, newShape, path)
+			return
+		case *NumberShape:
+			d.report(ChangeTypeChanged, path, false, true, "type changed from integer to number")
+			return
+		}
+	case *NumberShape:
+		switch newShape := newBase.Shape.(type) {
+		case *NumberShape:
+			d.diffNumber(oldShape, newShape, path)
+			return
+		case *IntegerShape:
+			d.report(ChangeTypeChanged, path, true, false, "type changed from number to integer")
+			return
+		}
+	case *BooleanShape:
+		if newShape, ok := newBase.Shape.(*BooleanShape); ok {
+			d.diffEnum(oldShape.Enum, newShape.Enum, path)
+			return
+		}
+	case *FileShape:
+		if newShape, ok := newBase.Shape.(*FileShape); ok {
+			d.diffLength(oldShape.LengthFacets, newShape.LengthFacets, path)
+			d.diffEnumFacet("fileTypes", oldShape.FileTypes, newShape.FileTypes, path)
+			return
+		}
+	case *DateTimeShape:
+		if newShape, ok := newBase.Shape.(*DateTimeShape); ok {
+			oldFormat, newFormat := dateTimeFormat(oldShape.Format), dateTimeFormat(newShape.Format)
+			if oldFormat != newFormat {
+				d.report(ChangeFacetChanged, path, true, true, "format changed from %s to %s", oldFormat, newFormat)
+			}
+			return
+		}
+	case *AnyShape:
+		if _, ok := newBase.Shape.(*AnyShape); !ok {
+			d.report(ChangeTypeChanged, path, true, false, "type changed from any to %s", shapeKind(newBase.Shape))
+		}
+		return
+	}
+	if _, ok := newBase.Shape.(*AnyShape); ok {
+		d.report(ChangeTypeChanged, path, false, true, "type changed from %s to any", shapeKind(oldBase.Shape))
+		return
+	}
+	// NOTE: Shapes are compared by kind since type labels may differ for the same kind, e.g. named types.
+	if oldKind, newKind := shapeKind(oldBase.Shape), shapeKind(newBase.Shape); oldKind != newKind {
+		d.report(ChangeTypeChanged, path, true, true, "type changed from %s to %s", oldKind, newKind)
+	}
+}
+
+func dateTimeFormat(format *string) string {
+	if format == nil {
+		return DateTimeFormatRFC3339
+	}
+	return *format
+}
+
+func (d *differ) diffObject(oldBase *BaseShape, oldShape *ObjectShape, newBase *BaseShape, newShape *ObjectShape,
+	path string,
+) {
+	oldClosed := oldShape.AdditionalProperties != nil && !*oldShape.AdditionalProperties
+	newClosed := newShape.AdditionalProperties != nil && !*newShape.AdditionalProperties
+	switch {
+	case !oldClosed && newClosed:
+		d.report(ChangeAdditionalPropertiesChanged, path, true, false, "additional properties are no longer allowed")
+	case oldClosed && !newClosed:
+		d.report(ChangeAdditionalPropertiesChanged, path, false, true, "additional properties are allowed")
+	}
+
+	for pair := oldShape.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		name, oldProp := pair.Key, pair.Value
+		propPath := path + "." + name
+		newProp, ok := newShape.Properties.Get(name)
+		if !ok {
+			// Producers may still send the property, it is accepted only as an additional property.
+			breaksProducers := newClosed || newShape.findPropertyShape(name) != nil
+			d.report(ChangePropertyRemoved, propPath, breaksProducers, oldProp.Required, "property removed")
+			continue
+		}
+		switch {
+		case !oldProp.Required && newProp.Required:
+			d.report(ChangePropertyRequired, propPath, true, false, "property became required")
+		case oldProp.Required && !newProp.Required:
+			d.report(ChangePropertyOptional, propPath, false, true, "property became optional")
+		}
+		d.diff(oldProp.Base, newProp.Base, propPath)
+	}
+	for pair := newShape.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		name, newProp := pair.Key, pair.Value
+		if _, ok := oldShape.Properties.Get(name); ok {
+			continue
+		}
+		consumers := oldClosed || oldShape.findPropertyShape(name) != nil
+		if newProp.Required {
+			d.report(ChangePropertyAdded, path+"."+name, true, consumers, "required property added")
+		} else {
+			d.report(ChangePropertyAdded, path+"."+name, false, consumers, "optional property added")
+		}
+	}
+
+	for pair := oldShape.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+		pattern := pair.Key
+		newProp, ok := newShape.PatternProperties.Get(pattern)
+		if !ok {
+			d.report(ChangePropertyRemoved, path+"."+pattern, newClosed, false, "pattern property removed")
+			continue
+		}
+		d.diff(pair.Value.Base, newProp.Base, path+"."+pattern)
+	}
+	for pair := newShape.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := oldShape.PatternProperties.Get(pair.Key); !ok {
+			d.report(ChangePropertyAdded, path+"."+pair.Key, !newClosed, oldClosed, "pattern property added")
+		}
+	}
+
+	d.diffBounds("Properties", uintBound(oldShape.MinProperties), uintBound(oldShape.MaxProperties),
+		uintBound(newShape.MinProperties), uintBound(newShape.MaxProperties), path)
+
+	oldDiscriminator, newDiscriminator := "", ""
+	if oldShape.Discriminator != nil {
+		oldDiscriminator = *oldShape.Discriminator
+	}
+	if newShape.Discriminator != nil {
+		newDiscriminator = *newShape.Discriminator
+	}
+	if oldDiscriminator != newDiscriminator {
+		d.report(ChangeFacetChanged, path, true, true, "discriminator changed from %q to %q",
+			oldDiscriminator, newDiscriminator)
+	} else if oldDiscriminator != "" {
+		oldValue, newValue := discriminatorValueOf(oldBase, oldShape), discriminatorValueOf(newBase, newShape)
+		if fmt.Sprint(oldValue) != fmt.Sprint(newValue) {
+			d.report(ChangeFacetChanged, path, true, true, "discriminator value changed from %v to %v",
+				oldValue, newValue)
+		}
+	}
+}
+
+func (d *differ) diffArray(oldShape, newShape *ArrayShape, path string) {
+	d.diffBounds("Items", uintBound(oldShape.MinItems), uintBound(oldShape.MaxItems),
+		uintBound(newShape.MinItems), uintBound(newShape.MaxItems), path)
+	oldUnique := oldShape.UniqueItems != nil && *oldShape.UniqueItems
+	newUnique := newShape.UniqueItems != nil && *newShape.UniqueItems
+	switch {
+	case !oldUnique && newUnique:
+		d.report(ChangeFacetTightened, path, true, false, "items must be unique")
+	case oldUnique && !newUnique:
+		d.report(ChangeFacetRelaxed, path, false, true, "items are no longer required to be unique")
+	}
+	switch {
+	case oldShape.Items != nil && newShape.Items != nil:
+		d.diff(oldShape.Items, newShape.Items, path+"[]")
+	case oldShape.Items == nil && newShape.Items != nil:
+		d.report(ChangeFacetTightened, path+"[]", true, false, "items type declared")
+	case oldShape.Items != nil && newShape.Items == nil:
+		d.report(ChangeFacetRelaxed, path+"[]", false, true, "items type removed")
+	}
+}
+
+func (d *differ) diffInteger(oldShape, newShape *IntegerShape, path string) {
+	d.diffBounds("", intBound(oldShape.Minimum), intBound(oldShape.Maximum),
+		intBound(newShape.Minimum), intBound(newShape.Maximum), path)
+	d.diffMultipleOf(oldShape.MultipleOf, newShape.MultipleOf, path)
+	oldSize, newSize := integerFormatSize(oldShape.Format), integerFormatSize(newShape.Format)
+	switch {
+	case newSize < oldSize:
+		d.report(ChangeFacetTightened, path, true, false, "format narrowed to %s", *newShape.Format)
+	case newSize > oldSize:
+		d.report(ChangeFacetRelaxed, path, false, true, "format widened from %s", *oldShape.Format)
+	}
+	d.diffEnum(oldShape.Enum, newShape.Enum, path)
+}
+
+// integerFormatSize returns the size order of the integer format. Unknown and missing formats are the widest.
+func integerFormatSize(format *string) int8 {
+	if format != nil {
+		if size, ok := SetOfIntegerFormats[*format]; ok {
+			return size
+		}
+	}
+	return int8(len(SetOfIntegerFormats))
+}
+
+func (d *differ) diffNumber(oldShape, newShape *NumberShape, path string) {
+	d.diffBounds("", floatBound(oldShape.Minimum), floatBound(oldShape.Maximum),
+		floatBound(newShape.Minimum), floatBound(newShape.Maximum), path)
+	d.diffMultipleOf(oldShape.MultipleOf, newShape.MultipleOf, path)
+	oldFormat, newFormat := "", ""
+	if oldShape.Format != nil {
+		oldFormat = *oldShape.Format
+	}
+	if newShape.Format != nil {
+		newFormat = *newShape.Format
+	}
+	switch {
+	case oldFormat == newFormat:
+	case newFormat == "float":
+		d.report(ChangeFacetTightened, path, true, false, "format narrowed to float")
+	case oldFormat == "float":
+		d.report(ChangeFacetRelaxed, path, false, true, "format widened from float")
+	}
+	d.diffEnum(oldShape.Enum, newShape.Enum, path)
+}
+
+func (d *differ) diffMultipleOf(oldValue, newValue *float64, path string) {
+	switch {
+	case oldValue == nil && newValue == nil:
+	case oldValue == nil:
+		d.report(ChangeFacetTightened, path, true, false, "multipleOf %v added", *newValue)
+	case newValue == nil:
+		d.report(ChangeFacetRelaxed, path, false, true, "multipleOf %v removed", *oldValue)
+	case *oldValue != *newValue:
+		// Values of one type are valid for the other one only if its multipleOf is a multiple of the other's.
+		d.report(ChangeFacetChanged, path, !isMultiple(*oldValue, *newValue), !isMultiple(*newValue, *oldValue),
+			"multipleOf changed from %v to %v", *oldValue, *newValue)
+	}
+}
+
+func isMultiple(a, b float64) bool {
+	q := a / b
+	return q == float64(int64(q))
+}
+
+func (d *differ) diffLength(oldFacets, newFacets LengthFacets, path string) {
+	d.diffBounds("Length", uintBound(oldFacets.MinLength), uintBound(oldFacets.MaxLength),
+		uintBound(newFacets.MinLength), uintBound(newFacets.MaxLength), path)
+}
+
+func (d *differ) diffPattern(oldShape, newShape *StringShape, path string) {
+	switch {
+	case oldShape.Pattern == nil && newShape.Pattern == nil:
+	case oldShape.Pattern == nil:
+		d.report(ChangeFacetTightened, path, true, false, "pattern %s added", newShape.Pattern.String())
+	case newShape.Pattern == nil:
+		d.report(ChangeFacetRelaxed, path, false, true, "pattern %s removed", oldShape.Pattern.String())
+	case oldShape.Pattern.String() != newShape.Pattern.String():
+		d.report(ChangeFacetChanged, path, true, true, "pattern changed from %s to %s",
+			oldShape.Pattern.String(), newShape.Pattern.String())
+	}
+}
+
+// diffBounds compares minimum and maximum facets. The suffix is appended to "min" and "max" to get facet names,
+// e.g. "Length" for "minLength".
+func (d *differ) diffBounds(suffix string, oldMin, oldMax, newMin, newMax *big.Float, path string) {
+	minName, maxName := "min"+suffix, "max"+suffix
+	if suffix == "" {
+		minName, maxName = "minimum", "maximum"
+	}
+	// Raising a lower bound narrows the type, lowering it widens the type.
+	d.diffBound(minName, oldMin, newMin, 1, path)
+	d.diffBound(maxName, oldMax, newMax, -1, path)
+}
+
+// diffBound compares the bound facet. The direction is 1 for lower bounds and -1 for upper bounds.
+func (d *differ) diffBound(facet string, oldValue, newValue *big.Float, direction int, path string) {
+	switch {
+	case oldValue == nil && newValue == nil:
+	case oldValue == nil:
+		d.report(ChangeFacetTightened, path, true, false, "%s %s added", facet, newValue.String())
+	case newValue == nil:
+		d.report(ChangeFacetRelaxed, path, false, true, "%s %s removed", facet, oldValue.String())
+	default:
+		switch newValue.Cmp(oldValue) * direction {
+		case 1:
+			d.report(ChangeFacetTightened, path, true, false, "%s changed from %s to %s",
+				facet, oldValue.String(), newValue.String())
+		case -1:
+			d.report(ChangeFacetRelaxed, path, false, true, "%s changed from %s to %s",
+				facet, oldValue.String(), newValue.String())
+		}
+	}
+}
+
+func uintBound(v *uint64) *big.Float {
+	if v == nil {
+		return nil
+	}
+	return new(big.Float).SetUint64(*v)
+}
+
+func intBound(v *big.Int) *big.Float {
+	if v == nil {
+		return nil
+	}
+	return new(big.Float).SetInt(v)
+}
+
+func floatBound(v *float64) *big.Float {
+	if v == nil {
+		return nil
+	}
+	return big.NewFloat(*v)
+}
+
+func (d *differ) diffEnum(oldEnum, newEnum Nodes, path string) {
+	d.diffEnumFacet("enum", oldEnum, newEnum, path)
+}
+
+func (d *differ) diffEnumFacet(facet string, oldEnum, newEnum Nodes, path string) {
+	switch {
+	case oldEnum == nil && newEnum == nil:
+		return
+	case oldEnum == nil:
+		d.report(ChangeFacetTightened, path, true, false, "%s added", facet)
+		return
+	case newEnum == nil:
+		d.report(ChangeFacetRelaxed, path, false, true, "%s removed", facet)
+		return
+	}
+	oldValues, newValues := enumValues(oldEnum), enumValues(newEnum)
+	for _, v := range sortedKeys(oldValues) {
+		if _, ok := newValues[v]; !ok {
+			d.report(ChangeEnumValueRemoved, path, true, false, "%s value %s removed", facet, v)
+		}
+	}
+	for _, v := range sortedKeys(newValues) {
+		if _, ok := oldValues[v]; !ok {
+			d.report(ChangeEnumValueAdded, path, false, true, "%s value %s added", facet, v)
+		}
+	}
+}
+
+func enumValues(enum Nodes) map[string]struct{} {
+	values := make(map[string]struct{}, len(enum))
+	for _, n := range enum {
+		values[fmt.Sprintf("%#v", n.Value)] = struct{}{}
+	}
+	return values
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unionMembers returns union members keyed by their type. A shape that is not a union is its only member.
+func unionMembers(base *BaseShape, union *UnionShape, isUnion bool) map[string]*BaseShape {
+	if !isUnion {
+		return map[string]*BaseShape{unionMemberKey(base): base}
+	}
+	members := make(map[string]*BaseShape, len(union.AnyOf))
+	for _, member := range union.AnyOf {
+		key := unionMemberKey(member)
+		// Anonymous members of the same type are distinguished by their order.
+		for i := 2; members[key] != nil; i++ {
+			key = unionMemberKey(member) + "#" + strconv.Itoa(i)
+		}
+		members[key] = member
+	}
+	return members
+}
+
+func unionMemberKey(base *BaseShape) string {
+	if rs, ok := base.Shape.(*RecursiveShape); ok {
+		base = rs.Head
+	}
+	if base.TypeLabel != "" {
+		return base.TypeLabel
+	}
+	return base.Type
+}
+
+func (d *differ) diffUnionMembers(oldMembers, newMembers map[string]*BaseShape, path string) {
+	for _, key := range sortedKeys(oldMembers) {
+		newMember, ok := newMembers[key]
+		if !ok {
+			d.report(ChangeUnionMemberRemoved, path, true, false, "union member %s removed", key)
+			continue
+		}
+		memberPath := path
+		if len(oldMembers) > 1 || len(newMembers) > 1 {
+			memberPath = path + "(" + key + ")"
+		}
+		d.diff(oldMembers[key], newMember, memberPath)
+	}
+	for _, key := range sortedKeys(newMembers) {
+		if _, ok := oldMembers[key]; !ok {
+			d.report(ChangeUnionMemberAdded, path, false, true, "union member %s added", key)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLibraries(t *testing.T) {
+	oldContent := `#%RAML 1.0 Library
+types:
+  Status:
+    enum: [active, disabled, deleted]
+  Order:
+    additionalProperties: true
+    properties:
+      id: integer
+      name:
+        type: string
+        maxLength: 100
+      note?: string
+      legacy: string
+      count:
+        type: integer
+        format: int64
+      status: Status
+      tags: string[]
+      owner: nil | string
+      created: datetime
+  Pet: Cat | Dog
+  Cat:
+    properties:
+      lives: integer
+  Dog:
+    properties:
+      good: boolean
+  Node:
+    properties:
+      next?: Node
+  Removed: string
+`
+	newContent := `#%RAML 1.0 Library
+types:
+  Status:
+    enum: [active, disabled, archived]
+  Order:
+    additionalProperties: false
+    properties:
+      id: number
+      name:
+        type: string
+        maxLength: 50
+        minLength: 1
+      note: string
+      count:
+        type: integer
+        format: int32
+      status: Status
+      tags:
+        type: string[]
+        uniqueItems: true
+      owner: string
+      created: date-only
+      extra?: string
+  Pet: Cat
+  Cat:
+    properties:
+      lives: integer
+  Dog:
+    properties:
+      good: boolean
+  Node:
+    properties:
+      next?: Node
+      value?: string
+  Added: string
+`
+	parse := func(content string) *Library {
+		r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+		require.NoError(t, err)
+		return r.EntryPoint().(*Library)
+	}
+	changes := DiffLibraries(parse(oldContent), parse(newContent))
+
+	type result struct {
+		kind      ChangeKind
+		producers bool
+		consumers bool
+	}
+	got := make(map[string]result, len(changes))
+	for _, c := range changes {
+		got[c.String()] = result{kind: c.Kind, producers: c.BreaksProducers, consumers: c.BreaksConsumers}
+	}
+	want := map[string]result{
+		`Status: enum value "deleted" removed`:                   {ChangeEnumValueRemoved, true, false},
+		`Status: enum value "archived" added`:                    {ChangeEnumValueAdded, false, true},
+		`Order: additional properties are no longer allowed`:     {ChangeAdditionalPropertiesChanged, true, false},
+		`Order.id: type changed from integer to number`:          {ChangeTypeChanged, false, true},
+		`Order.name: maxLength changed from 100 to 50`:           {ChangeFacetTightened, true, false},
+		`Order.name: minLength 1 added`:                          {ChangeFacetTightened, true, false},
+		`Order.note: property became required`:                   {ChangePropertyRequired, true, false},
+		`Order.legacy: property removed`:                         {ChangePropertyRemoved, true, true},
+		`Order.count: format narrowed to int32`:                  {ChangeFacetTightened, true, false},
+		`Order.status: enum value "deleted" removed`:             {ChangeEnumValueRemoved, true, false},
+		`Order.status: enum value "archived" added`:              {ChangeEnumValueAdded, false, true},
+		`Order.tags: items must be unique`:                       {ChangeFacetTightened, true, false},
+		`Order.owner: union member nil removed`:                  {ChangeUnionMemberRemoved, true, false},
+		`Order.created: type changed from datetime to date-only`: {ChangeTypeChanged, true, true},
+		`Order.extra: optional property added`:                   {ChangePropertyAdded, false, false},
+		`Pet: union member Dog removed`:                          {ChangeUnionMemberRemoved, true, false},
+		`Node.next.value: optional property added`:               {ChangePropertyAdded, false, false},
+		`Node.value: optional property added`:                    {ChangePropertyAdded, false, false},
+		`Removed: type removed`:                                  {ChangeTypeRemoved, true, true},
+		`Added: type added`:                                      {ChangeTypeAdded, false, false},
+	}
+	require.Equal(t, want, got)
+}
+
+func TestDiffShapes_Bounds(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  A:
+    type: number
+    minimum: 1
+    maximum: 10
+    multipleOf: 2
+  B:
+    type: number
+    minimum: 0
+    maximum: 20
+    multipleOf: 4
+  C:
+    type: array
+    items: string
+    maxItems: 5
+  D:
+    type: array
+    items: integer
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+	get := func(name string) *BaseShape {
+		s, _ := lib.Types.Get(name)
+		return s
+	}
+
+	changes := DiffShapes(get("A"), get("B"), "$")
+	require.Len(t, changes, 3)
+	require.Equal(t, "$: minimum changed from 1 to 0", changes[0].String())
+	require.False(t, changes[0].BreaksProducers)
+	require.True(t, changes[0].BreaksConsumers)
+	require.Equal(t, "$: maximum changed from 10 to 20", changes[1].String())
+	// Multiples of 4 are multiples of 2, but not vice versa.
+	require.Equal(t, "$: multipleOf changed from 2 to 4", changes[2].String())
+	require.True(t, changes[2].BreaksProducers)
+	require.False(t, changes[2].BreaksConsumers)
+
+	changes = DiffShapes(get("C"), get("D"), "$")
+	require.Len(t, changes, 2)
+	require.Equal(t, "$: maxItems 5 removed", changes[0].String())
+	require.Equal(t, "$[]: type changed from string to integer", changes[1].String())
+	require.True(t, changes[1].Breaking())
+
+	require.Empty(t, DiffShapes(get("A"), get("A"), "$"))
+}
+
+func TestDiffShapes_Kinds(t *testing.T) {
+	shape := func(label string, kind func(base *BaseShape) Shape) *BaseShape {
+		base := &BaseShape{Type: label}
+		base.Shape = kind(base)
+		return base
+	}
+	nilShape := func(base *BaseShape) Shape { return &NilShape{BaseShape: base} }
+	dateOnly := func(base *BaseShape) Shape { return &DateOnlyShape{BaseShape: base} }
+	timeOnly := func(base *BaseShape) Shape { return &TimeOnlyShape{BaseShape: base} }
+	jsonShape := func(base *BaseShape) Shape { return &JSONShape{BaseShape: base} }
+
+	tests := []struct {
+		name     string
+		old, new *BaseShape
+		want     []string
+	}{
+		{name: "nil", old: shape("nil", nilShape), new: shape("Nothing", nilShape)},
+		{name: "date-only", old: shape("date-only", dateOnly), new: shape("Day", dateOnly)},
+		{name: "json", old: shape("json", jsonShape), new: shape("Schema", jsonShape)},
+		{
+			name: "different kinds", old: shape("Day", dateOnly), new: shape("Day", timeOnly),
+			want: []string{"$: type changed from date-only to time-only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range DiffShapes(tt.old, tt.new, "$") {
+				got = append(got, c.String())
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
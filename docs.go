@@ -0,0 +1,657 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"math/big"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// DocsFormat is the output format of the documentation.
+type DocsFormat string
+
+const (
+	DocsFormatHTML     DocsFormat = "html"
+	DocsFormatMarkdown DocsFormat = "markdown"
+)
+
+// DocsSearchIndexFile is the name of the search index file of the generated documentation.
+const DocsSearchIndexFile = "search-index.json"
+
+type DocsGeneratorOptions struct {
+	format    DocsFormat
+	templates fs.FS
+	patterns  []string
+}
+
+type DocsGeneratorOpt interface {
+	apply(*DocsGeneratorOptions)
+}
+
+type optDocsFormat struct{ format DocsFormat }
+
+func (o optDocsFormat) apply(c *DocsGeneratorOptions) { c.format = o.format }
+
+// WithDocsFormat sets the output format of the documentation, HTML by default.
+func WithDocsFormat(format DocsFormat) DocsGeneratorOpt {
+	return optDocsFormat{format: format}
+}
+
+type optDocsTemplates struct {
+	fsys     fs.FS
+	patterns []string
+}
+
+func (o optDocsTemplates) apply(c *DocsGeneratorOptions) {
+	c.templates = o.fsys
+	c.patterns = o.patterns
+}
+
+// WithDocsTemplates overrides the default templates with the templates matched by the patterns in the file system.
+// The templates are parsed on top of the default ones, so it is enough to redefine the templates that need to be
+// changed: "index", "library" and "type" render the pages, "style" renders the stylesheet of the HTML site.
+// HTML templates are parsed with html/template and Markdown templates are parsed with text/template.
+func WithDocsTemplates(fsys fs.FS, patterns ...string) DocsGeneratorOpt {
+	return optDocsTemplates{fsys: fsys, patterns: patterns}
+}
+
+// DocsLink is a link to a page of the documentation.
+type DocsLink struct {
+	Name string `json:"name"`
+	// URL is the path to the page relative to the root of the documentation.
+	URL string `json:"url"`
+}
+
+// DocsTypeRef is the type expression of a shape with links to the named types it refers to.
+type DocsTypeRef struct {
+	Label string
+	Links []DocsLink
+}
+
+// DocsProperty is a property of an object type.
+type DocsProperty struct {
+	Name        string
+	Required    bool
+	Type        DocsTypeRef
+	Description string
+	// Origin is the ancestor type the property is inherited from, nil for the properties declared by the type.
+	Origin *DocsLink
+}
+
+// DocsValue is a named value, e.g. a facet, an annotation or an example.
+type DocsValue struct {
+	Name  string
+	Value string
+}
+
+// DocsType is the data of a type page.
+type DocsType struct {
+	Name        string
+	DisplayName string
+	Description string
+	Kind        string
+	URL         string
+	Library     *DocsLibrary
+
+	AliasOf      *DocsTypeRef
+	Parents      []DocsLink
+	Properties   []DocsProperty
+	Items        *DocsTypeRef
+	Members      []DocsTypeRef
+	Facets       []DocsValue
+	CustomFacets []DocsValue
+	Annotations  []DocsValue
+	Examples     []DocsValue
+}
+
+// DocsUse is an entry of the "uses" section of a library.
+type DocsUse struct {
+	Alias   string
+	Library DocsLink
+}
+
+// DocsLibrary is the data of a library page.
+type DocsLibrary struct {
+	Name        string
+	Usage       string
+	URL         string
+	Location    string
+	Uses        []DocsUse
+	Types       []*DocsType
+	Annotations []DocsValue
+
+	lib *Library
+}
+
+// DocsSearchEntry is an entry of the search index.
+type DocsSearchEntry struct {
+	Name        string `json:"name"`
+	Library     string `json:"library"`
+	Kind        string `json:"kind"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// DocsPage is the data passed to the page templates.
+type DocsPage struct {
+	// Root is the relative path from the page to the root of the documentation, e.g. "../".
+	Root      string
+	Title     string
+	Libraries []*DocsLibrary
+	Library   *DocsLibrary
+	Type      *DocsType
+	Search    []DocsSearchEntry
+}
+
+// DocsGenerator renders the documentation of the unwrapped types of a RAML library and the libraries it uses.
+//
+// The documentation contains an index page, a page per library and a page per type, and a JSON search index.
+// Type pages list the properties with the inherited ones marked by the origin type, the facet constraints,
+// examples, annotation and custom facet values. References to named types and "uses" aliases are cross-linked.
+type DocsGenerator struct {
+	opts DocsGeneratorOptions
+
+	libraries []*DocsLibrary
+	byLib     map[string]*DocsLibrary
+	byShape   map[shapeKey]*DocsType
+	resolver  *namedTypeResolver
+}
+
+func NewDocsGenerator(opt ...DocsGeneratorOpt) *DocsGenerator {
+	opts := DocsGeneratorOptions{format: DocsFormatHTML}
+	for _, o := range opt {
+		o.apply(&opts)
+	}
+	return &DocsGenerator{opts: opts}
+}
+
+// docsRenderer executes a named template, implemented by both html/template and text/template.
+type docsRenderer interface {
+	ExecuteTemplate(w *bytes.Buffer, name string, data any) error
+}
+
+type htmlRenderer struct{ t *htmltemplate.Template }
+
+func (r htmlRenderer) ExecuteTemplate(w *bytes.Buffer, name string, data any) error {
+	return r.t.ExecuteTemplate(w, name, data)
+}
+
+type textRenderer struct{ t *texttemplate.Template }
+
+func (r textRenderer) ExecuteTemplate(w *bytes.Buffer, name string, data any) error {
+	return r.t.ExecuteTemplate(w, name, data)
+}
+
+func (g *DocsGenerator) renderer() (docsRenderer, string, error) {
+	funcs := map[string]any{
+		"dict": docsDict,
+		"md":   docsMarkdownCell,
+	}
+	switch g.opts.format {
+	case DocsFormatHTML:
+		t, err := htmltemplate.New("docs").Funcs(funcs).Parse(docsHTMLTemplates)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse default templates: %w", err)
+		}
+		if g.opts.templates != nil {
+			if t, err = t.ParseFS(g.opts.templates, g.opts.patterns...); err != nil {
+				return nil, "", fmt.Errorf("parse templates: %w", err)
+			}
+		}
+		return htmlRenderer{t: t}, ".html", nil
+	case DocsFormatMarkdown:
+		t, err := texttemplate.New("docs").Funcs(funcs).Parse(docsMarkdownTemplates)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse default templates: %w", err)
+		}
+		if g.opts.templates != nil {
+			if t, err = t.ParseFS(g.opts.templates, g.opts.patterns...); err != nil {
+				return nil, "", fmt.Errorf("parse templates: %w", err)
+			}
+		}
+		return textRenderer{t: t}, ".md", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported docs format: %s", g.opts.format)
+	}
+}
+
+// Generate renders the documentation of the library. It returns the content of the files by the paths relative
+// to the root of the documentation. The library must be unwrapped.
+func (g *DocsGenerator) Generate(lib *Library) (map[string][]byte, error) {
+	r, ext, err := g.renderer()
+	if err != nil {
+		return nil, err
+	}
+	g.libraries = nil
+	g.byLib = make(map[string]*DocsLibrary)
+	g.byShape = make(map[shapeKey]*DocsType)
+	g.resolver = newNamedTypeResolver(lib)
+	g.collect(lib, filepath.Dir(lib.Location), ext)
+	for _, l := range g.libraries {
+		g.describeLibrary(l)
+	}
+
+	var search []DocsSearchEntry
+	for _, l := range g.libraries {
+		for _, t := range l.Types {
+			search = append(search, DocsSearchEntry{
+				Name: t.Name, Library: l.Name, Kind: t.Kind, Description: t.Description, URL: t.URL,
+			})
+		}
+	}
+	if search == nil {
+		search = []DocsSearchEntry{}
+	}
+
+	files := make(map[string][]byte)
+	render := func(file, name string, page DocsPage) error {
+		page.Root = strings.Repeat("../", strings.Count(file, "/"))
+		page.Libraries = g.libraries
+		var buf bytes.Buffer
+		if errExec := r.ExecuteTemplate(&buf, name, page); errExec != nil {
+			return fmt.Errorf("render %s: %w", file, errExec)
+		}
+		files[file] = buf.Bytes()
+		return nil
+	}
+	if err = render("index"+ext, "index", DocsPage{Title: docsTitle(lib), Search: search}); err != nil {
+		return nil, err
+	}
+	for _, l := range g.libraries {
+		if err = render(l.URL, "library", DocsPage{Title: l.Name, Library: l}); err != nil {
+			return nil, err
+		}
+		for _, t := range l.Types {
+			if err = render(t.URL, "type", DocsPage{Title: t.Name, Library: l, Type: t}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	index, err := json.MarshalIndent(search, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal search index: %w", err)
+	}
+	files[DocsSearchIndexFile] = append(index, '\n')
+	return files, nil
+}
+
+// docsDict builds a map from the key and value pairs to pass several values to a template.
+func docsDict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict: odd number of arguments")
+	}
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key %v is not a string", pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+	return m, nil
+}
+
+// docsMarkdownCell escapes the text to be placed in a cell of a Markdown table.
+func docsMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func docsTitle(lib *Library) string {
+	name := filepath.Base(lib.Location)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// collect registers the pages of the library and the libraries it uses.
+func (g *DocsGenerator) collect(lib *Library, baseDir string, ext string) {
+	if lib == nil {
+		return
+	}
+	if _, ok := g.byLib[lib.Location]; ok {
+		return
+	}
+	name := lib.Location
+	if rel, err := filepath.Rel(baseDir, lib.Location); err == nil {
+		name = filepath.ToSlash(rel)
+	}
+	name = strings.TrimSuffix(name, path.Ext(name))
+	dir := strings.ReplaceAll(name, "../", "_/")
+	l := &DocsLibrary{Name: name, Usage: lib.Usage, URL: dir + "/index" + ext, Location: lib.Location, lib: lib}
+	g.byLib[lib.Location] = l
+	g.libraries = append(g.libraries, l)
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		t := &DocsType{Name: pair.Key, Kind: shapeKind(pair.Value.Shape), URL: dir + "/" + pair.Key + ext, Library: l}
+		l.Types = append(l.Types, t)
+		g.byShape[keyOf(pair.Value)] = t
+	}
+	if lib.Uses == nil {
+		return
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		g.collect(pair.Value.Link, baseDir, ext)
+	}
+}
+
+func (g *DocsGenerator) describeLibrary(l *DocsLibrary) {
+	lib := l.lib
+	if lib.Uses != nil {
+		for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+			if used, ok := g.byLib[pair.Value.Link.Location]; ok {
+				l.Uses = append(l.Uses, DocsUse{Alias: pair.Key, Library: DocsLink{Name: used.Name, URL: used.URL}})
+			}
+		}
+	}
+	l.Annotations = docsAnnotations(lib.CustomDomainProperties)
+	i := 0
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		g.describeType(l.Types[i], pair.Value)
+		i++
+	}
+}
+
+func (g *DocsGenerator) describeType(t *DocsType, base *BaseShape) {
+	if base.DisplayName != nil && *base.DisplayName != t.Name {
+		t.DisplayName = *base.DisplayName
+	}
+	if base.Description != nil {
+		t.Description = *base.Description
+	}
+	if ref := referencedNamedType(base); ref != nil {
+		aliasOf := g.typeRef(t.Library, ref)
+		t.AliasOf = &aliasOf
+	}
+	for _, parent := range base.Inherits {
+		if link, ok := g.link(t.Library, parent); ok {
+			t.Parents = append(t.Parents, link)
+		}
+	}
+	switch s := base.Shape.(type) {
+	case *ObjectShape:
+		t.Properties = g.properties(t.Library, base, s)
+	case *ArrayShape:
+		if s.Items != nil {
+			items := g.typeRef(t.Library, s.Items)
+			t.Items = &items
+		}
+	case *UnionShape:
+		for _, member := range s.AnyOf {
+			t.Members = append(t.Members, g.typeRef(t.Library, member))
+		}
+	}
+	t.Facets = docsFacets(base)
+	if base.CustomShapeFacets != nil {
+		for pair := base.CustomShapeFacets.Oldest(); pair != nil; pair = pair.Next() {
+			t.CustomFacets = append(t.CustomFacets, DocsValue{Name: pair.Key, Value: docsJSON(pair.Value.Value)})
+		}
+	}
+	t.Annotations = docsAnnotations(base.CustomDomainProperties)
+	if base.Example != nil && base.Example.Data != nil {
+		t.Examples = append(t.Examples, DocsValue{Name: base.Example.Name, Value: docsJSON(base.Example.Data.Value)})
+	}
+	if base.Examples != nil && base.Examples.Map != nil {
+		for pair := base.Examples.Map.Oldest(); pair != nil; pair = pair.Next() {
+			if pair.Value.Data != nil {
+				t.Examples = append(t.Examples, DocsValue{Name: pair.Key, Value: docsJSON(pair.Value.Data.Value)})
+			}
+		}
+	}
+}
+
+// link returns the link to the page of the named type qualified relative to the library.
+func (g *DocsGenerator) link(from *DocsLibrary, base *BaseShape) (DocsLink, bool) {
+	t, ok := g.byShape[keyOf(base)]
+	if !ok {
+		return DocsLink{}, false
+	}
+	name := t.Name
+	if t.Library != from {
+		name = t.Library.Name + "." + t.Name
+		for _, u := range from.Uses {
+			if u.Library.URL == t.Library.URL {
+				name = u.Alias + "." + t.Name
+				break
+			}
+		}
+	}
+	return DocsLink{Name: name, URL: t.URL}, true
+}
+
+// typeRef returns the type expression of the shape with the links to the named types it refers to.
+func (g *DocsGenerator) typeRef(from *DocsLibrary, base *BaseShape) DocsTypeRef {
+	ref := DocsTypeRef{}
+	seen := make(map[string]struct{})
+	ref.Label = g.label(from, base, func(link DocsLink) {
+		if _, ok := seen[link.URL]; !ok {
+			seen[link.URL] = struct{}{}
+			ref.Links = append(ref.Links, link)
+		}
+	})
+	return ref
+}
+
+// label returns the type expression of the shape. Named types are qualified relative to the library.
+func (g *DocsGenerator) label(from *DocsLibrary, base *BaseShape, addLink func(DocsLink)) string {
+	if rs, ok := base.Shape.(*RecursiveShape); ok {
+		base = rs.Head
+	}
+	if named := g.named(base); named != nil {
+		if link, ok := g.link(from, named); ok {
+			addLink(link)
+			return link.Name
+		}
+	}
+	switch s := base.Shape.(type) {
+	case *ArrayShape:
+		if s.Items != nil {
+			items := g.label(from, s.Items, addLink)
+			if _, isUnion := s.Items.Shape.(*UnionShape); isUnion && g.named(s.Items) == nil {
+				items = "(" + items + ")"
+			}
+			return items + "[]"
+		}
+	case *UnionShape:
+		members := make([]string, 0, len(s.AnyOf))
+		for _, member := range s.AnyOf {
+			members = append(members, g.label(from, member, addLink))
+		}
+		return strings.Join(members, " | ")
+	}
+	return shapeKind(base.Shape)
+}
+
+// named returns the named type the shape refers to, or the shape itself if it is a named type.
+func (g *DocsGenerator) named(base *BaseShape) *BaseShape {
+	if _, ok := g.resolver.declaration(base); ok {
+		return base
+	}
+	if nt, ok := g.resolver.referenced(base); ok {
+		if _, declared := g.resolver.declaration(nt.shape); declared {
+			return nt.shape
+		}
+	}
+	return nil
+}
+
+func (g *DocsGenerator) properties(from *DocsLibrary, base *BaseShape, s *ObjectShape) []DocsProperty {
+	var res []DocsProperty
+	if s.Properties == nil {
+		return res
+	}
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		p := DocsProperty{Name: pair.Key, Required: prop.Required, Type: g.typeRef(from, prop.Base)}
+		if prop.Base.Description != nil {
+			p.Description = *prop.Base.Description
+		}
+		if origin := propertyOrigin(base, pair.Key, prop.Base.ID); origin != nil {
+			if link, ok := g.link(from, origin); ok {
+				p.Origin = &link
+			}
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// propertyOrigin returns the most distant ancestor that declares the same property shape. Unwrapped types share
+// the inherited property shapes with their parents.
+func propertyOrigin(base *BaseShape, name string, id int64) *BaseShape {
+	var origin *BaseShape
+	visited := map[int64]struct{}{base.ID: {}}
+	queue := append([]*BaseShape{}, base.Inherits...)
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[parent.ID]; ok {
+			continue
+		}
+		visited[parent.ID] = struct{}{}
+		s, ok := parent.Shape.(*ObjectShape)
+		if !ok || s.Properties == nil {
+			continue
+		}
+		if prop, found := s.Properties.Get(name); found && prop.Base.ID == id {
+			origin = parent
+			queue = append(queue, parent.Inherits...)
+		}
+	}
+	return origin
+}
+
+// docsAnnotations returns the values of the annotations.
+func docsAnnotations(annotations *orderedmap.OrderedMap[string, *DomainExtension]) []DocsValue {
+	var res []DocsValue
+	if annotations == nil {
+		return res
+	}
+	for pair := annotations.Oldest(); pair != nil; pair = pair.Next() {
+		var value any
+		if pair.Value.Extension != nil {
+			value = pair.Value.Extension.Value
+		}
+		res = append(res, DocsValue{Name: "(" + pair.Key + ")", Value: docsJSON(value)})
+	}
+	return res
+}
+
+// docsFacets returns the facet constraints of the shape.
+func docsFacets(base *BaseShape) []DocsValue {
+	var res []DocsValue
+	add := func(name string, value any) {
+		res = append(res, DocsValue{Name: name, Value: fmt.Sprint(value)})
+	}
+	addUint := func(name string, value *uint64) {
+		if value != nil {
+			add(name, *value)
+		}
+	}
+	addEnum := func(enum Nodes) {
+		if len(enum) == 0 {
+			return
+		}
+		values := make([]string, 0, len(enum))
+		for _, n := range enum {
+			values = append(values, docsJSON(n.Value))
+		}
+		add(FacetEnum, strings.Join(values, ", "))
+	}
+	addFormat := func(format *string) {
+		if format != nil {
+			add(FacetFormat, *format)
+		}
+	}
+	switch s := base.Shape.(type) {
+	case *StringShape:
+		addUint(FacetMinLength, s.MinLength)
+		addUint(FacetMaxLength, s.MaxLength)
+		if s.Pattern != nil {
+			add(FacetPattern, s.Pattern.String())
+		}
+		addEnum(s.Enum)
+	case *IntegerShape:
+		addBig := func(name string, value *big.Int) {
+			if value != nil {
+				add(name, value.String())
+			}
+		}
+		addBig(FacetMinimum, s.Minimum)
+		addBig(FacetMaximum, s.Maximum)
+		if s.MultipleOf != nil {
+			add(FacetMultipleOf, strconv.FormatFloat(*s.MultipleOf, 'g', -1, 64))
+		}
+		addFormat(s.Format)
+		addEnum(s.Enum)
+	case *NumberShape:
+		for _, f := range []struct {
+			name  string
+			value *float64
+		}{{FacetMinimum, s.Minimum}, {FacetMaximum, s.Maximum}, {FacetMultipleOf, s.MultipleOf}} {
+			if f.value != nil {
+				add(f.name, strconv.FormatFloat(*f.value, 'g', -1, 64))
+			}
+		}
+		addFormat(s.Format)
+		addEnum(s.Enum)
+	case *BooleanShape:
+		addEnum(s.Enum)
+	case *FileShape:
+		addUint(FacetMinLength, s.MinLength)
+		addUint(FacetMaxLength, s.MaxLength)
+		if len(s.FileTypes) > 0 {
+			types := make([]string, 0, len(s.FileTypes))
+			for _, n := range s.FileTypes {
+				types = append(types, fmt.Sprint(n.Value))
+			}
+			add(FacetFileTypes, strings.Join(types, ", "))
+		}
+	case *DateTimeShape:
+		addFormat(s.Format)
+	case *ArrayShape:
+		addUint(FacetMinItems, s.MinItems)
+		addUint(FacetMaxItems, s.MaxItems)
+		if s.UniqueItems != nil {
+			add(FacetUniqueItems, *s.UniqueItems)
+		}
+	case *ObjectShape:
+		addUint(FacetMinProperties, s.MinProperties)
+		addUint(FacetMaxProperties, s.MaxProperties)
+		if s.AdditionalProperties != nil {
+			add(FacetAdditionalProperties, *s.AdditionalProperties)
+		}
+		if s.Discriminator != nil {
+			add(FacetDiscriminator, *s.Discriminator)
+		}
+		if s.DiscriminatorValue != nil {
+			add(FacetDiscriminatorValue, docsJSON(s.DiscriminatorValue))
+		}
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				add("pattern property", pair.Key)
+			}
+		}
+	case *UnionShape:
+		addEnum(s.Enum)
+	}
+	if base.Default != nil {
+		add(FacetDefault, docsJSON(base.Default.Value))
+	}
+	return res
+}
+
+// docsJSON renders the value as indented JSON.
+func docsJSON(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,311 @@
+package raml
+
+// docsHTMLTemplates are the default templates of the HTML documentation site.
+const docsHTMLTemplates = `
+{{- define "style" -}}
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 1em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+.inherited { color: #777; }
+{{- end -}}
+
+{{- define "header" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{template "style" .}}</style>
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">Index</a>{{with .Library}} / <a href="{{$.Root}}{{.URL}}">{{.Name}}</a>{{end}}</nav>
+{{- end -}}
+
+{{- define "footer" -}}
+</body>
+</html>
+{{end -}}
+
+{{- define "typeref" -}}
+{{- if and (eq (len .Ref.Links) 1) (eq (index .Ref.Links 0).Name .Ref.Label) -}}
+<a href="{{.Root}}{{(index .Ref.Links 0).URL}}">{{.Ref.Label}}</a>
+{{- else -}}
+<code>{{.Ref.Label}}</code>{{range .Ref.Links}} <a href="{{$.Root}}{{.URL}}">{{.Name}}</a>{{end}}
+{{- end -}}
+{{- end -}}
+
+{{- define "values" -}}
+<table>
+<tr><th>Name</th><th>Value</th></tr>
+{{- range .}}
+<tr><td>{{.Name}}</td><td><code>{{.Value}}</code></td></tr>
+{{- end}}
+</table>
+{{- end -}}
+
+{{- define "index" -}}
+{{template "header" .}}
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search types">
+<ul id="results"></ul>
+{{- range .Libraries}}
+<h2><a href="{{$.Root}}{{.URL}}">{{.Name}}</a></h2>
+{{- with .Usage}}
+<p>{{.}}</p>
+{{- end}}
+<ul>
+{{- range .Types}}
+<li><a href="{{$.Root}}{{.URL}}">{{.Name}}</a>{{with .Description}} – {{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<script>
+const index = {{.Search}};
+const input = document.getElementById("search");
+const results = document.getElementById("results");
+input.addEventListener("input", () => {
+  const query = input.value.toLowerCase();
+  results.replaceChildren();
+  if (!query) {
+    return;
+  }
+  for (const entry of index) {
+    const text = [entry.name, entry.library, entry.description || ""].join(" ").toLowerCase();
+    if (text.includes(query)) {
+      const item = document.createElement("li");
+      const link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.library + "." + entry.name;
+      item.append(link);
+      results.append(item);
+    }
+  }
+});
+</script>
+{{template "footer" .}}
+{{- end -}}
+
+{{- define "library" -}}
+{{template "header" .}}
+{{- with .Library}}
+<h1>{{.Name}}</h1>
+{{- with .Usage}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Uses}}
+<h2>Uses</h2>
+<table>
+<tr><th>Alias</th><th>Library</th></tr>
+{{- range .}}
+<tr><td>{{.Alias}}</td><td><a href="{{$.Root}}{{.Library.URL}}">{{.Library.Name}}</a></td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Annotations}}
+<h2>Annotations</h2>
+{{template "values" .}}
+{{- end}}
+<h2>Types</h2>
+<table>
+<tr><th>Name</th><th>Kind</th><th>Description</th></tr>
+{{- range .Types}}
+<tr><td><a href="{{$.Root}}{{.URL}}">{{.Name}}</a></td><td>{{.Kind}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{template "footer" .}}
+{{- end -}}
+
+{{- define "type" -}}
+{{template "header" .}}
+{{- with .Type}}
+<h1>{{.Name}}</h1>
+{{- with .DisplayName}}
+<p><em>{{.}}</em></p>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+<p>Kind: <code>{{.Kind}}</code></p>
+{{- with .AliasOf}}
+<p>Alias of {{template "typeref" (dict "Root" $.Root "Ref" .)}}</p>
+{{- end}}
+{{- with .Parents}}
+<p>Inherits: {{range $i, $p := .}}{{if $i}}, {{end}}<a href="{{$.Root}}{{$p.URL}}">{{$p.Name}}</a>{{end}}</p>
+{{- end}}
+{{- with .Properties}}
+<h2>Properties</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Required</th><th>Description</th><th>Inherited from</th></tr>
+{{- range .}}
+<tr{{if .Origin}} class="inherited"{{end}}><td>{{.Name}}</td><td>{{template "typeref" (dict "Root" $.Root "Ref" .Type)}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{.Description}}</td><td>{{with .Origin}}<a href="{{$.Root}}{{.URL}}">{{.Name}}</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Items}}
+<h2>Items</h2>
+<p>{{template "typeref" (dict "Root" $.Root "Ref" .)}}</p>
+{{- end}}
+{{- with .Members}}
+<h2>Union members</h2>
+<ul>
+{{- range .}}
+<li>{{template "typeref" (dict "Root" $.Root "Ref" .)}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Facets}}
+<h2>Facets</h2>
+{{template "values" .}}
+{{- end}}
+{{- with .CustomFacets}}
+<h2>Custom facets</h2>
+{{template "values" .}}
+{{- end}}
+{{- with .Annotations}}
+<h2>Annotations</h2>
+{{template "values" .}}
+{{- end}}
+{{- with .Examples}}
+<h2>Examples</h2>
+{{- range .}}
+{{- with .Name}}
+<h3>{{.}}</h3>
+{{- end}}
+<pre>{{.Value}}</pre>
+{{- end}}
+{{- end}}
+{{- end}}
+{{template "footer" .}}
+{{- end -}}
+`
+
+// docsMarkdownTemplates are the default templates of the Markdown documentation.
+const docsMarkdownTemplates = `
+{{- define "typeref" -}}
+{{- if and (eq (len .Ref.Links) 1) (eq (index .Ref.Links 0).Name .Ref.Label) -}}
+[{{md .Ref.Label}}]({{.Root}}{{(index .Ref.Links 0).URL}})
+{{- else -}}
+` + "`{{md .Ref.Label}}`" + `{{range .Ref.Links}} [{{md .Name}}]({{$.Root}}{{.URL}}){{end}}
+{{- end -}}
+{{- end -}}
+
+{{- define "values" -}}
+| Name | Value |
+|------|-------|
+{{- range .}}
+| {{md .Name}} | ` + "`{{md .Value}}`" + ` |
+{{- end}}
+{{- end -}}
+
+{{- define "index" -}}
+# {{.Title}}
+{{range .Libraries}}
+## [{{.Name}}]({{$.Root}}{{.URL}})
+{{with .Usage}}
+{{.}}
+{{end}}
+{{- range .Types}}
+- [{{.Name}}]({{$.Root}}{{.URL}}){{with .Description}} – {{md .}}{{end}}
+{{- end}}
+{{end -}}
+{{- end -}}
+
+{{- define "library" -}}
+{{- with .Library -}}
+[Index]({{$.Root}}index.md)
+
+# {{.Name}}
+{{with .Usage}}
+{{.}}
+{{end}}
+{{- with .Uses}}
+## Uses
+
+| Alias | Library |
+|-------|---------|
+{{- range .}}
+| {{.Alias}} | [{{.Library.Name}}]({{$.Root}}{{.Library.URL}}) |
+{{- end}}
+{{end}}
+{{- with .Annotations}}
+## Annotations
+
+{{template "values" .}}
+{{end}}
+## Types
+
+| Name | Kind | Description |
+|------|------|-------------|
+{{- range .Types}}
+| [{{.Name}}]({{$.Root}}{{.URL}}) | {{.Kind}} | {{md .Description}} |
+{{- end}}
+{{end -}}
+{{- end -}}
+
+{{- define "type" -}}
+{{- with .Type -}}
+[Index]({{$.Root}}index.md) / [{{.Library.Name}}]({{$.Root}}{{.Library.URL}})
+
+# {{.Name}}
+{{with .DisplayName}}
+*{{.}}*
+{{end}}
+{{- with .Description}}
+{{.}}
+{{end}}
+Kind: ` + "`{{.Kind}}`" + `
+{{with .AliasOf}}
+Alias of {{template "typeref" (dict "Root" $.Root "Ref" .)}}
+{{end}}
+{{- with .Parents}}
+Inherits: {{range $i, $p := .}}{{if $i}}, {{end}}[{{$p.Name}}]({{$.Root}}{{$p.URL}}){{end}}
+{{end}}
+{{- with .Properties}}
+## Properties
+
+| Name | Type | Required | Description | Inherited from |
+|------|------|----------|-------------|----------------|
+{{- range .}}
+| {{md .Name}} | {{template "typeref" (dict "Root" $.Root "Ref" .Type)}} | {{if .Required}}yes{{else}}no{{end}} | {{md .Description}} | {{with .Origin}}[{{.Name}}]({{$.Root}}{{.URL}}){{end}} |
+{{- end}}
+{{end}}
+{{- with .Items}}
+## Items
+
+{{template "typeref" (dict "Root" $.Root "Ref" .)}}
+{{end}}
+{{- with .Members}}
+## Union members
+{{range .}}
+- {{template "typeref" (dict "Root" $.Root "Ref" .)}}
+{{- end}}
+{{end}}
+{{- with .Facets}}
+## Facets
+
+{{template "values" .}}
+{{end}}
+{{- with .CustomFacets}}
+## Custom facets
+
+{{template "values" .}}
+{{end}}
+{{- with .Annotations}}
+## Annotations
+
+{{template "values" .}}
+{{end}}
+{{- with .Examples}}
+## Examples
+{{range .}}
+{{with .Name}}### {{.}}
+
+{{end -}}
+` + "```json\n{{.Value}}\n```" + `
+{{end}}
+{{- end}}
+{{- end -}}
+{{- end -}}
+`
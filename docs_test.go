@@ -0,0 +1,135 @@
+package raml
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseDocsLibrary(t *testing.T) *Library {
+	t.Helper()
+	files := map[string]string{
+		"api.raml": `#%RAML 1.0 Library
+usage: Pet store types.
+uses:
+  common: common/types.raml
+annotationTypes:
+  owner: string
+types:
+  Pet:
+    (owner): team-pets
+    description: A pet.
+    facets:
+      level: integer
+    discriminator: kind
+    properties:
+      kind: string
+      name:
+        type: common.Name
+        description: Name of the pet.
+      tags?: common.Name[]
+  Cat:
+    type: Pet
+    level: 3
+    additionalProperties: false
+    properties:
+      lives:
+        type: integer
+        minimum: 1
+        maximum: 9
+    example:
+      kind: Cat
+      name: Tom
+      lives: 7
+  Animal: Cat | common.Name
+  Code:
+    type: string
+    pattern: ^[A-Z]{3}$
+    enum: [ABC, XYZ]
+  Alias: Pet
+`,
+		"common/types.raml": `#%RAML 1.0 Library
+types:
+  Name:
+    type: string
+    minLength: 1
+`,
+	}
+	return parseTestLibrary(t, files, "api.raml", OptWithUnwrap())
+}
+
+func TestDocsGenerator_Generate(t *testing.T) {
+	lib := parseDocsLibrary(t)
+
+	t.Run("html", func(t *testing.T) {
+		files, err := NewDocsGenerator().Generate(lib)
+		require.NoError(t, err)
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		require.Equal(t, []string{
+			"api/Alias.html", "api/Animal.html", "api/Cat.html", "api/Code.html", "api/Pet.html", "api/index.html",
+			"common/types/Name.html", "common/types/index.html", "index.html", DocsSearchIndexFile,
+		}, names)
+
+		cat := string(files["api/Cat.html"])
+		require.Contains(t, cat, `<p>Inherits: <a href="../api/Pet.html">Pet</a></p>`)
+		require.Contains(t, cat, `<tr><td>lives</td><td><code>integer</code></td><td>yes</td><td></td><td></td></tr>`)
+		require.Contains(t, cat, `<tr class="inherited"><td>name</td><td><a href="../common/types/Name.html">common.Name</a></td>`+
+			`<td>yes</td><td>Name of the pet.</td><td><a href="../api/Pet.html">Pet</a></td></tr>`)
+		require.Contains(t, cat, `<code>common.Name[]</code> <a href="../common/types/Name.html">common.Name</a>`)
+		require.Contains(t, cat, `<tr><td>additionalProperties</td><td><code>false</code></td></tr>`)
+		require.Contains(t, cat, `<tr><td>level</td><td><code>3</code></td></tr>`)
+		require.Contains(t, cat, `&#34;lives&#34;: 7`)
+
+		pet := string(files["api/Pet.html"])
+		require.Contains(t, pet, `<tr><td>(owner)</td><td><code>&#34;team-pets&#34;</code></td></tr>`)
+		require.Contains(t, string(files["api/Code.html"]), `<tr><td>enum</td><td><code>&#34;ABC&#34;, &#34;XYZ&#34;</code></td></tr>`)
+		require.Contains(t, string(files["api/Alias.html"]), `<p>Alias of <a href="../api/Pet.html">Pet</a></p>`)
+		require.Contains(t, string(files["api/Animal.html"]), `<li><a href="../common/types/Name.html">common.Name</a></li>`)
+		require.Contains(t, string(files["api/index.html"]),
+			`<tr><td>common</td><td><a href="../common/types/index.html">common/types</a></td></tr>`)
+		require.Contains(t, string(files["index.html"]), `{"name":"Name","library":"common/types","kind":"string","url":"common/types/Name.html"}`)
+
+		var search []DocsSearchEntry
+		require.NoError(t, json.Unmarshal(files[DocsSearchIndexFile], &search))
+		require.Len(t, search, 6)
+		require.Equal(t, DocsSearchEntry{
+			Name: "Pet", Library: "api", Kind: "object", Description: "A pet.", URL: "api/Pet.html",
+		}, search[0])
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		files, err := NewDocsGenerator(WithDocsFormat(DocsFormatMarkdown)).Generate(lib)
+		require.NoError(t, err)
+		require.Len(t, files, 10)
+		cat := string(files["api/Cat.md"])
+		require.Contains(t, cat, "Inherits: [Pet](../api/Pet.md)\n")
+		require.Contains(t, cat, "| name | [common.Name](../common/types/Name.md) | yes | Name of the pet. | [Pet](../api/Pet.md) |\n")
+		require.Contains(t, cat, "```json\n{\n  \"kind\": \"Cat\",")
+		require.Contains(t, string(files["api/Code.md"]), "| pattern | `^[A-Z]{3}$` |\n")
+		require.Contains(t, string(files["index.md"]), "- [Pet](api/Pet.md) – A pet.\n")
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"type.tmpl": {Data: []byte(`{{define "type"}}<h1>{{.Type.Name}}</h1>{{end}}`)},
+		}
+		files, err := NewDocsGenerator(WithDocsTemplates(fsys, "*.tmpl")).Generate(lib)
+		require.NoError(t, err)
+		require.Equal(t, "<h1>Cat</h1>", string(files["api/Cat.html"]))
+		require.Contains(t, string(files["index.html"]), `<a href="api/Cat.html">Cat</a>`)
+
+		fsys["type.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "type"}}<script>{{.Type.Name}}{{end}}`)}
+		_, err = NewDocsGenerator(WithDocsTemplates(fsys, "*.tmpl")).Generate(lib)
+		require.ErrorContains(t, err, "render api/Pet.html")
+
+		_, err = NewDocsGenerator(WithDocsFormat("pdf")).Generate(lib)
+		require.ErrorContains(t, err, "unsupported docs format: pdf")
+	})
+}
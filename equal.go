@@ -0,0 +1,258 @@
+package raml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+type compareOptions struct {
+	annotations bool
+}
+
+type CompareOpt interface {
+	Apply(*compareOptions)
+}
+
+type compareOptAnnotations struct{}
+
+func (compareOptAnnotations) Apply(opt *compareOptions) {
+	opt.annotations = true
+}
+
+// OptCompareAnnotations makes Equal and Fingerprint take annotations of shapes into account.
+func OptCompareAnnotations() CompareOpt {
+	return compareOptAnnotations{}
+}
+
+// Equal returns true if both shapes describe the same type.
+//
+// Shapes are compared by semantics: the kind of the shape, facets, properties, array items, union members,
+// default values and custom facet values. IDs, names, source locations and documentation facets such as
+// description, display name and examples are ignored. Union members, enum values and properties are compared
+// regardless of their order, while the order of pattern properties is significant. Recursive shapes are equal
+// if they have the same structure. Annotations are compared only with OptCompareAnnotations.
+// The shapes must be unwrapped.
+func Equal(a, b *BaseShape, opts ...CompareOpt) bool {
+	return bytes.Equal(canonicalShape(a, opts), canonicalShape(b, opts))
+}
+
+// Fingerprint returns a hash of the shape such that equal shapes have the same fingerprint. See Equal for details.
+func Fingerprint(base *BaseShape, opts ...CompareOpt) [32]byte {
+	return sha256.Sum256(canonicalShape(base, opts))
+}
+
+func canonicalShape(base *BaseShape, opts []CompareOpt) []byte {
+	cOpts := &compareOptions{}
+	for _, opt := range opts {
+		opt.Apply(cOpts)
+	}
+	e := &shapeEncoder{annotations: cOpts.annotations}
+	e.encode(base)
+	return e.buf.Bytes()
+}
+
+// shapeEncoder writes the canonical representation of the shape.
+type shapeEncoder struct {
+	buf         bytes.Buffer
+	annotations bool
+	// stack contains shapes being encoded, recursive references are encoded as a distance to the shape in stack.
+	stack []*BaseShape
+}
+
+// write writes length-prefixed tokens so that different sequences of tokens never produce the same output.
+func (e *shapeEncoder) write(tokens ...string) {
+	for _, t := range tokens {
+		e.buf.WriteString(strconv.Itoa(len(t)))
+		e.buf.WriteByte(':')
+		e.buf.WriteString(t)
+	}
+}
+
+func (e *shapeEncoder) writeValue(name string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%#v", v))
+	}
+	e.write(name, string(data))
+}
+
+// writeSorted writes the name and the tokens in sorted order.
+func (e *shapeEncoder) writeSorted(name string, tokens []string) {
+	sort.Strings(tokens)
+	e.write(name, strconv.Itoa(len(tokens)))
+	e.write(tokens...)
+}
+
+func (e *shapeEncoder) writeNodes(name string, nodes Nodes) {
+	if nodes == nil {
+		return
+	}
+	values := make([]string, len(nodes))
+	for i, n := range nodes {
+		data, err := json.Marshal(n.Value)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%#v", n.Value))
+		}
+		values[i] = string(data)
+	}
+	e.writeSorted(name, values)
+}
+
+func (e *shapeEncoder) writeBound(name string, v *big.Float) {
+	if v != nil {
+		// NOTE: String rounds to 10 significant digits, the shortest exact representation is required.
+		e.write(name, v.Text('g', -1))
+	}
+}
+
+// nested encodes the shape into a separate token, e.g. to sort union members.
+func (e *shapeEncoder) nested(base *BaseShape) string {
+	n := &shapeEncoder{annotations: e.annotations, stack: e.stack}
+	n.encode(base)
+	return n.buf.String()
+}
+
+func (e *shapeEncoder) encode(base *BaseShape) {
+	if base == nil {
+		e.write("none")
+		return
+	}
+	if rs, ok := base.Shape.(*RecursiveShape); ok {
+		for i := len(e.stack) - 1; i >= 0; i-- {
+			if e.stack[i].ID == rs.Head.ID {
+				e.write("ref", strconv.Itoa(len(e.stack)-1-i))
+				return
+			}
+		}
+		base = rs.Head
+	}
+	for i := len(e.stack) - 1; i >= 0; i-- {
+		if e.stack[i] == base {
+			e.write("ref", strconv.Itoa(len(e.stack)-1-i))
+			return
+		}
+	}
+	e.stack = append(e.stack, base)
+	defer func() {
+		e.stack = e.stack[:len(e.stack)-1]
+	}()
+
+	e.write("shape", shapeKind(base.Shape))
+	if base.Default != nil {
+		e.writeValue("default", base.Default.Value)
+	}
+	e.encodeNodeMap("facets", base.CustomShapeFacets)
+	if e.annotations && base.CustomDomainProperties != nil {
+		annotations := orderedmap.New[string, *Node](base.CustomDomainProperties.Len())
+		for pair := base.CustomDomainProperties.Oldest(); pair != nil; pair = pair.Next() {
+			annotations.Set(pair.Key, pair.Value.Extension)
+		}
+		e.encodeNodeMap("annotations", annotations)
+	}
+	e.encodeShape(base)
+}
+
+func (e *shapeEncoder) encodeNodeMap(name string, m *orderedmap.OrderedMap[string, *Node]) {
+	if m == nil {
+		return
+	}
+	values := make([]string, 0, m.Len())
+	for pair := m.Oldest(); pair != nil; pair = pair.Next() {
+		var v any
+		if pair.Value != nil {
+			v = pair.Value.Value
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%#v", v))
+		}
+		values = append(values, pair.Key+"="+string(data))
+	}
+	e.writeSorted(name, values)
+}
+
+func (e *shapeEncoder) encodeShape(base *BaseShape) {
+	switch s := base.Shape.(type) {
+	case *ObjectShape:
+		e.encodeObject(base, s)
+	case *ArrayShape:
+		e.write("items", e.nested(s.Items))
+		e.writeBound("minItems", uintBound(s.MinItems))
+		e.writeBound("maxItems", uintBound(s.MaxItems))
+		e.write("uniqueItems", strconv.FormatBool(s.UniqueItems != nil && *s.UniqueItems))
+	case *UnionShape:
+		members := make([]string, len(s.AnyOf))
+		for i, member := range s.AnyOf {
+			members[i] = e.nested(member)
+		}
+		e.writeSorted("anyOf", members)
+		e.writeNodes("enum", s.Enum)
+	case *StringShape:
+		e.writeBound("minLength", uintBound(s.MinLength))
+		e.writeBound("maxLength", uintBound(s.MaxLength))
+		if s.Pattern != nil {
+			e.write("pattern", s.Pattern.String())
+		}
+		e.writeNodes("enum", s.Enum)
+	case *IntegerShape:
+		e.writeBound("minimum", intBound(s.Minimum))
+		e.writeBound("maximum", intBound(s.Maximum))
+		e.writeBound("multipleOf", floatBound(s.MultipleOf))
+		e.write("format", strconv.Itoa(int(integerFormatSize(s.Format))))
+		e.writeNodes("enum", s.Enum)
+	case *NumberShape:
+		e.writeBound("minimum", floatBound(s.Minimum))
+		e.writeBound("maximum", floatBound(s.Maximum))
+		e.writeBound("multipleOf", floatBound(s.MultipleOf))
+		if s.Format != nil {
+			e.write("format", *s.Format)
+		}
+		e.writeNodes("enum", s.Enum)
+	case *FileShape:
+		e.writeBound("minLength", uintBound(s.MinLength))
+		e.writeBound("maxLength", uintBound(s.MaxLength))
+		e.writeNodes("fileTypes", s.FileTypes)
+	case *BooleanShape:
+		e.writeNodes("enum", s.Enum)
+	case *DateTimeShape:
+		e.write("format", dateTimeFormat(s.Format))
+	case *JSONShape:
+		e.write("schema", s.Raw)
+	}
+}
+
+func (e *shapeEncoder) encodeObject(base *BaseShape, s *ObjectShape) {
+	e.write("additionalProperties",
+		strconv.FormatBool(s.AdditionalProperties == nil || *s.AdditionalProperties))
+	e.writeBound("minProperties", uintBound(s.MinProperties))
+	e.writeBound("maxProperties", uintBound(s.MaxProperties))
+	if s.Discriminator != nil {
+		e.write("discriminator", *s.Discriminator)
+		e.writeValue("discriminatorValue", discriminatorValueOf(base, s))
+	}
+	if s.Properties != nil {
+		props := make([]string, 0, s.Properties.Len())
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			p := pair.Value
+			n := &shapeEncoder{annotations: e.annotations, stack: e.stack}
+			n.write(pair.Key, strconv.FormatBool(p.Required))
+			n.encode(p.Base)
+			props = append(props, n.buf.String())
+		}
+		e.writeSorted("properties", props)
+	}
+	if s.PatternProperties != nil {
+		// NOTE: The first matching pattern property prevails, so the order is significant.
+		e.write("patternProperties", strconv.Itoa(s.PatternProperties.Len()))
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			e.write(pair.Key, e.nested(pair.Value.Base))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	content := `#%RAML 1.0 Library
+annotationTypes:
+  internal: boolean
+types:
+  Address:
+    description: Postal address
+    properties:
+      street:
+        type: string
+        maxLength: 100
+      city: string
+      zip?:
+        type: string
+        pattern: ^[0-9]{5}$
+  Order:
+    properties:
+      billing:
+        displayName: Billing address
+        properties:
+          city: string
+          street:
+            type: string
+            maxLength: 100
+          zip?:
+            type: string
+            pattern: ^[0-9]{5}$
+      shipping:
+        properties:
+          street:
+            type: string
+            maxLength: 50
+          city: string
+  Annotated:
+    (internal): true
+    type: Address
+  Pet: Cat | Dog
+  ReversedPet: Dog | Cat
+  Cat:
+    properties:
+      lives: integer
+  Dog:
+    properties:
+      good: boolean
+  Status:
+    enum: [active, disabled]
+  ReversedStatus:
+    enum: [disabled, active]
+  Int32:
+    type: integer
+    format: int32
+  Int:
+    type: integer
+    format: int
+  Limit:
+    type: integer
+    maximum: 10000000001
+  OtherLimit:
+    type: integer
+    maximum: 10000000002
+  Node:
+    properties:
+      value: string
+      next?: Node
+  OtherNode:
+    properties:
+      value: string
+      next?: OtherNode
+  TreeNode:
+    properties:
+      value: string
+      children?: TreeNode[]
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+	get := func(name string) *BaseShape {
+		s, ok := lib.Types.Get(name)
+		require.True(t, ok, name)
+		return s
+	}
+	order := get("Order").Shape.(*ObjectShape)
+	property := func(name string) *BaseShape {
+		p, ok := order.Properties.Get(name)
+		require.True(t, ok, name)
+		return p.Base
+	}
+
+	tests := []struct {
+		name  string
+		a, b  *BaseShape
+		opts  []CompareOpt
+		equal bool
+	}{
+		{name: "same shape", a: get("Address"), b: get("Address"), equal: true},
+		{name: "identical inline type", a: get("Address"), b: property("billing"), equal: true},
+		{name: "different facets", a: get("Address"), b: property("shipping"), equal: false},
+		{name: "annotations are ignored", a: get("Address"), b: get("Annotated"), equal: true},
+		{
+			name: "annotations are compared", a: get("Address"), b: get("Annotated"),
+			opts: []CompareOpt{OptCompareAnnotations()}, equal: false,
+		},
+		{name: "union members order", a: get("Pet"), b: get("ReversedPet"), equal: true},
+		{name: "enum values order", a: get("Status"), b: get("ReversedStatus"), equal: true},
+		{name: "integer format aliases", a: get("Int32"), b: get("Int"), equal: true},
+		{name: "different kinds", a: get("Cat"), b: get("Status"), equal: false},
+		{name: "bounds differ in last digit", a: get("Limit"), b: get("OtherLimit"), equal: false},
+		{name: "recursive types", a: get("Node"), b: get("OtherNode"), equal: true},
+		{name: "different recursive types", a: get("Node"), b: get("TreeNode"), equal: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.equal, Equal(tt.a, tt.b, tt.opts...))
+			require.Equal(t, tt.equal, Fingerprint(tt.a, tt.opts...) == Fingerprint(tt.b, tt.opts...))
+		})
+	}
+}
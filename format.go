@@ -0,0 +1,294 @@
+package raml
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// facetOrder is the canonical order of the facets of a type declaration. "()" stands for annotations and "*"
+// stands for custom facets and other unknown keys, which keep their relative order.
+var facetOrder = []string{
+	"uses",
+	FacetType,
+	FacetDisplayName,
+	FacetDescription,
+	"()",
+	FacetRequired,
+	FacetDefault,
+	FacetEnum,
+	FacetFormat,
+	FacetPattern,
+	FacetMinLength,
+	FacetMaxLength,
+	FacetMinimum,
+	FacetMaximum,
+	FacetMultipleOf,
+	FacetFileTypes,
+	FacetItems,
+	FacetMinItems,
+	FacetMaxItems,
+	FacetUniqueItems,
+	FacetDiscriminator,
+	FacetDiscriminatorValue,
+	FacetAdditionalProperties,
+	FacetMinProperties,
+	FacetMaxProperties,
+	FacetProperties,
+	FacetFacets,
+	FacetAllowedTargets,
+	FacetStrict,
+	"*",
+	FacetExample,
+	FacetExamples,
+}
+
+// fragmentOrder is the canonical order of the top-level keys of library fragments.
+var fragmentOrder = []string{"usage", "()", "uses", "annotationTypes", "types", "schemas", "*"}
+
+// typeExpressionRe matches scalars that may be type expressions, e.g. "string | nil" or "(Cat | Dog)[]".
+var typeExpressionRe = regexp.MustCompile(`^[A-Za-z0-9_.\-\s|()\[\]]+$`)
+
+// Format rewrites the RAML document into the canonical layout:
+//   - the facets of type declarations are ordered canonically, the order of types and properties is preserved;
+//   - type expressions are normalized, e.g. "string |nil" becomes "string | nil";
+//   - scalars are quoted only when required and the indentation is two spaces;
+//   - comments are preserved, comments that follow values are moved before the next key or item.
+//
+// The result is idempotent and parses into the same model as the source.
+func Format(data []byte) ([]byte, error) {
+	head, body, _ := bytes.Cut(data, []byte("\n"))
+	head = bytes.TrimRight(head, "\r ")
+	kind, err := IdentifyFragment(string(head))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal yaml: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.Write(head)
+	buf.WriteByte('\n')
+	if doc.Kind == 0 {
+		return buf.Bytes(), nil
+	}
+	root := &doc
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		root = doc.Content[0]
+	}
+	if root.Kind == yaml.MappingNode && len(root.Content) > 0 && root.HeadComment == "" {
+		// NOTE: The comment at the top of the document is attached to the first key and must stay at the top.
+		root.HeadComment = root.Content[0].HeadComment
+		root.Content[0].HeadComment = ""
+	}
+	switch kind {
+	case FragmentLibrary:
+		formatLibrary(root)
+	case FragmentDataType:
+		formatTypeDeclaration(root)
+	case FragmentNamedExample, FragmentUnknown:
+	}
+	formatScalars(&doc)
+	formatComments(&doc)
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode yaml: %w", err)
+	}
+	if err = enc.Close(); err != nil {
+		return nil, fmt.Errorf("close encoder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatLibrary(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	sortMapping(node, fragmentOrder)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "types", "annotationTypes", "schemas":
+			formatTypeDeclarations(node.Content[i+1])
+		}
+	}
+}
+
+// formatTypeDeclarations formats the mapping of names to type declarations.
+func formatTypeDeclarations(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 1; i < len(node.Content); i += 2 {
+		formatTypeDeclaration(node.Content[i])
+	}
+}
+
+func formatTypeDeclaration(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		formatTypeExpression(node)
+	case yaml.MappingNode:
+		sortMapping(node, facetOrder)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			switch node.Content[i].Value {
+			case FacetType:
+				if value.Kind == yaml.SequenceNode {
+					for _, item := range value.Content {
+						formatTypeExpression(item)
+					}
+				} else {
+					formatTypeDeclaration(value)
+				}
+			case FacetItems:
+				formatTypeDeclaration(value)
+			case FacetProperties, FacetFacets:
+				formatTypeDeclarations(value)
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode, yaml.AliasNode:
+	}
+}
+
+// formatTypeExpression normalizes the whitespace of the type expression.
+func formatTypeExpression(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" || !typeExpressionRe.MatchString(node.Value) {
+		return
+	}
+	var sb strings.Builder
+	for _, r := range node.Value {
+		switch r {
+		case ' ', '\t', '\n', '\r':
+		case '|':
+			sb.WriteString(" | ")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	node.Value = sb.String()
+}
+
+// formatScalars drops the quotes of scalars that do not require them. The encoder quotes the scalars that would
+// change their type otherwise. Block scalars keep their style.
+func formatScalars(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		switch {
+		case strings.HasPrefix(node.Value, "\n") && node.Tag == "!!str":
+			// NOTE: The encoder drops the leading line breaks of block scalars, so such values stay quoted.
+			node.Style = yaml.DoubleQuotedStyle
+		case node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0:
+			node.Style &^= yaml.SingleQuotedStyle | yaml.DoubleQuotedStyle
+		}
+		return
+	}
+	for _, child := range node.Content {
+		formatScalars(child)
+	}
+}
+
+// formatComments places the comments so that the parser attaches them to the same nodes on the next pass.
+// The parser attaches comments that follow a value to different nodes depending on the indentation and
+// the blank lines around them, so foot comments are moved to the head comment of the next key or item in
+// the document, and the ones at the end become the foot comment of the document. Blank lines are dropped from
+// head comments, since the parser attaches the part before a blank line to the previous node.
+func formatComments(doc *yaml.Node) {
+	var pending string
+	// entry moves the pending comments to the head comment of the key or item that starts on a new line.
+	entry := func(node *yaml.Node) {
+		node.HeadComment = dropBlankLines(joinComments(pending, node.HeadComment))
+		pending = ""
+	}
+	// foot moves the foot comment of the node to the pending comments.
+	foot := func(node *yaml.Node) {
+		pending = joinComments(pending, node.FootComment)
+		node.FootComment = ""
+	}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		block := node.Style&yaml.FlowStyle == 0
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if block {
+					entry(key)
+				}
+				walk(value)
+				foot(key)
+				foot(value)
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				if block {
+					entry(item)
+				}
+				walk(item)
+				foot(item)
+			}
+		case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+		}
+	}
+	for _, child := range doc.Content {
+		walk(child)
+		foot(child)
+	}
+	doc.FootComment = dropBlankLines(joinComments(pending, doc.FootComment))
+}
+
+func dropBlankLines(comment string) string {
+	lines := strings.Split(comment, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func joinComments(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "\n" + b
+}
+
+// sortMapping orders the keys of the mapping by the order. Keys of the same rank keep their relative order.
+func sortMapping(node *yaml.Node, order []string) {
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i
+	}
+	type pair struct {
+		key, value *yaml.Node
+		rank       int
+	}
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		r, ok := rank[key]
+		switch {
+		case ok:
+		case strings.HasPrefix(key, "(") && strings.HasSuffix(key, ")"):
+			r = rank["()"]
+		default:
+			r = rank["*"]
+		}
+		pairs = append(pairs, pair{key: node.Content[i], value: node.Content[i+1], rank: r})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].rank < pairs[j].rank
+	})
+	for i, p := range pairs {
+		node.Content[2*i] = p.key
+		node.Content[2*i+1] = p.value
+	}
+}
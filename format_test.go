@@ -0,0 +1,216 @@
+package raml
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	src := `#%RAML 1.0 Library
+# Pets library.
+types:
+  # A pet.
+  Pet:
+    properties:
+      name: string |nil   # optional name
+      'kind':
+        required: true
+        type: "string"
+        enum: ['cat', "dog"]
+    description: 'A pet'
+    (owner): team
+    example:
+      name: "Tom"
+      kind: cat
+    discriminator: kind
+    type: object
+  Code:
+    pattern: "^[A-Z]+$"
+    type: string
+    default: "123"
+    description: |
+      Multi
+      line
+  Animals: (Pet|Code)[]
+  Pair:
+    type: [ Pet ,Code ]
+uses:
+  other: other.raml
+annotationTypes:
+  owner: string
+usage: "Test"
+`
+	expected := `#%RAML 1.0 Library
+# Pets library.
+usage: Test
+uses:
+  other: other.raml
+annotationTypes:
+  owner: string
+types:
+  # A pet.
+  Pet:
+    type: object
+    description: A pet
+    (owner): team
+    discriminator: kind
+    properties:
+      name: string | nil # optional name
+      kind:
+        type: string
+        required: true
+        enum: [cat, dog]
+    example:
+      name: Tom
+      kind: cat
+  Code:
+    type: string
+    description: |
+      Multi
+      line
+    default: "123"
+    pattern: ^[A-Z]+$
+  Animals: (Pet | Code)[]
+  Pair:
+    type: [Pet, Code]
+`
+	out, err := Format([]byte(src))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(out))
+
+	again, err := Format(out)
+	require.NoError(t, err)
+	require.Equal(t, string(out), string(again), "formatting must be idempotent")
+
+	_, err = Format([]byte("#%RAML 1.0\ntitle: API\n"))
+	require.ErrorContains(t, err, "unknown fragment kind")
+}
+
+func TestFormat_Comments(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "comment after nested mapping",
+			src:  "types:\n  A:\n    properties:\n      a: string\n    # after properties\n  B: string\n",
+			want: "types:\n  A:\n    properties:\n      a: string\n  # after properties\n  B: string\n",
+		},
+		{
+			name: "comment after empty value at the end",
+			src:  "types:\n  B:\n    properties:\n#      a: integer",
+			want: "types:\n  B:\n    properties:\n\n#      a: integer\n",
+		},
+		{
+			name: "blank line in head comment",
+			src:  "types:\n  A: string\n  # first\n\n  # second\n  B: string\n",
+			want: "types:\n  A: string\n  # first\n  # second\n  B: string\n",
+		},
+		{
+			name: "comment after sequence item",
+			src:  "types:\n  A:\n    enum:\n    - a\n    # after a\n    - b\n",
+			want: "types:\n  A:\n    enum:\n      - a\n      # after a\n      - b\n",
+		},
+		{
+			name: "block scalar with leading line break",
+			src:  "usage: |\n\n  Test\n",
+			want: "usage: \"\\nTest\\n\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const head = "#%RAML 1.0 Library\n"
+			out, err := Format([]byte(head + tt.src))
+			require.NoError(t, err)
+			require.Equal(t, head+tt.want, string(out))
+			// A single pass must be a fixed point.
+			again, err := Format(out)
+			require.NoError(t, err)
+			require.Equal(t, string(out), string(again))
+		})
+	}
+}
+
+// TestFormat_Fixtures formats the fixtures and verifies that the formatted files parse into the same model.
+func TestFormat_Fixtures(t *testing.T) {
+	dir := t.TempDir()
+	var entrypoints []string
+	err := filepath.Walk("fixtures", func(path string, info os.FileInfo, errWalk error) error {
+		if errWalk != nil || info.IsDir() {
+			return errWalk
+		}
+		rel, errRel := filepath.Rel("fixtures", path)
+		if errRel != nil {
+			return errRel
+		}
+		data, errRead := os.ReadFile(path)
+		if errRead != nil {
+			return errRead
+		}
+		target := filepath.Join(dir, rel)
+		if errMkdir := os.MkdirAll(filepath.Dir(target), 0o750); errMkdir != nil {
+			return errMkdir
+		}
+		if filepath.Ext(path) == ".raml" && !strings.Contains(path, "invalid") {
+			formatted, errFormat := Format(data)
+			require.NoError(t, errFormat, path)
+			again, errFormat := Format(formatted)
+			require.NoError(t, errFormat, path)
+			require.Equal(t, string(formatted), string(again), "%s: formatting must be idempotent", path)
+			data = formatted
+			entrypoints = append(entrypoints, rel)
+		}
+		return os.WriteFile(target, data, 0o600)
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, entrypoints)
+
+	for _, rel := range entrypoints {
+		original, errParse := ParseFromPath(filepath.Join("fixtures", rel), OptWithUnwrap())
+		if errParse != nil {
+			continue
+		}
+		formatted, errParse := ParseFromPath(filepath.Join(dir, rel), OptWithUnwrap())
+		require.NoError(t, errParse, rel)
+		require.Equal(t, modelSnapshot(t, original), modelSnapshot(t, formatted), rel)
+	}
+}
+
+// modelSnapshot returns the JSON schemas of the types and the values of the annotations of the entrypoint.
+func modelSnapshot(t *testing.T, r *RAML) map[string]string {
+	t.Helper()
+	res := make(map[string]string)
+	convert := func(name string, base *BaseShape) {
+		c, err := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper))
+		require.NoError(t, err)
+		schema, err := c.Convert(base.Shape)
+		require.NoError(t, err)
+		b, err := json.Marshal(schema)
+		require.NoError(t, err)
+		res[name] = string(b)
+	}
+	switch f := r.EntryPoint().(type) {
+	case *Library:
+		for pair := f.Types.Oldest(); pair != nil; pair = pair.Next() {
+			convert(pair.Key, pair.Value)
+		}
+		for pair := f.AnnotationTypes.Oldest(); pair != nil; pair = pair.Next() {
+			convert("("+pair.Key+")", pair.Value)
+		}
+		res["usage"] = f.Usage
+	case *DataType:
+		convert("$", f.Shape)
+	}
+	for _, de := range r.GetAllAnnotationsPtr() {
+		b, err := json.Marshal(de.Extension.Value)
+		require.NoError(t, err)
+		res[filepath.Base(de.Location)+":"+de.Name] += string(b)
+	}
+	return res
+}
@@ -0,0 +1,690 @@
+package raml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// generateDefaultMaxDepth is the nesting depth after which optional data is no longer generated.
+	generateDefaultMaxDepth = 4
+	// generateDepthSlack is the nesting depth over the maximum depth that is allowed for required data.
+	generateDepthSlack = 16
+	// generateAttempts is the number of attempts to generate a value that satisfies all facets.
+	generateAttempts = 100
+	// generateRepeat is the number of extra repetitions of unbounded collections and regexp operators.
+	generateRepeat = 3
+	// generateIntegerSpan is the span of generated integers and numbers that have no minimum or maximum.
+	generateIntegerSpan = 1000
+)
+
+const generateAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+type generateOptions struct {
+	rng             *rand.Rand
+	maxDepth        int
+	preferExamples  bool
+	optionalPercent int
+}
+
+type GenerateOpt interface {
+	Apply(*generateOptions)
+}
+
+type generateOptSeed int64
+
+func (o generateOptSeed) Apply(opt *generateOptions) {
+	opt.rng = rand.New(rand.NewSource(int64(o))) //nolint:gosec // Generated data is not used for security purposes.
+}
+
+// OptGenerateSeed seeds the random generator to produce reproducible data.
+// By default, the generator is seeded with the current time.
+func OptGenerateSeed(seed int64) GenerateOpt {
+	return generateOptSeed(seed)
+}
+
+type generateOptMaxDepth int
+
+func (o generateOptMaxDepth) Apply(opt *generateOptions) {
+	opt.maxDepth = int(o)
+}
+
+// OptGenerateMaxDepth sets the nesting depth of objects and arrays after which only required properties and
+// minimum number of items are generated. Recursive shapes are cut at this depth if the shape allows it.
+func OptGenerateMaxDepth(depth int) GenerateOpt {
+	return generateOptMaxDepth(depth)
+}
+
+type generateOptPreferExamples bool
+
+func (o generateOptPreferExamples) Apply(opt *generateOptions) {
+	opt.preferExamples = bool(o)
+}
+
+// OptGeneratePreferExamples makes the generator use declared examples of the shapes instead of random values.
+func OptGeneratePreferExamples(prefer bool) GenerateOpt {
+	return generateOptPreferExamples(prefer)
+}
+
+type generateOptOptionalPercent int
+
+func (o generateOptOptionalPercent) Apply(opt *generateOptions) {
+	opt.optionalPercent = int(o)
+}
+
+// OptGenerateOptionalPercent sets the probability in percent that an optional property is generated. Default is 50.
+func OptGenerateOptionalPercent(percent int) GenerateOpt {
+	return generateOptOptionalPercent(percent)
+}
+
+// Generate returns a random value that conforms to the shape.
+//
+// Generated values respect length, pattern, range, multipleOf, format and enum facets of scalars, number
+// and uniqueness of array items, required properties and discriminators of objects. Strings with
+// a pattern are generated from the regular expression. Values have the same types as values accepted by
+// Validate: objects are generated as map[string]any and arrays as []any. The shape must be unwrapped.
+func Generate(s *BaseShape, opts ...GenerateOpt) (any, error) {
+	if !s.IsUnwrapped() {
+		return nil, errors.New("shape must be unwrapped")
+	}
+	o := generateOptions{maxDepth: generateDefaultMaxDepth, optionalPercent: 50}
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+	if o.rng == nil {
+		OptGenerateSeed(time.Now().UnixNano()).Apply(&o)
+	}
+	g := &generator{opts: o, rng: o.rng}
+	return g.generate(s, "$")
+}
+
+type generator struct {
+	opts  generateOptions
+	rng   *rand.Rand
+	depth int
+}
+
+// minimal reports whether only the required data must be generated.
+func (g *generator) minimal() bool {
+	return g.depth >= g.opts.maxDepth
+}
+
+func (g *generator) generate(base *BaseShape, ctxPath string) (any, error) {
+	if g.opts.preferExamples {
+		if v, ok := g.example(base); ok {
+			return v, nil
+		}
+	}
+	switch s := base.Shape.(type) {
+	case *RecursiveShape:
+		return g.generate(s.Head, ctxPath)
+	case *ObjectShape:
+		return g.object(base, s, ctxPath)
+	case *ArrayShape:
+		return g.array(s, ctxPath)
+	case *UnionShape:
+		return g.union(s, ctxPath)
+	case *StringShape:
+		return g.string(s, ctxPath)
+	case *IntegerShape:
+		return g.integer(s, ctxPath)
+	case *NumberShape:
+		return g.number(s, ctxPath)
+	case *BooleanShape:
+		if len(s.Enum) > 0 {
+			return g.enum(s.Enum), nil
+		}
+		return g.rng.Intn(2) == 0, nil
+	case *FileShape:
+		return g.file(s, ctxPath)
+	case *DateTimeShape:
+		t := g.time()
+		if s.Format != nil && *s.Format == DateTimeFormatRFC2616 {
+			return t.Format(RFC2616), nil
+		}
+		return t.Format(time.RFC3339), nil
+	case *DateTimeOnlyShape:
+		return g.time().Format(DateTime), nil
+	case *DateOnlyShape:
+		return g.time().Format(time.DateOnly), nil
+	case *TimeOnlyShape:
+		return g.time().Format(time.TimeOnly), nil
+	case *NilShape:
+		return nil, nil
+	case *AnyShape:
+		return g.text(1, 8), nil
+	case *JSONShape:
+		// NOTE: JSON Schema types are not validated, so any JSON object conforms to the shape.
+		return map[string]any{}, nil
+	default:
+		return nil, fmt.Errorf("generate %s: unsupported shape %T", ctxPath, base.Shape)
+	}
+}
+
+// example returns a random declared example of the shape.
+func (g *generator) example(base *BaseShape) (any, bool) {
+	if base.Example != nil && base.Example.Data != nil {
+		return copyValue(base.Example.Data.Value), true
+	}
+	if base.Examples == nil || base.Examples.Map.Len() == 0 {
+		return nil, false
+	}
+	n := g.rng.Intn(base.Examples.Map.Len())
+	for pair := base.Examples.Map.Oldest(); pair != nil; pair = pair.Next() {
+		if n == 0 && pair.Value.Data != nil {
+			return copyValue(pair.Value.Data.Value), true
+		}
+		n--
+	}
+	return nil, false
+}
+
+func (g *generator) enum(enum Nodes) any {
+	return copyValue(enum[g.rng.Intn(len(enum))].Value)
+}
+
+func (g *generator) enter(ctxPath string) error {
+	g.depth++
+	if g.depth > g.opts.maxDepth+generateDepthSlack {
+		return fmt.Errorf("generate %s: maximum depth exceeded, the shape requires infinite recursion", ctxPath)
+	}
+	return nil
+}
+
+func (g *generator) leave() {
+	g.depth--
+}
+
+func (g *generator) object(base *BaseShape, s *ObjectShape, ctxPath string) (any, error) {
+	if err := g.enter(ctxPath); err != nil {
+		return nil, err
+	}
+	defer g.leave()
+
+	out := make(map[string]any)
+	// Optional data can be removed to satisfy maxProperties.
+	var removable []string
+	var skipped []string
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		k, prop := pair.Key, pair.Value
+		if s.Discriminator != nil && k == *s.Discriminator {
+			continue
+		}
+		if !prop.Required && (g.minimal() || g.rng.Intn(100) >= g.opts.optionalPercent) {
+			skipped = append(skipped, k)
+			continue
+		}
+		v, err := g.generate(prop.Base, ctxPath+"."+k)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = v
+		if !prop.Required {
+			removable = append(removable, k)
+		}
+	}
+	if s.Discriminator != nil {
+		out[*s.Discriminator] = discriminatorValueOf(base, s)
+	}
+	if !g.minimal() {
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			if g.rng.Intn(2) == 0 {
+				continue
+			}
+			k, err := g.patternPropertyKey(s, pair.Value, out, ctxPath)
+			if err != nil {
+				return nil, err
+			}
+			if k == "" {
+				continue
+			}
+			v, err := g.generate(pair.Value.Base, ctxPath+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = v
+			removable = append(removable, k)
+		}
+	}
+
+	if s.MaxProperties != nil {
+		for uint64(len(out)) > *s.MaxProperties && len(removable) > 0 {
+			delete(out, removable[len(removable)-1])
+			removable = removable[:len(removable)-1]
+		}
+		if uint64(len(out)) > *s.MaxProperties {
+			return nil, fmt.Errorf("generate %s: required properties exceed maxProperties", ctxPath)
+		}
+	}
+	if s.MinProperties != nil {
+		if err := g.fillProperties(s, out, skipped, ctxPath); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// fillProperties adds optional, pattern and additional properties until the object satisfies minProperties.
+func (g *generator) fillProperties(s *ObjectShape, out map[string]any, skipped []string, ctxPath string) error {
+	for _, k := range skipped {
+		if uint64(len(out)) >= *s.MinProperties {
+			return nil
+		}
+		prop, _ := s.Properties.Get(k)
+		v, err := g.generate(prop.Base, ctxPath+"."+k)
+		if err != nil {
+			return err
+		}
+		out[k] = v
+	}
+	for pair := s.PatternProperties.Oldest(); pair != nil && uint64(len(out)) < *s.MinProperties; {
+		k, err := g.patternPropertyKey(s, pair.Value, out, ctxPath)
+		if err != nil {
+			return err
+		}
+		if k == "" {
+			pair = pair.Next()
+			continue
+		}
+		v, err := g.generate(pair.Value.Base, ctxPath+"."+k)
+		if err != nil {
+			return err
+		}
+		out[k] = v
+	}
+	if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+		if uint64(len(out)) < *s.MinProperties {
+			return fmt.Errorf("generate %s: cannot satisfy minProperties without additional properties", ctxPath)
+		}
+		return nil
+	}
+	for i := 1; uint64(len(out)) < *s.MinProperties; i++ {
+		k := "additional" + strconv.Itoa(i)
+		if _, present := out[k]; present || s.findPropertyShape(k) != nil {
+			continue
+		}
+		out[k] = g.text(1, 8)
+	}
+	return nil
+}
+
+// patternPropertyKey returns a new property name that matches the pattern property and is not matched
+// by explicitly declared properties. An empty name is returned if the name cannot be generated.
+func (g *generator) patternPropertyKey(
+	s *ObjectShape, prop PatternProperty, out map[string]any, ctxPath string,
+) (string, error) {
+	re, err := syntax.Parse(prop.Pattern.String(), syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("generate %s: parse pattern property %s: %w", ctxPath, prop.Pattern.String(), err)
+	}
+	re = re.Simplify()
+	for i := 0; i < generateAttempts; i++ {
+		var sb strings.Builder
+		g.regexp(re, &sb, generateRepeat)
+		k := sb.String()
+		if _, present := out[k]; present || !prop.Pattern.MatchString(k) {
+			continue
+		}
+		// Properties with this name are validated against the declared property shape.
+		if s.findPropertyShape(k) != prop.Base {
+			continue
+		}
+		return k, nil
+	}
+	return "", nil
+}
+
+func (g *generator) array(s *ArrayShape, ctxPath string) (any, error) {
+	if err := g.enter(ctxPath); err != nil {
+		return nil, err
+	}
+	defer g.leave()
+
+	var lo, hi uint64
+	if s.MinItems != nil {
+		lo = *s.MinItems
+	}
+	hi = lo + generateRepeat
+	if s.MaxItems != nil && *s.MaxItems < hi {
+		hi = *s.MaxItems
+	}
+	if lo > hi {
+		return nil, fmt.Errorf("generate %s: minItems is greater than maxItems", ctxPath)
+	}
+	n := lo
+	if !g.minimal() {
+		n += uint64(g.rng.Int63n(int64(hi - lo + 1)))
+	}
+	if s.Items == nil {
+		out := make([]any, n)
+		for i := range out {
+			out[i] = g.text(1, 8)
+		}
+		return out, nil
+	}
+	unique := s.UniqueItems != nil && *s.UniqueItems
+	out := make([]any, 0, n)
+	for i := 0; uint64(len(out)) < n; i++ {
+		if i >= int(n)*generateAttempts {
+			// NOTE: The count is picked before it is known how many distinct items exist,
+			// so it is enough to satisfy minItems.
+			if uint64(len(out)) >= lo {
+				break
+			}
+			return nil, fmt.Errorf("generate %s: cannot generate %d unique items", ctxPath, lo)
+		}
+		v, err := g.generate(s.Items, ctxPath+"["+strconv.Itoa(len(out))+"]")
+		if err != nil {
+			return nil, err
+		}
+		if unique && containsValue(out, v) {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func containsValue(items []any, v any) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) union(s *UnionShape, ctxPath string) (any, error) {
+	if len(s.Enum) > 0 {
+		return g.enum(s.Enum), nil
+	}
+	// Members that cannot be generated, e.g. due to recursion limits, are skipped.
+	var errs []error
+	for _, i := range g.rng.Perm(len(s.AnyOf)) {
+		v, err := g.generate(s.AnyOf[i], ctxPath)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("generate %s: union has no members", ctxPath)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (g *generator) string(s *StringShape, ctxPath string) (any, error) {
+	if len(s.Enum) > 0 {
+		return g.enum(s.Enum), nil
+	}
+	lo, hi := g.lengthRange(s.LengthFacets)
+	if lo > hi {
+		return nil, fmt.Errorf("generate %s: minLength is greater than maxLength", ctxPath)
+	}
+	if s.Pattern == nil {
+		return g.text(lo, hi), nil
+	}
+	// Length of the pattern matches is limited only by the declared facets.
+	lo, hi = 0, math.MaxInt
+	if s.MinLength != nil {
+		lo = int(*s.MinLength)
+	}
+	if s.MaxLength != nil {
+		hi = int(*s.MaxLength)
+	}
+	re, err := syntax.Parse(s.Pattern.String(), syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("generate %s: parse pattern %s: %w", ctxPath, s.Pattern.String(), err)
+	}
+	re = re.Simplify()
+	// Unbounded repetitions are allowed to grow up to the length limits.
+	repeat := generateRepeat
+	if s.MinLength != nil && int(*s.MinLength)+generateRepeat > repeat {
+		repeat = int(*s.MinLength) + generateRepeat
+	}
+	for i := 0; i < generateAttempts; i++ {
+		var sb strings.Builder
+		g.regexp(re, &sb, repeat)
+		v := sb.String()
+		if n := len(v); n >= lo && n <= hi && s.Pattern.MatchString(v) {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("generate %s: cannot generate string that matches pattern %s and length facets",
+		ctxPath, s.Pattern.String())
+}
+
+// lengthRange returns the range of lengths of generated strings.
+func (g *generator) lengthRange(f LengthFacets) (int, int) {
+	lo, hi := 0, 0
+	if f.MinLength != nil {
+		lo = int(*f.MinLength)
+	}
+	hi = lo + 2*generateRepeat + 2
+	if f.MaxLength != nil && int(*f.MaxLength) < hi {
+		hi = int(*f.MaxLength)
+	}
+	if f.MaxLength == nil && f.MinLength == nil {
+		// Empty strings are valid, but hardly useful as sample data.
+		lo = 1
+	}
+	return lo, hi
+}
+
+func (g *generator) file(s *FileShape, ctxPath string) (any, error) {
+	lo, hi := g.lengthRange(s.LengthFacets)
+	if lo > hi {
+		return nil, fmt.Errorf("generate %s: minLength is greater than maxLength", ctxPath)
+	}
+	// Alphanumeric characters are valid base64 content.
+	return g.text(lo, hi), nil
+}
+
+// text returns a random alphanumeric string with the length in range [lo, hi].
+func (g *generator) text(lo, hi int) string {
+	n := lo
+	if hi > lo {
+		n += g.rng.Intn(hi - lo + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = generateAlphabet[g.rng.Intn(len(generateAlphabet))]
+	}
+	return string(b)
+}
+
+// regexp writes a random string matched by the simplified regular expression.
+// Assertions are ignored, so the result must be verified by the caller.
+func (g *generator) regexp(re *syntax.Regexp, sb *strings.Builder, repeat int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(g.runeOf(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(generateAlphabet[g.rng.Intn(len(generateAlphabet))])
+	case syntax.OpCapture:
+		g.regexp(re.Sub[0], sb, repeat)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(sub, sb, repeat)
+		}
+	case syntax.OpAlternate:
+		g.regexp(re.Sub[g.rng.Intn(len(re.Sub))], sb, repeat)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, repeat
+		switch re.Op {
+		case syntax.OpPlus:
+			lo, hi = 1, repeat+1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo, hi = re.Min, re.Max
+			if hi < 0 {
+				hi = lo + repeat
+			}
+		}
+		n := lo + g.rng.Intn(hi-lo+1)
+		for i := 0; i < n; i++ {
+			g.regexp(re.Sub[0], sb, repeat)
+		}
+	default:
+		// Empty matches and assertions do not produce characters.
+	}
+}
+
+// runeOf returns a random rune of the character class. Printable ASCII characters are preferred.
+func (g *generator) runeOf(ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	if len(ranges) == 0 {
+		return 'a'
+	}
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := g.rng.Intn(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
+
+func (g *generator) integer(s *IntegerShape, ctxPath string) (any, error) {
+	if len(s.Enum) > 0 {
+		return g.enum(s.Enum), nil
+	}
+	var lo, hi *big.Int
+	if s.Format != nil {
+		if size, ok := SetOfIntegerFormats[*s.Format]; ok {
+			bits := uint(8) << uint(size)
+			hi = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits-1), big.NewInt(1))
+			lo = new(big.Int).Neg(new(big.Int).Add(hi, big.NewInt(1)))
+		}
+	}
+	span := big.NewInt(generateIntegerSpan)
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		lo, hi = maxBigInt(lo, s.Minimum), minBigInt(hi, s.Maximum)
+	case s.Minimum != nil:
+		lo = maxBigInt(lo, s.Minimum)
+		hi = minBigInt(hi, new(big.Int).Add(lo, span))
+	case s.Maximum != nil:
+		hi = minBigInt(hi, s.Maximum)
+		lo = maxBigInt(lo, new(big.Int).Sub(hi, span))
+	default:
+		lo, hi = maxBigInt(lo, big.NewInt(0)), minBigInt(hi, span)
+	}
+
+	step := big.NewInt(1)
+	if s.MultipleOf != nil && *s.MultipleOf >= 1 && *s.MultipleOf == math.Trunc(*s.MultipleOf) {
+		new(big.Float).SetFloat64(*s.MultipleOf).Int(step)
+	}
+	// Generate k in [ceil(lo/step), floor(hi/step)] and return k*step.
+	kLo, m := new(big.Int).DivMod(lo, step, new(big.Int))
+	if m.Sign() != 0 {
+		kLo.Add(kLo, big.NewInt(1))
+	}
+	kHi := new(big.Int).Div(hi, step)
+	if kLo.Cmp(kHi) > 0 {
+		return nil, fmt.Errorf("generate %s: no integer satisfies minimum, maximum and multipleOf", ctxPath)
+	}
+	n := new(big.Int).Sub(kHi, kLo)
+	n.Add(n, big.NewInt(1))
+	k := new(big.Int).Rand(g.rng, n)
+	k.Add(k, kLo)
+	val := k.Mul(k, step)
+	if val.IsInt64() {
+		return int(val.Int64()), nil
+	}
+	return val, nil
+}
+
+func minBigInt(a, b *big.Int) *big.Int {
+	if a == nil || b.Cmp(a) < 0 {
+		return b
+	}
+	return a
+}
+
+func maxBigInt(a, b *big.Int) *big.Int {
+	if a == nil || b.Cmp(a) > 0 {
+		return b
+	}
+	return a
+}
+
+func (g *generator) number(s *NumberShape, ctxPath string) (any, error) {
+	if len(s.Enum) > 0 {
+		return g.enum(s.Enum), nil
+	}
+	lo, hi := 0.0, float64(generateIntegerSpan)
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		lo, hi = *s.Minimum, *s.Maximum
+	case s.Minimum != nil:
+		lo, hi = *s.Minimum, *s.Minimum+generateIntegerSpan
+	case s.Maximum != nil:
+		lo, hi = *s.Maximum-generateIntegerSpan, *s.Maximum
+	}
+	if lo > hi {
+		return nil, fmt.Errorf("generate %s: minimum is greater than maximum", ctxPath)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		step := *s.MultipleOf
+		kLo, kHi := math.Ceil(lo/step), math.Floor(hi/step)
+		// Rounding errors may move the bounds outside the range.
+		if kLo*step < lo {
+			kLo++
+		}
+		if kHi*step > hi {
+			kHi--
+		}
+		if kLo > kHi || kHi-kLo > math.MaxInt64/2 {
+			return nil, fmt.Errorf("generate %s: no number satisfies minimum, maximum and multipleOf", ctxPath)
+		}
+		k := kLo + float64(g.rng.Int63n(int64(kHi-kLo)+1))
+		return k * step, nil
+	}
+	v := lo + g.rng.Float64()*(hi-lo)
+	if s.Format != nil && *s.Format == "float" {
+		if f := float64(float32(v)); f >= lo && f <= hi {
+			v = f
+		}
+	}
+	return v, nil
+}
+
+func (g *generator) time() time.Time {
+	start := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(g.rng.Int63n(30*365*24*60*60)) * time.Second)
+}
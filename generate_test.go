@@ -0,0 +1,275 @@
+package raml
+
+import (
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Code:
+    type: string
+    pattern: ^[A-Z]{3}-[0-9]{2,4}$
+  Slug:
+    type: string
+    pattern: '[a-z]+'
+    minLength: 10
+    maxLength: 12
+  Name:
+    type: string
+    minLength: 2
+    maxLength: 5
+  Tiny:
+    type: integer
+    format: int8
+  Even:
+    type: integer
+    minimum: -10
+    maximum: 10
+    multipleOf: 2
+  Price:
+    type: number
+    minimum: 0.5
+    maximum: 2
+    multipleOf: 0.25
+  Color:
+    type: string
+    enum: [red, green]
+  Tags:
+    type: array
+    items:
+      type: integer
+      minimum: 1
+      maximum: 3
+    minItems: 3
+    maxItems: 3
+    uniqueItems: true
+  Pet:
+    discriminator: kind
+    properties:
+      kind: string
+      name: Name
+  Cat:
+    type: Pet
+    properties:
+      lives: Tiny
+  Dog:
+    type: Pet
+    discriminatorValue: doggo
+    properties:
+      good: boolean
+  Animal: Cat | Dog
+  Node:
+    properties:
+      value: Code
+      next?: Node
+      children?: Node[]
+  Dates:
+    properties:
+      dt: datetime
+      http:
+        type: datetime
+        format: rfc2616
+      dto: datetime-only
+      do: date-only
+      to: time-only
+      file?: file
+      nothing: nil
+      anything: any
+  Labels:
+    minProperties: 2
+    properties:
+      /^x-[a-z]+$/: string
+  Order:
+    properties:
+      code: Code
+      slug: Slug
+      even: Even
+      price: Price
+      color: Color
+      tags: Tags
+      pets: Animal[]
+      node: Node
+      dates: Dates
+      labels: Labels
+      note?: nil | string
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		name, shape := pair.Key, pair.Value
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 50; seed++ {
+				v, errGen := Generate(shape, OptGenerateSeed(seed))
+				require.NoError(t, errGen, "seed %d", seed)
+				require.NoError(t, shape.Validate(v), "seed %d: %v", seed, v)
+			}
+		})
+	}
+
+	order, _ := lib.Types.Get("Order")
+	first, err := Generate(order, OptGenerateSeed(42))
+	require.NoError(t, err)
+	second, err := Generate(order, OptGenerateSeed(42))
+	require.NoError(t, err)
+	require.Equal(t, first, second, "generation must be reproducible with the same seed")
+
+	tiny, _ := lib.Types.Get("Tiny")
+	even, _ := lib.Types.Get("Even")
+	tags, _ := lib.Types.Get("Tags")
+	animal, _ := lib.Types.Get("Animal")
+	for seed := int64(0); seed < 50; seed++ {
+		v, _ := Generate(tiny, OptGenerateSeed(seed))
+		require.True(t, v.(int) >= -128 && v.(int) <= 127)
+		v, _ = Generate(even, OptGenerateSeed(seed))
+		require.Zero(t, v.(int)%2)
+		v, _ = Generate(tags, OptGenerateSeed(seed))
+		require.ElementsMatch(t, []any{1, 2, 3}, v)
+		v, _ = Generate(animal, OptGenerateSeed(seed))
+		require.Contains(t, []any{"Cat", "doggo"}, v.(map[string]any)["kind"])
+	}
+}
+
+func TestGenerate_UniqueItems(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Colors:
+    type: array
+    items:
+      enum: [red, green]
+    uniqueItems: true
+  Cat:
+    properties:
+      kind:
+        enum: [cat]
+  Dog:
+    properties:
+      kind:
+        enum: [dog]
+  Pets:
+    type: (Cat | Dog)[]
+    uniqueItems: true
+  Impossible:
+    type: array
+    items:
+      enum: [red, green]
+    minItems: 3
+    uniqueItems: true
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+
+	for _, name := range []string{"Colors", "Pets"} {
+		shape, _ := lib.Types.Get(name)
+		for seed := int64(0); seed < 300; seed++ {
+			v, errGen := Generate(shape, OptGenerateSeed(seed))
+			require.NoError(t, errGen, "%s: seed %d", name, seed)
+			require.NoError(t, shape.Validate(v), "%s: seed %d: %v", name, seed, v)
+		}
+	}
+	impossible, _ := lib.Types.Get("Impossible")
+	_, err = Generate(impossible, OptGenerateSeed(1))
+	require.ErrorContains(t, err, "cannot generate 3 unique items")
+}
+
+func TestGenerate_Options(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Node:
+    properties:
+      next?: Node
+  Loop:
+    properties:
+      next: Loop
+  Sample:
+    properties:
+      id: integer
+    examples:
+      one:
+        id: 1
+      two:
+        id: 2
+  Big:
+    type: integer
+    minimum: 9223372036854775000
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+
+	t.Run("recursion depth", func(t *testing.T) {
+		node, _ := lib.Types.Get("Node")
+		v, errGen := Generate(node, OptGenerateSeed(1), OptGenerateMaxDepth(3), OptGenerateOptionalPercent(100))
+		require.NoError(t, errGen)
+		depth := 0
+		for m, ok := v.(map[string]any); ok; m, ok = m["next"].(map[string]any) {
+			depth++
+		}
+		require.Equal(t, 3, depth)
+	})
+
+	t.Run("infinite recursion", func(t *testing.T) {
+		loop, _ := lib.Types.Get("Loop")
+		_, errGen := Generate(loop, OptGenerateSeed(1))
+		require.ErrorContains(t, errGen, "maximum depth exceeded")
+	})
+
+	t.Run("prefer examples", func(t *testing.T) {
+		sample, _ := lib.Types.Get("Sample")
+		for seed := int64(0); seed < 10; seed++ {
+			v, errGen := Generate(sample, OptGenerateSeed(seed), OptGeneratePreferExamples(true))
+			require.NoError(t, errGen)
+			require.Contains(t, []any{1, 2}, v.(map[string]any)["id"])
+		}
+	})
+
+	t.Run("big integers", func(t *testing.T) {
+		huge, _ := lib.Types.Get("Big")
+		for seed := int64(0); seed < 10; seed++ {
+			v, errGen := Generate(huge, OptGenerateSeed(seed))
+			require.NoError(t, errGen)
+			if b, ok := v.(*big.Int); ok {
+				require.False(t, b.IsInt64())
+			}
+			require.NoError(t, huge.Validate(v))
+		}
+	})
+
+	t.Run("wrapped shape", func(t *testing.T) {
+		wrapped, errParse := ParseFromString(content, "library.raml", t.TempDir())
+		require.NoError(t, errParse)
+		node, _ := wrapped.EntryPoint().(*Library).Types.Get("Node")
+		_, errGen := Generate(node)
+		require.Error(t, errGen)
+	})
+}
+
+func TestGenerator_Regexp(t *testing.T) {
+	tests := []string{
+		`^[a-f0-9]{8}-[a-f0-9]{4}$`,
+		`^(foo|bar)+baz?$`,
+		`^\d{3}\s\w+$`,
+		`^[^a-z]{2,}$`,
+		`^.+@example\.com$`,
+	}
+	for _, pattern := range tests {
+		t.Run(pattern, func(t *testing.T) {
+			s := &StringShape{BaseShape: &BaseShape{}, StringFacets: StringFacets{Pattern: regexp.MustCompile(pattern)}}
+			g := &generator{opts: generateOptions{maxDepth: generateDefaultMaxDepth}}
+			OptGenerateSeed(1).Apply(&g.opts)
+			g.rng = g.opts.rng
+			for i := 0; i < 20; i++ {
+				v, err := g.string(s, "$")
+				require.NoError(t, err)
+				require.Regexp(t, pattern, v)
+			}
+		})
+	}
+}
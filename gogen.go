@@ -0,0 +1,723 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultGoPackage is the package name of generated Go code.
+	DefaultGoPackage = "types"
+
+	goImportRAML = "github.com/acronis/go-raml/v2"
+)
+
+// goInitialisms contains words that are written in upper case in Go identifiers.
+var goInitialisms = map[string]struct{}{
+	"API": {}, "HTML": {}, "HTTP": {}, "HTTPS": {}, "ID": {}, "IP": {}, "JSON": {}, "SQL": {},
+	"URI": {}, "URL": {}, "UUID": {}, "XML": {},
+}
+
+type GoGeneratorOptions struct {
+	packageName string
+	libraryPath string
+}
+
+type GoGeneratorOpt interface {
+	apply(*GoGeneratorOptions)
+}
+
+type optGoPackage struct{ name string }
+
+func (o optGoPackage) apply(c *GoGeneratorOptions) { c.packageName = o.name }
+
+// WithGoPackage sets the package name of generated code.
+func WithGoPackage(name string) GoGeneratorOpt {
+	return optGoPackage{name}
+}
+
+type optGoLibraryPath struct{ path string }
+
+func (o optGoLibraryPath) apply(c *GoGeneratorOptions) { c.libraryPath = o.path }
+
+// WithGoLibraryPath sets the path of the RAML library that generated Validate methods parse at runtime.
+// By default, the location of the library is used.
+func WithGoLibraryPath(path string) GoGeneratorOpt {
+	return optGoLibraryPath{path}
+}
+
+// goDecl is a generated top-level declaration.
+type goDecl struct {
+	name string
+	code string
+}
+
+// GoGenerator generates Go types from the unwrapped types of a RAML library.
+//
+// Objects are generated as structs with json tags, optional properties become pointers with "omitempty".
+// Enums become typed constants, discriminated unions become sealed interfaces with a JSON wrapper type,
+// recursive shapes become pointers to the named type. Every generated type has a Validate method that
+// validates the value against the source RAML type. The output is gofmt-clean and deterministic.
+type GoGenerator struct {
+	ShapeVisitor[string]
+
+	opts GoGeneratorOptions
+
+	resolver *namedTypeResolver
+	names    declNames
+	decls    map[string]*goDecl
+	typeKeys map[string]string
+	// typeExprs maps Go names of named types to Go type expressions that refer to them.
+	typeExprs map[string]string
+	imports   map[string]struct{}
+	// recursion maps IDs of shapes being declared to their Go names.
+	recursion map[int64]string
+
+	// root and path identify the shape of the next anonymous declaration in the library.
+	root string
+	path []string
+}
+
+func NewGoGenerator(opt ...GoGeneratorOpt) *GoGenerator {
+	g := &GoGenerator{opts: GoGeneratorOptions{packageName: DefaultGoPackage}}
+	for _, o := range opt {
+		o.apply(&g.opts)
+	}
+	return g
+}
+
+// Generate generates Go source code for all types of the library. The library must be unwrapped.
+func (g *GoGenerator) Generate(lib *Library) ([]byte, error) {
+	g.resolver = newNamedTypeResolver(lib)
+	g.names = newDeclNames()
+	g.decls = make(map[string]*goDecl)
+	g.typeKeys = make(map[string]string)
+	g.typeExprs = make(map[string]string)
+	g.imports = make(map[string]struct{})
+	g.recursion = make(map[int64]string)
+
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.IsUnwrapped() {
+			return nil, fmt.Errorf("type %s must be unwrapped", pair.Key)
+		}
+		g.declareNamed(namedType{location: lib.Location, name: pair.Key, shape: pair.Value})
+	}
+
+	libraryPath := g.opts.libraryPath
+	if libraryPath == "" {
+		libraryPath = lib.Location
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by raml gen go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.opts.packageName)
+	if len(g.decls) > 0 {
+		g.imports["fmt"] = struct{}{}
+		g.imports["strconv"] = struct{}{}
+		g.imports["strings"] = struct{}{}
+		g.imports["sync"] = struct{}{}
+		g.imports[goImportRAML] = struct{}{}
+	}
+	g.writeImports(&buf)
+	if len(g.decls) > 0 {
+		fmt.Fprintf(&buf, goRuntimeTemplate, strconv.Quote(libraryPath))
+	}
+	names := make([]string, 0, len(g.decls))
+	for name := range g.decls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteString("\n")
+		buf.WriteString(g.decls[name].code)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format source: %w", err)
+	}
+	return out, nil
+}
+
+func (g *GoGenerator) writeImports(buf *bytes.Buffer) {
+	if len(g.imports) == 0 {
+		return
+	}
+	var std, ext []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			ext = append(ext, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(ext)
+	buf.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(buf, "\t%q\n", imp)
+	}
+	if len(std) > 0 && len(ext) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, imp := range ext {
+		fmt.Fprintf(buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n")
+}
+
+// declareNamed declares the named type and returns the Go type expression that refers to it.
+func (g *GoGenerator) declareNamed(nt namedType) string {
+	key := nt.location + "#" + nt.name
+	if goName, ok := g.typeKeys[key]; ok {
+		if expr, found := g.typeExprs[goName]; found {
+			return expr
+		}
+		return goName
+	}
+	prefix := g.resolver.prefix(nt.location)
+	goName := g.names.unique(goIdent(nt.name), prefix)
+	g.typeKeys[key] = goName
+
+	restore := g.names.reserve(goName)
+	prevRoot, prevPath := g.root, g.path
+	g.root, g.path = prefix+nt.name, nil
+	defer func() {
+		restore()
+		g.root, g.path = prevRoot, prevPath
+	}()
+
+	expr := g.typeExpr(nt.shape)
+	if _, ok := g.decls[goName]; !ok {
+		// Scalars, arrays and references to other types are declared as defined types.
+		g.addDecl(goName, g.docComment(goName, nt.shape)+
+			fmt.Sprintf("type %s %s\n", goName, expr)+g.validateMethod(goName, "v"))
+		expr = goName
+	}
+	g.typeExprs[goName] = expr
+	return expr
+}
+
+func (g *GoGenerator) addDecl(name, code string) {
+	g.decls[name] = &goDecl{name: name, code: code}
+}
+
+// typeExpr returns the Go type expression of the shape.
+func (g *GoGenerator) typeExpr(base *BaseShape) string {
+	if name, ok := g.recursion[base.ID]; ok {
+		return name
+	}
+	if name, ok := g.referencedType(base); ok {
+		return name
+	}
+	return g.Visit(base.Shape)
+}
+
+// withContext evaluates f with the name and the path of the next anonymous declaration.
+func (g *GoGenerator) withContext(hint string, segment string, f func() string) string {
+	prevPath := g.path
+	g.path = append(g.path[:len(g.path):len(g.path)], segment)
+	defer func() {
+		g.path = prevPath
+	}()
+	return withHint(&g.names, hint, f)
+}
+
+// referencedType returns the Go type expression of the named type the shape refers to.
+func (g *GoGenerator) referencedType(base *BaseShape) (string, bool) {
+	nt, ok := g.resolver.reference(base)
+	if !ok {
+		return "", false
+	}
+	return g.declareNamed(nt), true
+}
+
+func (g *GoGenerator) Visit(s Shape) string {
+	switch shapeType := s.(type) {
+	case *ObjectShape:
+		return g.VisitObjectShape(shapeType)
+	case *ArrayShape:
+		return g.VisitArrayShape(shapeType)
+	case *StringShape:
+		return g.VisitStringShape(shapeType)
+	case *NumberShape:
+		return g.VisitNumberShape(shapeType)
+	case *IntegerShape:
+		return g.VisitIntegerShape(shapeType)
+	case *BooleanShape:
+		return g.VisitBooleanShape(shapeType)
+	case *FileShape:
+		return g.VisitFileShape(shapeType)
+	case *UnionShape:
+		return g.VisitUnionShape(shapeType)
+	case *NilShape:
+		return g.VisitNilShape(shapeType)
+	case *AnyShape:
+		return g.VisitAnyShape(shapeType)
+	case *DateTimeShape:
+		return g.VisitDateTimeShape(shapeType)
+	case *DateTimeOnlyShape:
+		return g.VisitDateTimeOnlyShape(shapeType)
+	case *DateOnlyShape:
+		return g.VisitDateOnlyShape(shapeType)
+	case *TimeOnlyShape:
+		return g.VisitTimeOnlyShape(shapeType)
+	case *JSONShape:
+		return g.VisitJSONShape(shapeType)
+	case *RecursiveShape:
+		return g.VisitRecursiveShape(shapeType)
+	default:
+		return "interface{}"
+	}
+}
+
+func (g *GoGenerator) VisitObjectShape(s *ObjectShape) string {
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		if s.PatternProperties != nil && s.PatternProperties.Len() == 1 {
+			pp := s.PatternProperties.Oldest().Value
+			return "map[string]" + g.withContext(g.names.hint+"Value", pp.Pattern.String(), func() string {
+				return g.typeExpr(pp.Base)
+			})
+		}
+		return "map[string]interface{}"
+	}
+
+	name := g.names.next()
+	g.recursion[s.ID] = name
+	g.addDecl(name, "") // Occupy the name before generating fields.
+	defer delete(g.recursion, s.ID)
+
+	var fields strings.Builder
+	fieldNames := make(map[string]struct{})
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		fieldName := goIdent(prop.Name)
+		for i := 2; ; i++ {
+			if _, ok := fieldNames[fieldName]; !ok {
+				break
+			}
+			fieldName = goIdent(prop.Name) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = struct{}{}
+
+		typ := g.withContext(name+fieldName, prop.Name, func() string {
+			return g.typeExpr(prop.Base)
+		})
+		tag := prop.Name
+		if !prop.Required {
+			if !goNilable(typ) {
+				typ = "*" + typ
+			}
+			tag += ",omitempty"
+		}
+		if prop.Base.Description != nil {
+			fields.WriteString(goComment(*prop.Base.Description, "\t"))
+		}
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", fieldName, typ, tag)
+	}
+
+	g.addDecl(name, g.docComment(name, s.Base())+
+		fmt.Sprintf("type %s struct {\n%s}\n", name, fields.String())+
+		g.validateMethod(name, "v"))
+	return name
+}
+
+func (g *GoGenerator) VisitArrayShape(s *ArrayShape) string {
+	if s.Items == nil {
+		return "[]interface{}"
+	}
+	return "[]" + g.withContext(g.names.hint+"Item", "[]", func() string {
+		return g.typeExpr(s.Items)
+	})
+}
+
+func (g *GoGenerator) VisitStringShape(s *StringShape) string {
+	return g.enumOrType(s.Base(), s.Enum, "string")
+}
+
+func (g *GoGenerator) VisitIntegerShape(s *IntegerShape) string {
+	typ := "int64"
+	if s.Format != nil {
+		switch *s.Format {
+		case "int8":
+			typ = "int8"
+		case "int16":
+			typ = "int16"
+		case "int32", "int":
+			typ = "int32"
+		}
+	}
+	return g.enumOrType(s.Base(), s.Enum, typ)
+}
+
+func (g *GoGenerator) VisitNumberShape(s *NumberShape) string {
+	typ := "float64"
+	if s.Format != nil && *s.Format == "float" {
+		typ = "float32"
+	}
+	return g.enumOrType(s.Base(), s.Enum, typ)
+}
+
+func (g *GoGenerator) VisitBooleanShape(s *BooleanShape) string {
+	return g.enumOrType(s.Base(), s.Enum, "bool")
+}
+
+// enumOrType declares a typed enum if the shape has enum values, otherwise it returns the Go type.
+func (g *GoGenerator) enumOrType(base *BaseShape, enum Nodes, typ string) string {
+	if len(enum) == 0 {
+		return typ
+	}
+	name := g.names.next()
+	var consts strings.Builder
+	constNames := make(map[string]struct{})
+	for i, e := range enum {
+		constName := name + goIdent(fmt.Sprint(e.Value))
+		if _, ok := constNames[constName]; ok || constName == name {
+			constName = name + strconv.Itoa(i)
+		}
+		constNames[constName] = struct{}{}
+		fmt.Fprintf(&consts, "\t%s %s = %s\n", constName, name, goLiteral(e.Value))
+	}
+	g.addDecl(name, g.docComment(name, base)+
+		fmt.Sprintf("type %s %s\n\nconst (\n%s)\n", name, typ, consts.String())+
+		g.validateMethod(name, "v"))
+	return name
+}
+
+func (g *GoGenerator) VisitFileShape(_ *FileShape) string {
+	return "[]byte"
+}
+
+func (g *GoGenerator) VisitUnionShape(s *UnionShape) string {
+	var members []*BaseShape
+	nullable := false
+	for _, item := range s.AnyOf {
+		if _, ok := item.Shape.(*NilShape); ok {
+			nullable = true
+			continue
+		}
+		members = append(members, item)
+	}
+	switch {
+	case len(members) == 0:
+		return "interface{}"
+	case len(members) == 1:
+		typ := g.withContext(g.names.hint, "|"+strconv.Itoa(g.memberIndex(s, members[0])), func() string {
+			return g.typeExpr(members[0])
+		})
+		if nullable && !goNilable(typ) {
+			typ = "*" + typ
+		}
+		return typ
+	case isDiscriminatedUnion(members):
+		return g.declareSealedUnion(s, members)
+	default:
+		return "interface{}"
+	}
+}
+
+func (g *GoGenerator) memberIndex(s *UnionShape, member *BaseShape) int {
+	for i, item := range s.AnyOf {
+		if item == member {
+			return i
+		}
+	}
+	return -1
+}
+
+// isDiscriminatedUnion reports whether all members are objects with a discriminator.
+func isDiscriminatedUnion(members []*BaseShape) bool {
+	for _, m := range members {
+		if rs, ok := m.Shape.(*RecursiveShape); ok {
+			m = rs.Head
+		}
+		obj, ok := m.Shape.(*ObjectShape)
+		if !ok || obj.Discriminator == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// declareSealedUnion declares the sealed interface of the discriminated union and returns its JSON wrapper type.
+// A nil member is represented by the nil interface value.
+func (g *GoGenerator) declareSealedUnion(s *UnionShape, members []*BaseShape) string {
+	name := g.names.next()
+	wrapper := g.names.unique(name+"JSON", "")
+	marker := "is" + name
+	g.addDecl(name, "")
+	g.addDecl(wrapper, "")
+
+	var discriminator string
+	var methods, cases strings.Builder
+	memberNames := make([]string, len(members))
+	for i, m := range members {
+		head := m
+		if rs, ok := m.Shape.(*RecursiveShape); ok {
+			head = rs.Head
+		}
+		memberName := g.withContext(name+goIdent(head.TypeLabel), "|"+strconv.Itoa(g.memberIndex(s, m)),
+			func() string {
+				return g.typeExpr(head)
+			})
+		memberNames[i] = memberName
+		obj := head.Shape.(*ObjectShape)
+		discriminator = *obj.Discriminator
+		value, err := json.Marshal(discriminatorValueOf(head, obj))
+		if err != nil {
+			value = []byte(strconv.Quote(fmt.Sprint(discriminatorValueOf(head, obj))))
+		}
+		fmt.Fprintf(&methods, "func (%s) %s() {}\n\n", memberName, marker)
+		fmt.Fprintf(&cases, "\tcase %s:\n\t\tvar v %s\n\t\terr := json.Unmarshal(data, &v)\n\t\treturn v, err\n",
+			strconv.Quote(string(value)), memberName)
+	}
+	delete(g.decls, wrapper)
+	g.addDecl(name, g.sealedUnionCode(s, name, wrapper, marker, discriminator, memberNames,
+		methods.String(), cases.String()))
+	g.imports["encoding/json"] = struct{}{}
+	return wrapper
+}
+
+func (g *GoGenerator) sealedUnionCode(
+	s *UnionShape, name, wrapper, marker, discriminator string, memberNames []string, methods, cases string,
+) string {
+	var b strings.Builder
+	b.WriteString(g.docComment(name, s.Base()))
+	fmt.Fprintf(&b, "//\n// %s is a discriminated union of %s. Use %s to marshal and unmarshal it as JSON.\n",
+		name, strings.Join(memberNames, ", "), wrapper)
+	fmt.Fprintf(&b, "type %s interface {\n\t%s()\n\tValidate() error\n}\n\n", name, marker)
+	b.WriteString(methods)
+	fmt.Fprintf(&b, "// %s wraps %s to marshal and unmarshal it as JSON.\n", wrapper, name)
+	fmt.Fprintf(&b, "type %s struct {\n\t%s\n}\n\n", wrapper, name)
+	fmt.Fprintf(&b, "// MarshalJSON implements json.Marshaler.\n")
+	fmt.Fprintf(&b, "func (u %s) MarshalJSON() ([]byte, error) {\n\treturn json.Marshal(u.%s)\n}\n\n", wrapper, name)
+	fmt.Fprintf(&b, "// UnmarshalJSON implements json.Unmarshaler.\n")
+	fmt.Fprintf(&b, "func (u *%s) UnmarshalJSON(data []byte) error {\n", wrapper)
+	fmt.Fprintf(&b, "\tif string(data) == \"null\" {\n\t\tu.%s = nil\n\t\treturn nil\n\t}\n", name)
+	fmt.Fprintf(&b, "\tv, err := Unmarshal%s(data)\n\tif err != nil {\n\t\treturn err\n\t}\n", name)
+	fmt.Fprintf(&b, "\tu.%s = v\n\treturn nil\n}\n\n", name)
+	fmt.Fprintf(&b, "// Validate validates the wrapped value against its RAML type.\n")
+	fmt.Fprintf(&b, "func (u %s) Validate() error {\n", wrapper)
+	fmt.Fprintf(&b, "\tif u.%s == nil {\n\t\treturn fmt.Errorf(\"%s is not set\")\n\t}\n", name, name)
+	fmt.Fprintf(&b, "\treturn u.%s.Validate()\n}\n\n", name)
+	fmt.Fprintf(&b, "// Unmarshal%s decodes the member of %s selected by the %q discriminator.\n",
+		name, name, discriminator)
+	fmt.Fprintf(&b, "func Unmarshal%s(data []byte) (%s, error) {\n", name, name)
+	b.WriteString("\tvar probe map[string]json.RawMessage\n")
+	b.WriteString("\tif err := json.Unmarshal(data, &probe); err != nil {\n\t\treturn nil, err\n\t}\n")
+	fmt.Fprintf(&b, "\tswitch string(probe[%q]) {\n", discriminator)
+	b.WriteString(cases)
+	b.WriteString("\tdefault:\n")
+	fmt.Fprintf(&b, "\t\treturn nil, fmt.Errorf(\"unknown %s discriminator value %%s\", probe[%q])\n",
+		name, discriminator)
+	b.WriteString("\t}\n}\n")
+	return b.String()
+}
+
+func (g *GoGenerator) VisitDateTimeShape(s *DateTimeShape) string {
+	if s.Format != nil && *s.Format == DateTimeFormatRFC2616 {
+		// time.Time marshals to RFC 3339 only.
+		return "string"
+	}
+	g.imports["time"] = struct{}{}
+	return "time.Time"
+}
+
+func (g *GoGenerator) VisitDateTimeOnlyShape(_ *DateTimeOnlyShape) string {
+	return "string"
+}
+
+func (g *GoGenerator) VisitDateOnlyShape(_ *DateOnlyShape) string {
+	return "string"
+}
+
+func (g *GoGenerator) VisitTimeOnlyShape(_ *TimeOnlyShape) string {
+	return "string"
+}
+
+func (g *GoGenerator) VisitRecursiveShape(s *RecursiveShape) string {
+	typ := g.typeExpr(s.Head)
+	if !goNilable(typ) {
+		// Recursive structs must be referenced by pointers.
+		return "*" + typ
+	}
+	return typ
+}
+
+func (g *GoGenerator) VisitJSONShape(_ *JSONShape) string {
+	g.imports["encoding/json"] = struct{}{}
+	return "json.RawMessage"
+}
+
+func (g *GoGenerator) VisitAnyShape(_ *AnyShape) string {
+	return "interface{}"
+}
+
+func (g *GoGenerator) VisitNilShape(_ *NilShape) string {
+	return "interface{}"
+}
+
+func (g *GoGenerator) docComment(name string, base *BaseShape) string {
+	comment := fmt.Sprintf("// %s is generated from RAML type %s.\n", name, g.shapeRef())
+	if base.Description != nil {
+		comment += "//\n" + goComment(*base.Description, "")
+	}
+	return comment
+}
+
+// shapeRef returns the human-readable reference to the shape of the current declaration.
+func (g *GoGenerator) shapeRef() string {
+	ref := g.root
+	for _, p := range g.path {
+		if p == "[]" || strings.HasPrefix(p, "|") {
+			ref += p
+		} else {
+			ref += "." + p
+		}
+	}
+	return ref
+}
+
+func (g *GoGenerator) validateMethod(name string, recv string) string {
+	args := []string{recv, strconv.Quote(g.root)}
+	for _, p := range g.path {
+		args = append(args, strconv.Quote(p))
+	}
+	return fmt.Sprintf("\n// Validate validates the value against the RAML type.\nfunc (%s %s) Validate() error {\n"+
+		"\treturn validateRAML(%s)\n}\n", recv, name, strings.Join(args, ", "))
+}
+
+func goNilable(typ string) bool {
+	return strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") ||
+		typ == "interface{}" || typ == "json.RawMessage"
+}
+
+func goComment(text string, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			b.WriteString(indent + "//\n")
+		} else {
+			b.WriteString(indent + "// " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func goLiteral(v any) string {
+	switch val := v.(type) {
+	case string:
+		return strconv.Quote(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// goIdent converts the RAML name to an exported Go identifier.
+func goIdent(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		if _, ok := goInitialisms[strings.ToUpper(part)]; ok {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	ident := b.String()
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = "V" + ident
+	}
+	return ident
+}
+
+// goRuntimeTemplate contains helpers of generated Validate methods.
+const goRuntimeTemplate = `
+// RAMLLibraryPath is the path of the RAML library the types are generated from.
+// Validate methods parse the library on the first call.
+var RAMLLibraryPath = %s
+
+var (
+	ramlLibraryOnce sync.Once
+	ramlLibrary     *raml.Library
+	ramlLibraryErr  error
+)
+
+func loadRAMLLibrary() (*raml.Library, error) {
+	ramlLibraryOnce.Do(func() {
+		r, err := raml.ParseFromPath(RAMLLibraryPath, raml.OptWithUnwrap())
+		if err != nil {
+			ramlLibraryErr = fmt.Errorf("parse RAML library: %%w", err)
+			return
+		}
+		lib, ok := r.EntryPoint().(*raml.Library)
+		if !ok {
+			ramlLibraryErr = fmt.Errorf("%%s is not a RAML library", RAMLLibraryPath)
+			return
+		}
+		ramlLibrary = lib
+	})
+	return ramlLibrary, ramlLibraryErr
+}
+
+// validateRAML validates the value against the shape identified by the type name qualified with library
+// aliases and the path of property names, "[]" for array items and "|N" for union members.
+func validateRAML(v interface{}, typeName string, path ...string) error {
+	lib, err := loadRAMLLibrary()
+	if err != nil {
+		return err
+	}
+	names := strings.Split(typeName, ".")
+	for _, alias := range names[:len(names)-1] {
+		use, ok := lib.Uses.Get(alias)
+		if !ok || use.Link == nil {
+			return fmt.Errorf("library %%s not found", alias)
+		}
+		lib = use.Link
+	}
+	shape, ok := lib.Types.Get(names[len(names)-1])
+	if !ok {
+		return fmt.Errorf("type %%s not found", typeName)
+	}
+	for _, p := range path {
+		if rs, isRecursive := shape.Shape.(*raml.RecursiveShape); isRecursive {
+			shape = rs.Head
+		}
+		switch s := shape.Shape.(type) {
+		case *raml.ObjectShape:
+			if s.Properties == nil {
+				return fmt.Errorf("property %%s of %%s not found", p, typeName)
+			}
+			prop, found := s.Properties.Get(p)
+			if !found {
+				return fmt.Errorf("property %%s of %%s not found", p, typeName)
+			}
+			shape = prop.Base
+		case *raml.ArrayShape:
+			shape = s.Items
+		case *raml.UnionShape:
+			i, err := strconv.Atoi(strings.TrimPrefix(p, "|"))
+			if err != nil || i < 0 || i >= len(s.AnyOf) {
+				return fmt.Errorf("union member %%s of %%s not found", p, typeName)
+			}
+			shape = s.AnyOf[i]
+		default:
+			return fmt.Errorf("shape %%s of %%s not found", p, typeName)
+		}
+	}
+	if shape == nil {
+		return fmt.Errorf("shape of %%s not found", typeName)
+	}
+	return shape.Validate(v)
+}
+`
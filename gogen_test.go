@@ -0,0 +1,151 @@
+package raml
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoGenerator_Generate(t *testing.T) {
+	common := `#%RAML 1.0 Library
+types:
+  Status:
+    type: string
+    enum: [active, disabled]
+`
+	library := `#%RAML 1.0 Library
+uses:
+  common: common.raml
+types:
+  Pet:
+    discriminator: kind
+    properties:
+      kind: string
+      name: string
+  Cat:
+    type: Pet
+    properties:
+      lives:
+        type: integer
+        format: int8
+  Dog:
+    type: Pet
+    discriminatorValue: doggo
+    properties:
+      good: boolean
+  Animal: Cat | Dog
+  Node:
+    properties:
+      value: string
+      next?: Node
+  Order:
+    description: An order of pets.
+    properties:
+      id:
+        type: integer
+        format: int32
+      status: common.Status
+      created: datetime
+      note?: string
+      tags: string[]
+      pets: Animal[]
+      owner?: nil | Owner
+      extra:
+        properties:
+          weight?: number
+      labels:
+        properties:
+          /.*/: string
+      payload?: any
+  Owner:
+    properties:
+      name: string
+`
+	lib := parseTestLibrary(t, map[string]string{"common.raml": common, "library.raml": library}, "library.raml",
+		OptWithUnwrap())
+
+	g := NewGoGenerator(WithGoPackage("pets"), WithGoLibraryPath("library.raml"))
+	out, err := g.Generate(lib)
+	require.NoError(t, err)
+
+	formatted, err := format.Source(out)
+	require.NoError(t, err)
+	require.Equal(t, string(formatted), string(out), "output must be gofmt-clean")
+	_, err = parser.ParseFile(token.NewFileSet(), "types.go", out, parser.AllErrors)
+	require.NoError(t, err)
+
+	// Collapse gofmt alignment of struct fields and constants.
+	src := regexp.MustCompile(` +`).ReplaceAllString(string(out), " ")
+	for _, want := range []string{
+		"// Code generated by raml gen go. DO NOT EDIT.",
+		"package pets",
+		"var RAMLLibraryPath = \"library.raml\"",
+		// Structs with json tags and pointer-optional fields.
+		"type Order struct {",
+		"ID int32 `json:\"id\"`",
+		"Note *string `json:\"note,omitempty\"`",
+		"Created time.Time `json:\"created\"`",
+		"Tags []string `json:\"tags\"`",
+		"Owner *Owner `json:\"owner,omitempty\"`",
+		"Extra OrderExtra `json:\"extra\"`",
+		"Weight *float64 `json:\"weight,omitempty\"`",
+		"Labels map[string]string `json:\"labels\"`",
+		"Payload interface{} `json:\"payload,omitempty\"`",
+		"// An order of pets.",
+		// Enums from used libraries.
+		"Status Status `json:\"status\"`",
+		"type Status string",
+		"StatusActive Status = \"active\"",
+		// Discriminated unions.
+		"type Animal interface {",
+		"func (Cat) isAnimal() {}",
+		"func (Dog) isAnimal() {}",
+		"Pets []AnimalJSON `json:\"pets\"`",
+		"func UnmarshalAnimal(data []byte) (Animal, error) {",
+		"case \"\\\"doggo\\\"\":",
+		"case \"\\\"Cat\\\"\":",
+		"Lives int8 `json:\"lives\"`",
+		// Recursive shapes.
+		"Next *Node `json:\"next,omitempty\"`",
+		// Validation backed by the source shape.
+		"return validateRAML(v, \"Order\")",
+		"return validateRAML(v, \"Order\", \"extra\")",
+		"return validateRAML(v, \"common.Status\")",
+	} {
+		require.Contains(t, src, want)
+	}
+
+	again, err := NewGoGenerator(WithGoPackage("pets"), WithGoLibraryPath("library.raml")).Generate(lib)
+	require.NoError(t, err)
+	require.Equal(t, string(out), string(again), "output must be deterministic")
+}
+
+func TestGoGenerator_GenerateWrapped(t *testing.T) {
+	r, err := ParseFromString("#%RAML 1.0 Library\ntypes:\n  A: string\n", "library.raml", t.TempDir())
+	require.NoError(t, err)
+	_, err = NewGoGenerator().Generate(r.EntryPoint().(*Library))
+	require.Error(t, err)
+}
+
+func TestGoIdent(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "id", want: "ID"},
+		{name: "user_name", want: "UserName"},
+		{name: "api-url", want: "APIURL"},
+		{name: "createdAt", want: "CreatedAt"},
+		{name: "1st", want: "V1st"},
+		{name: "", want: "V"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, goIdent(tt.name))
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// GraphEdgeKind is the kind of a relationship between two types.
+type GraphEdgeKind string
+
+const (
+	GraphEdgeInherits    GraphEdgeKind = "inherits"
+	GraphEdgeAlias       GraphEdgeKind = "alias"
+	GraphEdgeProperty    GraphEdgeKind = "property"
+	GraphEdgeItems       GraphEdgeKind = "items"
+	GraphEdgeUnionMember GraphEdgeKind = "union-member"
+	GraphEdgeRecursive   GraphEdgeKind = "recursive"
+)
+
+// GraphNode is a named type of the graph.
+type GraphNode struct {
+	// ID is the unique identifier of the node, e.g. "common.raml#Customer".
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Library is the path to the library relative to the directory of the entrypoint.
+	Library string `json:"library"`
+
+	Location string `json:"location"`
+	stacktrace.Position
+}
+
+// GraphEdge is a relationship between two types.
+type GraphEdge struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Kind GraphEdgeKind `json:"kind"`
+	// Label is the path to the property that refers to the type, e.g. "address.city". Empty for other kinds.
+	Label string `json:"label,omitempty"`
+}
+
+// TypeGraph is the graph of relationships between the named types of libraries.
+type TypeGraph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+
+	index map[string]int
+}
+
+// graphBuilder collects the nodes and the edges of the libraries reachable from the entrypoint.
+type graphBuilder struct {
+	graph    *TypeGraph
+	baseDir  string
+	resolver *namedTypeResolver
+	// libNames maps locations of libraries to their names relative to the directory of the entrypoint.
+	libNames map[string]string
+	seen     map[GraphEdge]struct{}
+}
+
+// BuildTypeGraph builds the graph of the types declared by the library and the libraries it uses across files.
+// Properties inherited from parent types are attributed to the parents. The library may be wrapped or unwrapped,
+// recursion edges are only present in the unwrapped model.
+func BuildTypeGraph(lib *Library) *TypeGraph {
+	b := &graphBuilder{
+		graph:    &TypeGraph{index: make(map[string]int)},
+		baseDir:  filepath.Dir(lib.Location),
+		resolver: newNamedTypeResolver(lib),
+		libNames: make(map[string]string),
+		seen:     make(map[GraphEdge]struct{}),
+	}
+	var libs []*Library
+	b.collect(lib, &libs)
+	for _, l := range libs {
+		for pair := l.Types.Oldest(); pair != nil; pair = pair.Next() {
+			b.root(b.libNames[l.Location]+"#"+pair.Key, pair.Value)
+		}
+	}
+	return b.graph
+}
+
+func (b *graphBuilder) collect(lib *Library, libs *[]*Library) {
+	if lib == nil {
+		return
+	}
+	if _, ok := b.libNames[lib.Location]; ok {
+		return
+	}
+	*libs = append(*libs, lib)
+	libName := lib.Location
+	if rel, err := filepath.Rel(b.baseDir, lib.Location); err == nil {
+		libName = filepath.ToSlash(rel)
+	}
+	b.libNames[lib.Location] = libName
+	if lib.Types != nil {
+		for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+			node := GraphNode{
+				ID:       libName + "#" + pair.Key,
+				Name:     pair.Key,
+				Library:  libName,
+				Location: lib.Location,
+				Position: pair.Value.Position,
+			}
+			b.graph.index[node.ID] = len(b.graph.Nodes)
+			b.graph.Nodes = append(b.graph.Nodes, node)
+		}
+	}
+	if lib.Uses != nil {
+		for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+			b.collect(pair.Value.Link, libs)
+		}
+	}
+}
+
+func (b *graphBuilder) addEdge(from string, to string, kind GraphEdgeKind, label string) {
+	e := GraphEdge{From: from, To: to, Kind: kind, Label: label}
+	if _, ok := b.seen[e]; ok {
+		return
+	}
+	b.seen[e] = struct{}{}
+	b.graph.Edges = append(b.graph.Edges, e)
+}
+
+// named returns the node of the named type the shape refers to.
+func (b *graphBuilder) named(base *BaseShape) (string, bool) {
+	nt, ok := b.resolver.named(base)
+	if !ok {
+		return "", false
+	}
+	return b.libNames[nt.location] + "#" + nt.name, true
+}
+
+// root adds the edges of the named type.
+func (b *graphBuilder) root(from string, base *BaseShape) {
+	if target, ok := b.named(base.Alias); ok {
+		b.addEdge(from, target, GraphEdgeAlias, "")
+		return
+	}
+	if ref := referencedNamedType(base); ref != nil {
+		if target, ok := b.named(ref); ok {
+			b.addEdge(from, target, GraphEdgeAlias, "")
+			return
+		}
+	}
+	for _, parent := range base.Inherits {
+		if target, ok := b.named(parent); ok && target != from {
+			b.addEdge(from, target, GraphEdgeInherits, "")
+		}
+	}
+	b.children(from, base, "")
+}
+
+// child adds the edge to the named type the nested shape refers to or descends into the anonymous shape.
+func (b *graphBuilder) child(from string, base *BaseShape, kind GraphEdgeKind, label string) {
+	if base == nil {
+		return
+	}
+	if rs, ok := base.Shape.(*RecursiveShape); ok {
+		if target, found := b.named(rs.Head); found {
+			b.addEdge(from, target, GraphEdgeRecursive, label)
+		}
+		return
+	}
+	if target, ok := b.named(base); ok {
+		b.addEdge(from, target, kind, label)
+		return
+	}
+	for _, parent := range base.Inherits {
+		if target, ok := b.named(parent); ok {
+			b.addEdge(from, target, kind, label)
+		}
+	}
+	b.children(from, base, label)
+}
+
+func (b *graphBuilder) children(from string, base *BaseShape, label string) {
+	switch s := base.Shape.(type) {
+	case *ObjectShape:
+		inherited := inheritedPropertyIDs(base)
+		if s.Properties != nil {
+			for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				if _, ok := inherited[pair.Value.Base.ID]; !ok {
+					b.child(from, pair.Value.Base, GraphEdgeProperty, graphLabel(label, pair.Key))
+				}
+			}
+		}
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				if _, ok := inherited[pair.Value.Base.ID]; !ok {
+					b.child(from, pair.Value.Base, GraphEdgeProperty, graphLabel(label, pair.Key))
+				}
+			}
+		}
+	case *ArrayShape:
+		b.child(from, s.Items, GraphEdgeItems, label)
+	case *UnionShape:
+		for _, member := range s.AnyOf {
+			b.child(from, member, GraphEdgeUnionMember, label)
+		}
+	}
+}
+
+// inheritedPropertyIDs returns the IDs of the property shapes of the parents. Unwrapped shapes share the property
+// shapes with their parents.
+func inheritedPropertyIDs(base *BaseShape) map[int64]struct{} {
+	ids := make(map[int64]struct{})
+	for _, parent := range base.Inherits {
+		s, ok := parent.Shape.(*ObjectShape)
+		if !ok || parent.ID == base.ID {
+			continue
+		}
+		if s.Properties != nil {
+			for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				ids[pair.Value.Base.ID] = struct{}{}
+			}
+		}
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				ids[pair.Value.Base.ID] = struct{}{}
+			}
+		}
+	}
+	return ids
+}
+
+func graphLabel(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Node returns the node by ID, by qualified name "library#Name" or by name if it is unique.
+func (g *TypeGraph) Node(name string) (*GraphNode, error) {
+	if i, ok := g.index[name]; ok {
+		return &g.Nodes[i], nil
+	}
+	var found *GraphNode
+	for i := range g.Nodes {
+		if g.Nodes[i].Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("type %s is ambiguous: %s, %s", name, found.ID, g.Nodes[i].ID)
+		}
+		found = &g.Nodes[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+	return found, nil
+}
+
+// Neighborhood returns the subgraph of the types within the given number of edges from the type regardless of
+// the edge direction.
+func (g *TypeGraph) Neighborhood(name string, depth int) (*TypeGraph, error) {
+	center, err := g.Node(name)
+	if err != nil {
+		return nil, err
+	}
+	adjacent := make(map[string][]string)
+	for _, e := range g.Edges {
+		adjacent[e.From] = append(adjacent[e.From], e.To)
+		adjacent[e.To] = append(adjacent[e.To], e.From)
+	}
+	selected := map[string]struct{}{center.ID: {}}
+	frontier := []string{center.ID}
+	for i := 0; i < depth && len(frontier) > 0; i++ {
+		var next []string
+		for _, id := range frontier {
+			for _, adj := range adjacent[id] {
+				if _, ok := selected[adj]; !ok {
+					selected[adj] = struct{}{}
+					next = append(next, adj)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	sub := &TypeGraph{index: make(map[string]int)}
+	for _, n := range g.Nodes {
+		if _, ok := selected[n.ID]; ok {
+			sub.index[n.ID] = len(sub.Nodes)
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		_, fromOk := selected[e.From]
+		_, toOk := selected[e.To]
+		if fromOk && toOk {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub, nil
+}
+
+// LibraryCycles returns the cycles between libraries formed by the type relationships. Every cycle is a sorted list
+// of libraries that depend on each other.
+func (g *TypeGraph) LibraryCycles() [][]string {
+	libOf := make(map[string]string, len(g.Nodes))
+	var libs []string
+	seenLibs := make(map[string]struct{})
+	for _, n := range g.Nodes {
+		libOf[n.ID] = n.Library
+		if _, ok := seenLibs[n.Library]; !ok {
+			seenLibs[n.Library] = struct{}{}
+			libs = append(libs, n.Library)
+		}
+	}
+	deps := make(map[string]map[string]struct{})
+	for _, e := range g.Edges {
+		from, to := libOf[e.From], libOf[e.To]
+		if from == to {
+			continue
+		}
+		if deps[from] == nil {
+			deps[from] = make(map[string]struct{})
+		}
+		deps[from][to] = struct{}{}
+	}
+
+	// Tarjan's algorithm for strongly connected components.
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range sortedKeys(deps[v]) {
+			if _, ok := index[w]; !ok {
+				strongConnect(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && index[w] < lowlink[v] {
+				lowlink[v] = index[w]
+			}
+		}
+		if lowlink[v] != index[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, lib := range libs {
+		if _, ok := index[lib]; !ok {
+			strongConnect(lib)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// graphLibraries groups the nodes by library preserving the order of appearance.
+func (g *TypeGraph) graphLibraries() *orderedmap.OrderedMap[string, []GraphNode] {
+	libs := orderedmap.New[string, []GraphNode]()
+	for _, n := range g.Nodes {
+		nodes, _ := libs.Get(n.Library)
+		libs.Set(n.Library, append(nodes, n))
+	}
+	return libs
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format. Libraries are rendered as clusters.
+func (g *TypeGraph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph types {\n  rankdir=LR;\n  node [shape=box];\n")
+	i := 0
+	for pair := g.graphLibraries().Oldest(); pair != nil; pair = pair.Next() {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n    label=%s;\n", i, strconv.Quote(pair.Key))
+		for _, n := range pair.Value {
+			fmt.Fprintf(&sb, "    %s [label=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.Name))
+		}
+		sb.WriteString("  }\n")
+		i++
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		switch e.Kind {
+		case GraphEdgeInherits:
+			attrs = append(attrs, "arrowhead=empty")
+		case GraphEdgeAlias:
+			attrs = append(attrs, "style=dashed")
+		case GraphEdgeUnionMember:
+			attrs = append(attrs, "style=dotted")
+		case GraphEdgeRecursive:
+			attrs = append(attrs, "color=red")
+		case GraphEdgeProperty, GraphEdgeItems:
+		}
+		attrs = append(attrs, "label="+strconv.Quote(graphEdgeLabel(e)))
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart. Libraries are rendered as subgraphs.
+func (g *TypeGraph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = "n" + strconv.Itoa(i)
+	}
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	i := 0
+	for pair := g.graphLibraries().Oldest(); pair != nil; pair = pair.Next() {
+		fmt.Fprintf(&sb, "  subgraph lib%d [%s]\n", i, mermaidText(pair.Key))
+		for _, n := range pair.Value {
+			fmt.Fprintf(&sb, "    %s[%s]\n", ids[n.ID], mermaidText(n.Name))
+		}
+		sb.WriteString("  end\n")
+		i++
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch e.Kind {
+		case GraphEdgeInherits:
+			arrow = "==>"
+		case GraphEdgeAlias, GraphEdgeUnionMember, GraphEdgeRecursive:
+			arrow = "-.->"
+		case GraphEdgeProperty, GraphEdgeItems:
+		}
+		fmt.Fprintf(&sb, "  %s %s|%s| %s\n", ids[e.From], arrow, mermaidText(graphEdgeLabel(e)), ids[e.To])
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes the nodes and the adjacency list of the graph as JSON.
+func (g *TypeGraph) WriteJSON(w io.Writer) error {
+	type adjacentEdge struct {
+		To    string        `json:"to"`
+		Kind  GraphEdgeKind `json:"kind"`
+		Label string        `json:"label,omitempty"`
+	}
+	adjacency := make(map[string][]adjacentEdge, len(g.Nodes))
+	for _, n := range g.Nodes {
+		adjacency[n.ID] = []adjacentEdge{}
+	}
+	for _, e := range g.Edges {
+		adjacency[e.From] = append(adjacency[e.From], adjacentEdge{To: e.To, Kind: e.Kind, Label: e.Label})
+	}
+	nodes := g.Nodes
+	if nodes == nil {
+		nodes = []GraphNode{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Nodes     []GraphNode               `json:"nodes"`
+		Adjacency map[string][]adjacentEdge `json:"adjacency"`
+	}{Nodes: nodes, Adjacency: adjacency})
+}
+
+func graphEdgeLabel(e GraphEdge) string {
+	if e.Label == "" {
+		return string(e.Kind)
+	}
+	if e.Kind == GraphEdgeProperty {
+		return e.Label
+	}
+	return e.Label + " (" + string(e.Kind) + ")"
+}
+
+// mermaidText quotes the text for Mermaid labels.
+func mermaidText(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,172 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseGraphLibrary(t *testing.T, unwrap bool) *Library {
+	t.Helper()
+	files := map[string]string{
+		"api.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+types:
+  Pet:
+    properties:
+      owner: common.Person
+      tags: string[]
+  Cat:
+    type: Pet
+    properties:
+      lives: integer
+  Dog:
+    type: Pet
+  Animal: Cat | Dog
+  Shelter:
+    properties:
+      animals: Animal[]
+      address:
+        properties:
+          geo: common.Point
+  Node:
+    properties:
+      next?: Node
+  Friend: Pet
+`,
+		"common.raml": `#%RAML 1.0 Library
+types:
+  Point:
+    properties:
+      lat: number
+  Person:
+    properties:
+      location: Point
+`,
+	}
+	var opts []ParseOpt
+	if unwrap {
+		opts = append(opts, OptWithUnwrap())
+	}
+	return parseTestLibrary(t, files, "api.raml", opts...)
+}
+
+func graphSummary(g *TypeGraph) []string {
+	res := make([]string, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		res = append(res, e.From+" -"+string(e.Kind)+":"+e.Label+"-> "+e.To)
+	}
+	return res
+}
+
+func TestBuildTypeGraph(t *testing.T) {
+	expected := []string{
+		"api.raml#Pet -property:owner-> common.raml#Person",
+		"api.raml#Cat -inherits:-> api.raml#Pet",
+		"api.raml#Dog -inherits:-> api.raml#Pet",
+		"api.raml#Animal -union-member:-> api.raml#Cat",
+		"api.raml#Animal -union-member:-> api.raml#Dog",
+		"api.raml#Shelter -items:animals-> api.raml#Animal",
+		"api.raml#Shelter -property:address.geo-> common.raml#Point",
+		"api.raml#Node -property:next-> api.raml#Node",
+		"api.raml#Friend -alias:-> api.raml#Pet",
+		"common.raml#Person -property:location-> common.raml#Point",
+	}
+
+	g := BuildTypeGraph(parseGraphLibrary(t, false))
+	require.Len(t, g.Nodes, 9)
+	require.Equal(t, GraphNode{
+		ID: "common.raml#Point", Name: "Point", Library: "common.raml",
+		Location: g.Nodes[7].Location, Position: g.Nodes[7].Position,
+	}, g.Nodes[7])
+	require.Equal(t, expected, graphSummary(g))
+
+	g = BuildTypeGraph(parseGraphLibrary(t, true))
+	expected[7] = "api.raml#Node -recursive:next-> api.raml#Node"
+	require.Equal(t, expected, graphSummary(g))
+}
+
+func TestTypeGraph_Neighborhood(t *testing.T) {
+	g := BuildTypeGraph(parseGraphLibrary(t, false))
+
+	sub, err := g.Neighborhood("Person", 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"api.raml#Pet -property:owner-> common.raml#Person",
+		"common.raml#Person -property:location-> common.raml#Point",
+	}, graphSummary(sub))
+
+	sub, err = g.Neighborhood("common.raml#Point", 0)
+	require.NoError(t, err)
+	require.Len(t, sub.Nodes, 1)
+	require.Empty(t, sub.Edges)
+
+	_, err = g.Neighborhood("Missing", 1)
+	require.ErrorContains(t, err, "type Missing not found")
+}
+
+func TestTypeGraph_LibraryCycles(t *testing.T) {
+	g := &TypeGraph{
+		Nodes: []GraphNode{
+			{ID: "a#A", Library: "a"}, {ID: "b#B", Library: "b"}, {ID: "c#C", Library: "c"}, {ID: "d#D", Library: "d"},
+		},
+		Edges: []GraphEdge{
+			{From: "a#A", To: "b#B"}, {From: "b#B", To: "c#C"}, {From: "c#C", To: "a#A"}, {From: "c#C", To: "d#D"},
+		},
+	}
+	require.Equal(t, [][]string{{"a", "b", "c"}}, g.LibraryCycles())
+	require.Empty(t, BuildTypeGraph(parseGraphLibrary(t, false)).LibraryCycles())
+}
+
+func TestTypeGraph_Write(t *testing.T) {
+	g, err := BuildTypeGraph(parseGraphLibrary(t, false)).Neighborhood("Person", 1)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDOT(&buf))
+	require.Equal(t, `digraph types {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="api.raml";
+    "api.raml#Pet" [label="Pet"];
+  }
+  subgraph cluster_1 {
+    label="common.raml";
+    "common.raml#Point" [label="Point"];
+    "common.raml#Person" [label="Person"];
+  }
+  "api.raml#Pet" -> "common.raml#Person" [label="owner"];
+  "common.raml#Person" -> "common.raml#Point" [label="location"];
+}
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, g.WriteMermaid(&buf))
+	require.Equal(t, `flowchart LR
+  subgraph lib0 ["api.raml"]
+    n0["Pet"]
+  end
+  subgraph lib1 ["common.raml"]
+    n1["Point"]
+    n2["Person"]
+  end
+  n0 -->|"owner"| n2
+  n2 -->|"location"| n1
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, g.WriteJSON(&buf))
+	var doc struct {
+		Nodes     []GraphNode            `json:"nodes"`
+		Adjacency map[string][]GraphEdge `json:"adjacency"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Nodes, 3)
+	require.Equal(t, []GraphEdge{{To: "common.raml#Point", Kind: GraphEdgeProperty, Label: "location"}},
+		doc.Adjacency["common.raml#Person"])
+	require.Empty(t, doc.Adjacency["common.raml#Point"])
+}
@@ -0,0 +1,31 @@
+package raml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestFiles writes the files into the directory. File names may contain subdirectories.
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+// parseTestLibrary writes the files into a temporary directory and parses the library at the entry point path.
+func parseTestLibrary(t *testing.T, files map[string]string, entryPoint string, opts ...ParseOpt) *Library {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+	r, err := ParseFromPath(filepath.Join(dir, entryPoint), opts...)
+	require.NoError(t, err)
+	lib, ok := r.EntryPoint().(*Library)
+	require.True(t, ok)
+	return lib
+}
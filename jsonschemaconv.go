@@ -4,16 +4,61 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
 type WrapperFunc[T jsonSchemaWrapper[T]] func(conv *JSONSchemaConverter[T], core *JSONSchemaGeneric[T], src *BaseShape) T
 
+// JSONSchemaDialect selects the JSON Schema flavour produced by JSONSchemaConverter.
+type JSONSchemaDialect string
+
+const (
+	// JSONSchemaDialectDraft07 produces JSON Schema draft-07 with "#/definitions/" references.
+	JSONSchemaDialectDraft07 JSONSchemaDialect = "draft-07"
+	// JSONSchemaDialect202012 produces JSON Schema 2020-12 with "#/$defs/" references.
+	JSONSchemaDialect202012 JSONSchemaDialect = "2020-12"
+	// JSONSchemaDialectOpenAPI30 produces OpenAPI 3.0 schema objects with "#/components/schemas/" references.
+	// Nil types are expressed with "nullable", "$schema" is omitted.
+	JSONSchemaDialectOpenAPI30 JSONSchemaDialect = "openapi-3.0"
+)
+
+// DefinitionNamer returns the definition name of the named shape. The converter makes names unique
+// by appending a numeric suffix, so the namer does not need to track the names it returned before.
+type DefinitionNamer func(shape *BaseShape, entrypoint *BaseShape) string
+
+// QualifiedDefinitionName is the default DefinitionNamer. Types located in the entrypoint file keep their names,
+// types of used libraries are qualified with the library alias (e.g. "common.Error"), or with the library
+// file name if the library is not used by the entrypoint directly.
+func QualifiedDefinitionName(shape *BaseShape, entrypoint *BaseShape) string {
+	name := shape.Name
+	if name == "" {
+		name = shape.Type
+	}
+	if shape.Location == "" || shape.Location == entrypoint.Location {
+		return name
+	}
+	if entrypoint.raml != nil {
+		if lib, ok := entrypoint.raml.GetFragment(entrypoint.Location).(*Library); ok && lib.Uses != nil {
+			for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+				if pair.Value.Link != nil && pair.Value.Link.Location == shape.Location {
+					return pair.Key + "." + name
+				}
+			}
+		}
+	}
+	file := filepath.Base(shape.Location)
+	return strings.TrimSuffix(file, filepath.Ext(file)) + "." + name
+}
+
 type JSONSchemaConverterOptions[T jsonSchemaWrapper[T]] struct {
-	// omitRefs bool
-	wrap WrapperFunc[T]
+	omitRefs bool
+	wrap     WrapperFunc[T]
+	dialect  JSONSchemaDialect
+	namer    DefinitionNamer
 }
 
 type JSONSchemaConverterOpt[T jsonSchemaWrapper[T]] interface {
@@ -30,26 +75,63 @@ func WithWrapper[T jsonSchemaWrapper[T]](f WrapperFunc[T]) JSONSchemaConverterOp
 	return optWrapper[T]{f}
 }
 
-// type optOmitRefs[T jsonSchemaWrapper[T]] struct{ omitRefs bool }
+type optDialect[T jsonSchemaWrapper[T]] struct{ dialect JSONSchemaDialect }
 
-// func (o optOmitRefs[T]) apply(c *JSONSchemaConverterOptions[T]) { c.omitRefs = o.omitRefs }
-// func WithOmitRefs[T jsonSchemaWrapper[T]](b bool) JSONSchemaConverterOpt[T] {
-// 	return optOmitRefs[T]{b}
-// }
+//nolint:unused // Actually used in JSONSchemaConverter constructor.
+func (o optDialect[T]) apply(c *JSONSchemaConverterOptions[T]) { c.dialect = o.dialect }
+
+// WithDialect selects the output dialect. JSONSchemaDialectDraft07 is used by default.
+func WithDialect[T jsonSchemaWrapper[T]](dialect JSONSchemaDialect) JSONSchemaConverterOpt[T] {
+	return optDialect[T]{dialect}
+}
+
+type optOmitRefs[T jsonSchemaWrapper[T]] struct{ omitRefs bool }
+
+//nolint:unused // Actually used in JSONSchemaConverter constructor.
+func (o optOmitRefs[T]) apply(c *JSONSchemaConverterOptions[T]) { c.omitRefs = o.omitRefs }
+
+// WithOmitRefs inlines named types referenced from the entrypoint instead of emitting them as definitions.
+// Recursive types are always emitted as definitions.
+func WithOmitRefs[T jsonSchemaWrapper[T]](b bool) JSONSchemaConverterOpt[T] {
+	return optOmitRefs[T]{b}
+}
+
+type optDefinitionNamer[T jsonSchemaWrapper[T]] struct{ namer DefinitionNamer }
+
+//nolint:unused // Actually used in JSONSchemaConverter constructor.
+func (o optDefinitionNamer[T]) apply(c *JSONSchemaConverterOptions[T]) { c.namer = o.namer }
+
+// WithDefinitionNamer lets the caller name definitions. QualifiedDefinitionName is used by default.
+func WithDefinitionNamer[T jsonSchemaWrapper[T]](namer DefinitionNamer) JSONSchemaConverterOpt[T] {
+	return optDefinitionNamer[T]{namer}
+}
 
 type JSONSchemaConverter[T jsonSchemaWrapper[T]] struct {
 	ShapeVisitor[T]
 
 	definitions map[string]T
+	// names maps shape IDs to their definition names.
+	names      map[int64]string
+	entrypoint *BaseShape
 
 	opts JSONSchemaConverterOptions[T]
 }
 
 func NewJSONSchemaConverter[T jsonSchemaWrapper[T]](opt ...JSONSchemaConverterOpt[T]) (*JSONSchemaConverter[T], error) {
-	c := &JSONSchemaConverter[T]{definitions: make(map[string]T)}
+	c := &JSONSchemaConverter[T]{definitions: make(map[string]T), names: make(map[int64]string)}
 	for _, o := range opt {
 		o.apply(&c.opts)
 	}
+	if c.opts.namer == nil {
+		c.opts.namer = QualifiedDefinitionName
+	}
+	switch c.opts.dialect {
+	case "":
+		c.opts.dialect = JSONSchemaDialectDraft07
+	case JSONSchemaDialectDraft07, JSONSchemaDialect202012, JSONSchemaDialectOpenAPI30:
+	default:
+		return nil, fmt.Errorf("unsupported JSON Schema dialect: %s", c.opts.dialect)
+	}
 	if c.opts.wrap == nil {
 		if _, ok := any((*JSONSchema)(nil)).(T); !ok {
 			return nil, errors.New("NewJSONSchemaConverter requires WithWrapper for customized schemas")
@@ -65,16 +147,25 @@ func (c *JSONSchemaConverter[T]) Convert(s Shape) (T, error) {
 		return zero, fmt.Errorf("entrypoint shape must be unwrapped")
 	}
 
-	entrypointName := s.Base().Name
 	c.definitions = make(map[string]T)
-	// NOTE: Assign empty schema before traversing to definitions to occupy the name.
-	c.definitions[entrypointName] = zero
+	c.names = make(map[int64]string)
+	c.entrypoint = s.Base()
+	entrypointName, _ := c.definitionName(c.entrypoint)
 	c.definitions[entrypointName] = c.Visit(s)
 
 	core := &JSONSchemaGeneric[T]{
-		Version:     JSONSchemaVersion,
-		Ref:         "#/definitions/" + entrypointName,
-		Definitions: c.definitions,
+		Ref: c.definitionRef(entrypointName),
+	}
+	switch c.opts.dialect {
+	case JSONSchemaDialect202012:
+		core.Version = JSONSchemaVersion202012
+		core.Defs = c.definitions
+	case JSONSchemaDialectOpenAPI30:
+		// OpenAPI schema objects cannot hold definitions, they are placed into components by the caller.
+		return c.definitions[entrypointName], nil
+	default:
+		core.Version = JSONSchemaVersion
+		core.Definitions = c.definitions
 	}
 
 	if c.opts.wrap != nil {
@@ -84,7 +175,67 @@ func (c *JSONSchemaConverter[T]) Convert(s Shape) (T, error) {
 	return any(core).(T), nil
 }
 
+// Definitions returns the named schemas produced by the last conversion. In the OpenAPI 3.0 dialect,
+// they are expected to be placed into "components/schemas".
+func (c *JSONSchemaConverter[T]) Definitions() map[string]T {
+	return c.definitions
+}
+
+func (c *JSONSchemaConverter[T]) definitionRef(name string) string {
+	// Definition names are JSON pointer tokens.
+	name = strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+	switch c.opts.dialect {
+	case JSONSchemaDialect202012:
+		return "#/$defs/" + name
+	case JSONSchemaDialectOpenAPI30:
+		return "#/components/schemas/" + name
+	default:
+		return "#/definitions/" + name
+	}
+}
+
+// definitionName returns the unique definition name of the shape. If the shape has no name yet,
+// the name is reserved with an empty schema and true is returned, so the caller must fill the definition.
+func (c *JSONSchemaConverter[T]) definitionName(base *BaseShape) (string, bool) {
+	if name, ok := c.names[base.ID]; ok {
+		return name, false
+	}
+	entrypoint := c.entrypoint
+	if entrypoint == nil {
+		entrypoint = base
+	}
+	candidate := c.opts.namer(base, entrypoint)
+	name := candidate
+	for i := 2; ; i++ {
+		if _, taken := c.definitions[name]; !taken {
+			break
+		}
+		name = candidate + "_" + strconv.Itoa(i)
+	}
+	c.names[base.ID] = name
+	// NOTE: Assign empty schema to definitions to occupy the name before traversing.
+	var placeholder T
+	c.definitions[name] = placeholder
+	return name, true
+}
+
+// refTo returns the reference to the definition of the named shape, converting the shape on first use.
+func (c *JSONSchemaConverter[T]) refTo(base *BaseShape) T {
+	node := c.makeEmptySchema()
+	name, isNew := c.definitionName(base)
+	if isNew {
+		c.definitions[name] = c.Visit(base.Shape)
+	}
+	node.Generic().Ref = c.definitionRef(name)
+	return node
+}
+
 func (c *JSONSchemaConverter[T]) Visit(s Shape) T {
+	if s != nil && !c.opts.omitRefs {
+		if ref := referencedNamedType(s.Base()); ref != nil {
+			return c.refTo(ref)
+		}
+	}
 	switch shapeType := s.(type) {
 	case *ObjectShape:
 		return c.VisitObjectShape(shapeType)
@@ -132,6 +283,13 @@ func (c *JSONSchemaConverter[T]) VisitObjectShape(s *ObjectShape) T {
 	schema.MinProperties = s.MinProperties
 	schema.MaxProperties = s.MaxProperties
 	schema.AdditionalProperties = s.AdditionalProperties
+	if c.opts.dialect == JSONSchemaDialect202012 && len(s.Inherits) > 0 &&
+		s.AdditionalProperties != nil && !*s.AdditionalProperties {
+		// Closed objects that inherit other types are closed with unevaluatedProperties,
+		// which also sees properties of composed schemas.
+		schema.UnevaluatedProperties = s.AdditionalProperties
+		schema.AdditionalProperties = nil
+	}
 
 	if s.Properties != nil {
 		schema.Properties = orderedmap.New[string, T](s.Properties.Len())
@@ -173,6 +331,10 @@ func (c *JSONSchemaConverter[T]) VisitUnionShape(s *UnionShape) T {
 	node := c.makeSchemaFromBaseShape(s.Base())
 	schema := node.Generic()
 
+	if c.opts.dialect == JSONSchemaDialectOpenAPI30 {
+		return c.visitNullableUnion(s, node)
+	}
+
 	schema.AnyOf = make([]T, len(s.AnyOf))
 	for i, item := range s.AnyOf {
 		schema.AnyOf[i] = c.Visit(item.Shape)
@@ -180,6 +342,47 @@ func (c *JSONSchemaConverter[T]) VisitUnionShape(s *UnionShape) T {
 	return node
 }
 
+// visitNullableUnion converts the union for OpenAPI 3.0 that has no null type. A single typed member is merged
+// with "nullable" into the union schema. Otherwise, nil members are replaced with a schema that accepts null only,
+// since "nullable" has no effect without a type and is ignored next to "$ref".
+func (c *JSONSchemaConverter[T]) visitNullableUnion(s *UnionShape, node T) T {
+	schema := node.Generic()
+	var members []T
+	nullable := false
+	for _, item := range s.AnyOf {
+		if _, ok := item.Shape.(*NilShape); ok {
+			nullable = true
+			continue
+		}
+		members = append(members, c.Visit(item.Shape))
+	}
+	if len(members) == 0 {
+		return c.nullOnly(node)
+	}
+	if len(members) == 1 && members[0].Generic().Ref == "" {
+		// Merge the single member into the union schema, since nullable requires a type.
+		member := members[0].Generic()
+		if schema.Title != "" {
+			member.Title = schema.Title
+		}
+		if schema.Description != "" {
+			member.Description = schema.Description
+		}
+		if schema.Example != nil {
+			member.Example = schema.Example
+		}
+		if nullable {
+			member.Nullable = &nullable
+		}
+		return members[0]
+	}
+	if nullable {
+		members = append(members, c.nullOnly(c.makeEmptySchema()))
+	}
+	schema.AnyOf = members
+	return node
+}
+
 func (c *JSONSchemaConverter[T]) VisitStringShape(s *StringShape) T {
 	node := c.makeSchemaFromBaseShape(s.Base())
 	schema := node.Generic()
@@ -250,7 +453,11 @@ func (c *JSONSchemaConverter[T]) VisitFileShape(s *FileShape) T {
 	schema.Type = TypeString
 	schema.MinLength = s.MinLength
 	schema.MaxLength = s.MaxLength
-	schema.ContentEncoding = "base64"
+	if c.opts.dialect == JSONSchemaDialectOpenAPI30 {
+		schema.Format = "byte"
+	} else {
+		schema.ContentEncoding = "base64"
+	}
 
 	// TODO: JSON Schema allows for only one content media type
 	if s.FileTypes != nil {
@@ -328,10 +535,24 @@ func (c *JSONSchemaConverter[T]) VisitAnyShape(s *AnyShape) T {
 func (c *JSONSchemaConverter[T]) VisitNilShape(s *NilShape) T {
 	node := c.makeSchemaFromBaseShape(s.Base())
 	schema := node.Generic()
+	if c.opts.dialect == JSONSchemaDialectOpenAPI30 {
+		return c.nullOnly(node)
+	}
 	schema.Type = TypeNull
 	return node
 }
 
+// nullOnly makes the schema accept only null in OpenAPI 3.0 that has no null type. Nullable takes effect only
+// together with a type, and the enum excludes the values of the type.
+func (c *JSONSchemaConverter[T]) nullOnly(node T) T {
+	schema := node.Generic()
+	nullable := true
+	schema.Type = TypeObject
+	schema.Nullable = &nullable
+	schema.Enum = []any{nil}
+	return node
+}
+
 func (c *JSONSchemaConverter[T]) VisitRecursiveShape(s *RecursiveShape) T {
 	// NOTE: Recursive schema will always produce ref.
 	// Ref ignores all other keywords defined within the schema per JSON Schema spec.
@@ -339,22 +560,11 @@ func (c *JSONSchemaConverter[T]) VisitRecursiveShape(s *RecursiveShape) T {
 	// NOTE: We create empty schema because all base RAML types are allowed to have
 	// custom facets which can be recursive. RAML-JSON Schema wrapper
 	// The use of `makeSchemaFromBaseShape` will lead to infinite recursion.
-	node := c.makeEmptySchema()
-	schema := node.Generic()
-
-	head := s.Head.Shape
-	baseHead := head.Base()
-	// TODO: Type name is not unique, need pretty naming to avoid collisions.
-	definition := baseHead.Name
-	if _, ok := c.definitions[definition]; !ok {
-		// NOTE: Assign empty schema to definitions to occupy the name before traversing.
-		var placeholder T
-		c.definitions[definition] = placeholder
-		c.definitions[definition] = c.Visit(head)
+	head := s.Head
+	if ref := referencedNamedType(head); ref != nil {
+		head = ref
 	}
-	schema.Ref = "#/definitions/" + definition
-
-	return node
+	return c.refTo(head)
 }
 
 func (c *JSONSchemaConverter[T]) VisitJSONShape(s *JSONShape) T {
@@ -408,6 +618,10 @@ func (c *JSONSchemaConverter[T]) recast(src *JSONSchema) T {
 
 		Items: c.recast(src.Items),
 
+		UnevaluatedProperties: src.UnevaluatedProperties,
+		Nullable:              src.Nullable,
+		Example:               src.Example,
+
 		AdditionalProperties: src.AdditionalProperties,
 		PropertyNames:        c.recast(src.PropertyNames),
 		Type:                 src.Type,
@@ -473,6 +687,12 @@ func (c *JSONSchemaConverter[T]) recast(src *JSONSchema) T {
 			core.Definitions[k] = c.recast(v)
 		}
 	}
+	if len(src.Defs) > 0 {
+		core.Defs = make(map[string]T, len(src.Defs))
+		for k, v := range src.Defs {
+			core.Defs[k] = c.recast(v)
+		}
+	}
 	if c.opts.wrap != nil {
 		return c.opts.wrap(c, core, nil)
 	}
@@ -507,6 +727,11 @@ func (c *JSONSchemaConverter[T]) makeSchemaFromBaseShape(base *BaseShape) T {
 	if base.Example != nil {
 		core.Examples = []any{base.Example.Data.Value}
 	}
+	if c.opts.dialect == JSONSchemaDialectOpenAPI30 && len(core.Examples) > 0 {
+		// OpenAPI 3.0 schema objects allow only a single example.
+		core.Example = core.Examples[0]
+		core.Examples = nil
+	}
 	if c.opts.wrap != nil {
 		return c.opts.wrap(c, core, base)
 	}
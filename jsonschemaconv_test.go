@@ -11,81 +11,81 @@ import (
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
-// func Test_optOmitRefs_Apply(t *testing.T) {
-// 	type fields struct {
-// 		omitRefs bool
-// 	}
-// 	type args struct {
-// 		e *JSONSchemaConverterOptions[*JSONSchemaRAML]
-// 	}
-// 	tests := []struct {
-// 		name   string
-// 		fields fields
-// 		args   args
-// 		want   func(tt *testing.T, options *JSONSchemaConverterOptions[*JSONSchemaRAML])
-// 	}{
-// 		{
-// 			name: "positive case",
-// 			fields: fields{
-// 				omitRefs: true,
-// 			},
-// 			args: args{
-// 				e: &JSONSchemaConverterOptions[*JSONSchemaRAML]{},
-// 			},
-// 			want: func(tt *testing.T, options *JSONSchemaConverterOptions[*JSONSchemaRAML]) {
-// 				if !options.omitRefs {
-// 					tt.Errorf("expected options.OmitRefs to be true, got false")
-// 				}
-// 			},
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			o := optOmitRefs[*JSONSchemaRAML]{
-// 				omitRefs: tt.fields.omitRefs,
-// 			}
-// 			o.apply(tt.args.e)
-// 			if tt.want != nil {
-// 				tt.want(t, tt.args.e)
-// 			}
-// 		})
-// 	}
-// }
+func Test_optOmitRefs_Apply(t *testing.T) {
+	type fields struct {
+		omitRefs bool
+	}
+	type args struct {
+		e *JSONSchemaConverterOptions[*JSONSchemaRAML]
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   func(tt *testing.T, options *JSONSchemaConverterOptions[*JSONSchemaRAML])
+	}{
+		{
+			name: "positive case",
+			fields: fields{
+				omitRefs: true,
+			},
+			args: args{
+				e: &JSONSchemaConverterOptions[*JSONSchemaRAML]{},
+			},
+			want: func(tt *testing.T, options *JSONSchemaConverterOptions[*JSONSchemaRAML]) {
+				if !options.omitRefs {
+					tt.Errorf("expected options.OmitRefs to be true, got false")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := optOmitRefs[*JSONSchemaRAML]{
+				omitRefs: tt.fields.omitRefs,
+			}
+			o.apply(tt.args.e)
+			if tt.want != nil {
+				tt.want(t, tt.args.e)
+			}
+		})
+	}
+}
 
-// func TestWithOmitRefs(t *testing.T) {
-// 	type args struct {
-// 		omitRefs bool
-// 	}
-// 	tests := []struct {
-// 		name string
-// 		args args
-// 		want func(tt *testing.T, options JSONSchemaConverterOpt[*JSONSchemaRAML])
-// 	}{
-// 		{
-// 			name: "positive case",
-// 			args: args{
-// 				omitRefs: true,
-// 			},
-// 			want: func(tt *testing.T, options JSONSchemaConverterOpt[*JSONSchemaRAML]) {
-// 				opt, ok := options.(optOmitRefs[*JSONSchemaRAML])
-// 				if !ok {
-// 					tt.Errorf("expected options to be of type optOmitRefs, got %T", options)
-// 				}
-// 				if !opt.omitRefs {
-// 					tt.Errorf("expected options.OmitRefs to be true, got false")
-// 				}
-// 			},
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			got := WithOmitRefs[*JSONSchemaRAML](tt.args.omitRefs)
-// 			if tt.want != nil {
-// 				tt.want(t, got)
-// 			}
-// 		})
-// 	}
-// }
+func TestWithOmitRefs(t *testing.T) {
+	type args struct {
+		omitRefs bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want func(tt *testing.T, options JSONSchemaConverterOpt[*JSONSchemaRAML])
+	}{
+		{
+			name: "positive case",
+			args: args{
+				omitRefs: true,
+			},
+			want: func(tt *testing.T, options JSONSchemaConverterOpt[*JSONSchemaRAML]) {
+				opt, ok := options.(optOmitRefs[*JSONSchemaRAML])
+				if !ok {
+					tt.Errorf("expected options to be of type optOmitRefs, got %T", options)
+				}
+				if !opt.omitRefs {
+					tt.Errorf("expected options.OmitRefs to be true, got false")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithOmitRefs[*JSONSchemaRAML](tt.args.omitRefs)
+			if tt.want != nil {
+				tt.want(t, got)
+			}
+		})
+	}
+}
 
 func TestNewJSONSchemaConverter(t *testing.T) {
 	type args struct {
@@ -1850,8 +1850,8 @@ func TestJSONSchemaConverter_VisitRecursiveShape(t *testing.T) {
 				if schema.Title != "" {
 					tt.Errorf("expected schema.Title to be empty, got %s", schema.Title)
 				}
-				if schema.Ref != "#/definitions/" {
-					tt.Errorf("expected schema.Ref to be #/definitions/, got %s", schema.Ref)
+				if schema.Ref != "#/definitions/string" {
+					tt.Errorf("expected schema.Ref to be #/definitions/string, got %s", schema.Ref)
 				}
 				if schema.Properties != nil {
 					tt.Errorf("expected schema.Properties to be nil, got non-nil")
@@ -2406,3 +2406,185 @@ func TestJSONSchemaConverter_recast(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONSchemaConverter_ConvertDialects(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Base:
+    properties:
+      id: string
+  Node:
+    type: Base
+    additionalProperties: false
+    example:
+      id: a
+    properties:
+      next?: Node
+      note: nil | string
+      blob?: file
+      nothing: nil
+      value?: nil | string | integer
+      owner?: nil | Base
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	node, ok := r.EntryPoint().(*Library).Types.Get("Node")
+	require.True(t, ok)
+
+	convert := func(t *testing.T, dialect JSONSchemaDialect) (map[string]any, *JSONSchemaConverter[*JSONSchemaRAML]) {
+		c, errConv := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper), WithDialect[*JSONSchemaRAML](dialect))
+		require.NoError(t, errConv)
+		schema, errConv := c.Convert(node.Shape)
+		require.NoError(t, errConv)
+		b, errConv := json.Marshal(schema)
+		require.NoError(t, errConv)
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(b, &m))
+		return m, c
+	}
+
+	t.Run("draft-07 is the default", func(t *testing.T) {
+		c, errConv := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper))
+		require.NoError(t, errConv)
+		schema, errConv := c.Convert(node.Shape)
+		require.NoError(t, errConv)
+		require.Equal(t, JSONSchemaVersion, schema.Version)
+		require.Equal(t, "#/definitions/Node", schema.Ref)
+		def := schema.Definitions["Node"]
+		require.Equal(t, false, *def.AdditionalProperties)
+		next, _ := def.Properties.Get("next")
+		require.Equal(t, "#/definitions/Node", next.Ref)
+		note, _ := def.Properties.Get("note")
+		require.Equal(t, TypeNull, note.AnyOf[0].Type)
+	})
+
+	t.Run("2020-12", func(t *testing.T) {
+		m, _ := convert(t, JSONSchemaDialect202012)
+		require.Equal(t, JSONSchemaVersion202012, m["$schema"])
+		require.Equal(t, "#/$defs/Node", m["$ref"])
+		require.NotContains(t, m, "definitions")
+		def := m["$defs"].(map[string]any)["Node"].(map[string]any)
+		require.Equal(t, false, def["unevaluatedProperties"])
+		require.NotContains(t, def, "additionalProperties")
+		props := def["properties"].(map[string]any)
+		require.Equal(t, "#/$defs/Node", props["next"].(map[string]any)["$ref"])
+	})
+
+	t.Run("OpenAPI 3.0", func(t *testing.T) {
+		m, c := convert(t, JSONSchemaDialectOpenAPI30)
+		require.NotContains(t, m, "$schema")
+		require.NotContains(t, m, "definitions")
+		require.Equal(t, "object", m["type"])
+		require.Equal(t, map[string]any{"id": "a"}, m["example"])
+		require.NotContains(t, m, "examples")
+		props := m["properties"].(map[string]any)
+		require.Equal(t, "#/components/schemas/Node", props["next"].(map[string]any)["$ref"])
+		require.Equal(t, map[string]any{"type": "string", "nullable": true}, props["note"])
+		require.Equal(t, map[string]any{"type": "string", "format": "byte"}, props["blob"])
+		// NOTE: OpenAPI 3.0 has no null type, nullable has no effect without a type.
+		nullOnly := map[string]any{"type": "object", "nullable": true, "enum": []any{nil}}
+		require.Equal(t, nullOnly, props["nothing"])
+		// Null is accepted by a separate member, since nullable has no effect without a type.
+		require.Equal(t, map[string]any{"anyOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "integer"},
+			nullOnly,
+		}}, props["value"])
+		require.Equal(t, map[string]any{"anyOf": []any{
+			map[string]any{"$ref": "#/components/schemas/Base"},
+			nullOnly,
+		}}, props["owner"])
+		require.Contains(t, c.Definitions(), "Node")
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		_, errConv := NewJSONSchemaConverter(WithDialect[*JSONSchemaRAML]("draft-04"))
+		require.Error(t, errConv)
+	})
+}
+
+func TestJSONSchemaConverter_ConvertDefinitionNames(t *testing.T) {
+	errorLib := `#%RAML 1.0 Library
+types:
+  Error:
+    properties:
+      code: integer
+`
+	library := `#%RAML 1.0 Library
+uses:
+  a: a/errors.raml
+  b: b/errors.raml
+types:
+  Error:
+    properties:
+      message: string
+  Response:
+    properties:
+      local: Error
+      first: a.Error
+      second: b.Error
+      items: a.Error[]
+      inline:
+        type: a.Error
+        properties:
+          extra: string
+`
+	lib := parseTestLibrary(t, map[string]string{
+		"a/errors.raml": errorLib,
+		"b/errors.raml": errorLib,
+		"library.raml":  library,
+	}, "library.raml", OptWithUnwrap())
+	response, ok := lib.Types.Get("Response")
+	require.True(t, ok)
+
+	ref := func(schema *JSONSchemaRAML, property string) string {
+		prop, found := schema.Properties.Get(property)
+		require.True(t, found)
+		return prop.Ref
+	}
+
+	t.Run("qualified names", func(t *testing.T) {
+		c, errConv := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper))
+		require.NoError(t, errConv)
+		schema, errConv := c.Convert(response.Shape)
+		require.NoError(t, errConv)
+		require.Len(t, schema.Definitions, 4)
+		def := schema.Definitions["Response"]
+		require.Equal(t, "#/definitions/Error", ref(def, "local"))
+		require.Equal(t, "#/definitions/a.Error", ref(def, "first"))
+		require.Equal(t, "#/definitions/b.Error", ref(def, "second"))
+		items, _ := def.Properties.Get("items")
+		require.Equal(t, "#/definitions/a.Error", items.Items.Ref)
+		// Types that add facets to the named type are converted in place.
+		require.Empty(t, ref(def, "inline"))
+		_, found := schema.Definitions["a.Error"].Properties.Get("code")
+		require.True(t, found)
+		_, found = schema.Definitions["Error"].Properties.Get("message")
+		require.True(t, found)
+	})
+
+	t.Run("namer collisions are resolved", func(t *testing.T) {
+		namer := func(shape *BaseShape, _ *BaseShape) string { return shape.Name }
+		c, errConv := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper),
+			WithDefinitionNamer[*JSONSchemaRAML](namer))
+		require.NoError(t, errConv)
+		schema, errConv := c.Convert(response.Shape)
+		require.NoError(t, errConv)
+		def := schema.Definitions["Response"]
+		require.Equal(t, "#/definitions/Error", ref(def, "local"))
+		require.Equal(t, "#/definitions/Error_2", ref(def, "first"))
+		require.Equal(t, "#/definitions/Error_3", ref(def, "second"))
+	})
+
+	t.Run("omit refs", func(t *testing.T) {
+		c, errConv := NewJSONSchemaConverter(WithWrapper(JSONSchemaWrapper), WithOmitRefs[*JSONSchemaRAML](true))
+		require.NoError(t, errConv)
+		schema, errConv := c.Convert(response.Shape)
+		require.NoError(t, errConv)
+		require.Len(t, schema.Definitions, 1)
+		def := schema.Definitions["Response"]
+		first, _ := def.Properties.Get("first")
+		require.Empty(t, first.Ref)
+		require.Equal(t, TypeObject, first.Type)
+	})
+}
@@ -0,0 +1,460 @@
+package raml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+	"gopkg.in/yaml.v3"
+)
+
+// LintSeverity is the severity of a lint diagnostic.
+type LintSeverity string
+
+const (
+	LintSeverityOff     LintSeverity = "off"
+	LintSeverityInfo    LintSeverity = "info"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityError   LintSeverity = "error"
+)
+
+// DefaultLintIgnoreAnnotation is the annotation that suppresses lint rules for the annotated type or property.
+// The value of the annotation is a rule name or a list of rule names. An empty value suppresses all rules.
+const DefaultLintIgnoreAnnotation = "lint.ignore"
+
+func (s LintSeverity) valid() bool {
+	switch s {
+	case LintSeverityOff, LintSeverityInfo, LintSeverityWarning, LintSeverityError:
+		return true
+	default:
+		return false
+	}
+}
+
+// LintDiagnostic is a single finding reported by a lint rule.
+type LintDiagnostic struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	// Path is the path to the shape, e.g. "Order.items[].name".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+
+	Location string `json:"location"`
+	stacktrace.Position
+}
+
+func (d LintDiagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s: %s (%s)", d.Location, d.Line, d.Column, d.Severity, d.Path, d.Message, d.Rule)
+}
+
+// LintRule is the interface that represents a lint rule.
+type LintRule interface {
+	// Name returns the name of the rule that is used in the configuration and in the ignore annotation.
+	Name() string
+	// Description returns a short human readable description of the rule.
+	Description() string
+	// DefaultSeverity returns the severity used when the configuration does not override it.
+	DefaultSeverity() LintSeverity
+	// Check inspects the model and reports diagnostics through the context.
+	Check(ctx *LintContext)
+}
+
+// LintNode is a shape of the linted library visited by the linter.
+type LintNode struct {
+	// Path is the path to the shape, e.g. "Order.items[].name".
+	Path  string
+	Shape *BaseShape
+	// TypeName is set for the types declared by the library.
+	TypeName string
+	// PropertyName is set for the shapes of object properties.
+	PropertyName string
+
+	ignored []*BaseShape
+}
+
+// IsReference returns true if the node refers to a named type without adding any facets.
+// Such nodes are described by the named type itself.
+func (n LintNode) IsReference() bool {
+	return n.TypeName == "" && referencedNamedType(n.Shape) != nil
+}
+
+// LintContext provides the model to the rules and collects their diagnostics.
+type LintContext struct {
+	Library *Library
+	Nodes   []LintNode
+
+	linter      *Linter
+	rule        LintRule
+	severity    LintSeverity
+	diagnostics []LintDiagnostic
+	seen        map[string]struct{}
+}
+
+// RAML returns the RAML instance the library belongs to.
+func (c *LintContext) RAML() *RAML {
+	return c.Library.raml
+}
+
+// ReportNode reports a diagnostic for the node unless the node or one of its parents suppresses the rule.
+func (c *LintContext) ReportNode(node LintNode, format string, args ...any) {
+	for _, base := range node.ignored {
+		if c.linter.ignores(base, c.rule.Name()) {
+			return
+		}
+	}
+	c.report(node.Path, node.Shape.Location, node.Shape.Position, format, args...)
+}
+
+// Report reports a diagnostic for the shape unless the shape suppresses the rule.
+func (c *LintContext) Report(base *BaseShape, path string, format string, args ...any) {
+	if c.linter.ignores(base, c.rule.Name()) {
+		return
+	}
+	c.report(path, base.Location, base.Position, format, args...)
+}
+
+func (c *LintContext) report(path string, location string, pos stacktrace.Position, format string, args ...any) {
+	d := LintDiagnostic{
+		Rule:     c.rule.Name(),
+		Severity: c.severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Location: location,
+		Position: pos,
+	}
+	// NOTE: Inherited properties share the shapes with the parent type and must be reported once.
+	key := fmt.Sprintf("%s:%s:%d:%d:%s", d.Rule, d.Location, d.Line, d.Column, d.Message)
+	if _, ok := c.seen[key]; ok {
+		return
+	}
+	c.seen[key] = struct{}{}
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// LintConfig is the configuration of the linter.
+type LintConfig struct {
+	// Rules overrides the severity of the rules by name. Severity "off" disables the rule.
+	Rules map[string]LintSeverity `yaml:"rules"`
+	// IgnoreAnnotation is the name of the annotation that suppresses rules, "lint.ignore" by default.
+	IgnoreAnnotation string `yaml:"ignoreAnnotation"`
+}
+
+// ParseLintConfig parses the YAML configuration of the linter.
+func ParseLintConfig(data []byte) (*LintConfig, error) {
+	config := &LintConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unmarshal lint config: %w", err)
+	}
+	for name, severity := range config.Rules {
+		if !severity.valid() {
+			return nil, fmt.Errorf("rule %s: invalid severity %q", name, severity)
+		}
+	}
+	return config, nil
+}
+
+type lintOptions struct {
+	config *LintConfig
+	rules  []LintRule
+}
+
+type LintOpt interface {
+	Apply(*lintOptions)
+}
+
+type lintOptConfig struct {
+	config *LintConfig
+}
+
+func (o lintOptConfig) Apply(opt *lintOptions) {
+	opt.config = o.config
+}
+
+// OptLintConfig sets the configuration of the linter.
+func OptLintConfig(config *LintConfig) LintOpt {
+	return lintOptConfig{config: config}
+}
+
+type lintOptRules []LintRule
+
+func (o lintOptRules) Apply(opt *lintOptions) {
+	opt.rules = append(opt.rules, o...)
+}
+
+// OptLintRules adds custom rules to the built-in ones.
+func OptLintRules(rules ...LintRule) LintOpt {
+	return lintOptRules(rules)
+}
+
+// Linter checks libraries against a set of rules.
+type Linter struct {
+	rules            []LintRule
+	severities       map[string]LintSeverity
+	ignoreAnnotation string
+}
+
+// NewLinter creates a new linter with the built-in rules.
+func NewLinter(opts ...LintOpt) *Linter {
+	lOpts := &lintOptions{}
+	for _, opt := range opts {
+		opt.Apply(lOpts)
+	}
+	l := &Linter{
+		rules:            append(DefaultLintRules(), lOpts.rules...),
+		severities:       make(map[string]LintSeverity),
+		ignoreAnnotation: DefaultLintIgnoreAnnotation,
+	}
+	if lOpts.config != nil {
+		for name, severity := range lOpts.config.Rules {
+			l.severities[name] = severity
+		}
+		if lOpts.config.IgnoreAnnotation != "" {
+			l.ignoreAnnotation = lOpts.config.IgnoreAnnotation
+		}
+	}
+	return l
+}
+
+// Rules returns the rules of the linter.
+func (l *Linter) Rules() []LintRule {
+	return l.rules
+}
+
+// Lint checks the unwrapped library and returns the diagnostics sorted by location and position.
+func (l *Linter) Lint(lib *Library) []LintDiagnostic {
+	ctx := &LintContext{
+		Library: lib,
+		Nodes:   lintNodes(lib),
+		linter:  l,
+		seen:    make(map[string]struct{}),
+	}
+	for _, rule := range l.rules {
+		severity, ok := l.severities[rule.Name()]
+		if !ok {
+			severity = rule.DefaultSeverity()
+		}
+		if severity == LintSeverityOff {
+			continue
+		}
+		ctx.rule = rule
+		ctx.severity = severity
+		rule.Check(ctx)
+	}
+	sort.SliceStable(ctx.diagnostics, func(i, j int) bool {
+		a, b := ctx.diagnostics[i], ctx.diagnostics[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return ctx.diagnostics
+}
+
+// ignores returns true if the shape is annotated to suppress the rule.
+func (l *Linter) ignores(base *BaseShape, rule string) bool {
+	if base == nil || base.CustomDomainProperties == nil {
+		return false
+	}
+	ext, ok := base.CustomDomainProperties.Get(l.ignoreAnnotation)
+	if !ok {
+		return false
+	}
+	if ext.Extension == nil {
+		return true
+	}
+	switch v := ext.Extension.Value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == "" || v == rule
+	case []any:
+		if len(v) == 0 {
+			return true
+		}
+		for _, item := range v {
+			if item == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lintNodes collects the shapes declared in the library. References to named types and recursive shapes are
+// not followed since the named types are visited on their own.
+func lintNodes(lib *Library) []LintNode {
+	var nodes []LintNode
+	var walk func(node LintNode)
+	walk = func(node LintNode) {
+		base := node.Shape
+		if base.Location != lib.Location {
+			return
+		}
+		node.ignored = append(node.ignored[:len(node.ignored):len(node.ignored)], base)
+		nodes = append(nodes, node)
+		if node.IsReference() {
+			return
+		}
+		child := func(path string, s *BaseShape) LintNode {
+			return LintNode{Path: path, Shape: s, ignored: node.ignored}
+		}
+		switch s := base.Shape.(type) {
+		case *ObjectShape:
+			if s.Properties != nil {
+				for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+					prop := child(node.Path+"."+pair.Key, pair.Value.Base)
+					prop.PropertyName = pair.Key
+					walk(prop)
+				}
+			}
+			if s.PatternProperties != nil {
+				for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+					walk(child(node.Path+"."+pair.Key, pair.Value.Base))
+				}
+			}
+		case *ArrayShape:
+			if s.Items != nil {
+				walk(child(node.Path+"[]", s.Items))
+			}
+		case *UnionShape:
+			for _, member := range s.AnyOf {
+				key := member.TypeLabel
+				if key == "" {
+					key = member.Type
+				}
+				walk(child(node.Path+"("+key+")", member))
+			}
+		}
+	}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		walk(LintNode{Path: pair.Key, Shape: pair.Value, TypeName: pair.Key})
+	}
+	return nodes
+}
+
+// DefaultLintRules returns the built-in rules.
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		lintTypeNamePascalCase{},
+		lintPropertyNameCamelCase{},
+		lintDescriptionRequired{},
+		lintNoAny{},
+		lintAdditionalPropertiesExplicit{},
+		lintUnusedType{},
+	}
+}
+
+var (
+	pascalCaseRe = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelCaseRe  = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+)
+
+type lintTypeNamePascalCase struct{}
+
+func (lintTypeNamePascalCase) Name() string                  { return "type-name-pascal-case" }
+func (lintTypeNamePascalCase) Description() string           { return "type names must be PascalCase" }
+func (lintTypeNamePascalCase) DefaultSeverity() LintSeverity { return LintSeverityWarning }
+
+func (lintTypeNamePascalCase) Check(ctx *LintContext) {
+	for _, node := range ctx.Nodes {
+		if node.TypeName != "" && !pascalCaseRe.MatchString(node.TypeName) {
+			ctx.ReportNode(node, "type name %q is not PascalCase", node.TypeName)
+		}
+	}
+}
+
+type lintPropertyNameCamelCase struct{}
+
+func (lintPropertyNameCamelCase) Name() string                  { return "property-name-camel-case" }
+func (lintPropertyNameCamelCase) Description() string           { return "property names must be camelCase" }
+func (lintPropertyNameCamelCase) DefaultSeverity() LintSeverity { return LintSeverityWarning }
+
+func (lintPropertyNameCamelCase) Check(ctx *LintContext) {
+	for _, node := range ctx.Nodes {
+		if node.PropertyName != "" && !camelCaseRe.MatchString(node.PropertyName) {
+			ctx.ReportNode(node, "property name %q is not camelCase", node.PropertyName)
+		}
+	}
+}
+
+type lintDescriptionRequired struct{}
+
+func (lintDescriptionRequired) Name() string { return "description-required" }
+func (lintDescriptionRequired) Description() string {
+	return "types declared by the library must have a description"
+}
+func (lintDescriptionRequired) DefaultSeverity() LintSeverity { return LintSeverityWarning }
+
+func (lintDescriptionRequired) Check(ctx *LintContext) {
+	for _, node := range ctx.Nodes {
+		if node.TypeName != "" && (node.Shape.Description == nil || *node.Shape.Description == "") {
+			ctx.ReportNode(node, "type %q has no description", node.TypeName)
+		}
+	}
+}
+
+type lintNoAny struct{}
+
+func (lintNoAny) Name() string                  { return "no-any" }
+func (lintNoAny) Description() string           { return "shapes must not be of type any" }
+func (lintNoAny) DefaultSeverity() LintSeverity { return LintSeverityWarning }
+
+func (lintNoAny) Check(ctx *LintContext) {
+	for _, node := range ctx.Nodes {
+		if _, ok := node.Shape.Shape.(*AnyShape); ok && !node.IsReference() {
+			ctx.ReportNode(node, "type any accepts any value")
+		}
+	}
+}
+
+type lintAdditionalPropertiesExplicit struct{}
+
+func (lintAdditionalPropertiesExplicit) Name() string { return "additional-properties-explicit" }
+func (lintAdditionalPropertiesExplicit) Description() string {
+	return "objects must set additionalProperties explicitly"
+}
+func (lintAdditionalPropertiesExplicit) DefaultSeverity() LintSeverity { return LintSeverityInfo }
+
+func (lintAdditionalPropertiesExplicit) Check(ctx *LintContext) {
+	for _, node := range ctx.Nodes {
+		if node.IsReference() {
+			continue
+		}
+		if s, ok := node.Shape.Shape.(*ObjectShape); ok && s.AdditionalProperties == nil {
+			ctx.ReportNode(node, "additionalProperties is not set")
+		}
+	}
+}
+
+type lintUnusedType struct{}
+
+func (lintUnusedType) Name() string                  { return "unused-type" }
+func (lintUnusedType) Description() string           { return "types of used libraries must be reachable" }
+func (lintUnusedType) DefaultSeverity() LintSeverity { return LintSeverityWarning }
+
+// Check reports the types that FindUnused reports for the library. Types referenced only by unused types are
+// unused as well.
+func (lintUnusedType) Check(ctx *LintContext) {
+	resolver := newNamedTypeResolver(ctx.Library)
+	for _, unused := range FindUnused(ctx.Library).Types {
+		if unused.AnnotationType {
+			continue
+		}
+		lib, ok := resolver.library(unused.Location)
+		if !ok || lib.Types == nil {
+			continue
+		}
+		base, ok := lib.Types.Get(unused.Name)
+		if !ok {
+			continue
+		}
+		prefix := resolver.prefix(unused.Location)
+		ctx.Report(base, prefix+unused.Name, "type %q of library %q is not used",
+			unused.Name, strings.TrimSuffix(prefix, "."))
+	}
+}
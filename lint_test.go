@@ -0,0 +1,204 @@
+package raml
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseLintLibrary(t *testing.T, library string) *Library {
+	t.Helper()
+	files := map[string]string{
+		"lint.raml": `#%RAML 1.0 Library
+annotationTypes:
+  ignore: nil | string | string[]
+`,
+		"common.raml": `#%RAML 1.0 Library
+types:
+  Used:
+    type: string
+  Unused:
+    type: string
+  Legacy:
+    (lint.ignore): unused-type
+    type: string
+uses:
+  lint: lint.raml
+`,
+		"library.raml": library,
+	}
+	return parseTestLibrary(t, files, "library.raml", OptWithUnwrap())
+}
+
+func lintSummary(diagnostics []LintDiagnostic) []string {
+	res := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		res = append(res, d.Rule+" "+d.Path)
+	}
+	return res
+}
+
+func TestLinter_Lint(t *testing.T) {
+	lib := parseLintLibrary(t, `#%RAML 1.0 Library
+uses:
+  lint: lint.raml
+  common: common.raml
+types:
+  Pet:
+    description: A pet.
+    additionalProperties: false
+    properties:
+      Name: string
+      owner_id: common.Used
+      extra: any
+      address:
+        properties:
+          city: string
+  order:
+    description: An order.
+    additionalProperties: true
+    properties:
+      items:
+        type: array
+        items:
+          additionalProperties: false
+          properties:
+            sku_code: string
+  Cat:
+    type: Pet
+    description: A cat.
+  Legacy:
+    (lint.ignore):
+    properties:
+      Bad_Name: any
+  Partial:
+    (lint.ignore): [description-required, additional-properties-explicit]
+    properties:
+      Bad_Name: string
+      nested:
+        (lint.ignore): no-any
+        additionalProperties: false
+        properties:
+          value: any
+`)
+	diagnostics := NewLinter().Lint(lib)
+	require.Equal(t, []string{
+		"unused-type common.Unused",
+		"property-name-camel-case Pet.Name",
+		"property-name-camel-case Pet.owner_id",
+		"no-any Pet.extra",
+		"additional-properties-explicit Pet.address",
+		"type-name-pascal-case order",
+		"property-name-camel-case order.items[].sku_code",
+		"property-name-camel-case Partial.Bad_Name",
+	}, lintSummary(diagnostics))
+
+	d := diagnostics[1]
+	require.Equal(t, LintSeverityWarning, d.Severity)
+	require.Equal(t, "library.raml", filepath.Base(d.Location))
+	require.Equal(t, 10, d.Line)
+	require.Equal(t, `property name "Name" is not camelCase`, d.Message)
+}
+
+func TestLinter_Config(t *testing.T) {
+	lib := parseLintLibrary(t, `#%RAML 1.0 Library
+uses:
+  lint: lint.raml
+types:
+  pet:
+    (skip): type-name-pascal-case
+    properties:
+      value: any
+annotationTypes:
+  skip: string
+`)
+	config, err := ParseLintConfig([]byte(`
+rules:
+  description-required: error
+  additional-properties-explicit: "off"
+ignoreAnnotation: skip
+`))
+	require.NoError(t, err)
+	diagnostics := NewLinter(OptLintConfig(config)).Lint(lib)
+	require.Equal(t, []string{"description-required pet", "no-any pet.value"}, lintSummary(diagnostics))
+	require.Equal(t, LintSeverityError, diagnostics[0].Severity)
+
+	_, err = ParseLintConfig([]byte("rules:\n  no-any: fatal\n"))
+	require.ErrorContains(t, err, `rule no-any: invalid severity "fatal"`)
+}
+
+func TestLinter_UnusedType(t *testing.T) {
+	files := map[string]string{
+		"base.raml": `#%RAML 1.0 Library
+types:
+  Id: string
+  Helper: string
+  Orphan: string
+`,
+		"common.raml": `#%RAML 1.0 Library
+uses:
+  base: base.raml
+types:
+  Used:
+    properties:
+      id: base.Id
+  Unused:
+    properties:
+      helper: base.Helper
+`,
+		"library.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+types:
+  Pet:
+    properties:
+      owner: common.Used
+`,
+	}
+	lib := parseTestLibrary(t, files, "library.raml", OptWithUnwrap())
+	diagnostics := NewLinter(OptLintConfig(&LintConfig{Rules: map[string]LintSeverity{
+		"description-required":           LintSeverityOff,
+		"additional-properties-explicit": LintSeverityOff,
+	}})).Lint(lib)
+	// NOTE: Types referenced only by unused types are unused as well, as reported by FindUnused.
+	require.Equal(t, []string{
+		"unused-type common.base.Helper",
+		"unused-type common.base.Orphan",
+		"unused-type common.Unused",
+	}, lintSummary(diagnostics))
+	require.Equal(t, `type "Helper" of library "common.base" is not used`, diagnostics[0].Message)
+}
+
+type lintRuleFunc func(ctx *LintContext)
+
+func (lintRuleFunc) Name() string                  { return "custom" }
+func (lintRuleFunc) Description() string           { return "custom rule" }
+func (lintRuleFunc) DefaultSeverity() LintSeverity { return LintSeverityError }
+func (f lintRuleFunc) Check(ctx *LintContext)      { f(ctx) }
+
+func TestLinter_CustomRule(t *testing.T) {
+	lib := parseLintLibrary(t, `#%RAML 1.0 Library
+uses:
+  lint: lint.raml
+types:
+  Pet:
+    description: A pet.
+    additionalProperties: false
+    properties:
+      id: integer
+      name:
+        (lint.ignore): custom
+        type: string
+`)
+	rule := lintRuleFunc(func(ctx *LintContext) {
+		for _, node := range ctx.Nodes {
+			if node.PropertyName != "" {
+				ctx.ReportNode(node, "property found")
+			}
+		}
+	})
+	diagnostics := NewLinter(OptLintRules(rule)).Lint(lib)
+	require.Equal(t, []string{"custom Pet.id"}, lintSummary(diagnostics))
+	require.Equal(t, LintSeverityError, diagnostics[0].Severity)
+}
@@ -0,0 +1,230 @@
+package raml
+
+import (
+	"strconv"
+)
+
+// namedType is a type declared in a library.
+type namedType struct {
+	// location is the location of the library the type is declared in.
+	location string
+	name     string
+	shape    *BaseShape
+}
+
+// shapeKey identifies a shape. IDs are unique per RAML only, and unwrapped copies of a shape keep its ID.
+type shapeKey struct {
+	raml *RAML
+	id   int64
+}
+
+func keyOf(base *BaseShape) shapeKey {
+	return shapeKey{raml: base.raml, id: base.ID}
+}
+
+// namedTypeResolver resolves shapes to the named types they refer to. It is shared by the generators and
+// converters that emit named declarations. Libraries reachable from the entry library through "uses" are indexed,
+// so that declarations are recognized and prefixed by the chain of library aliases.
+type namedTypeResolver struct {
+	libs map[string]*Library
+	// prefixes maps locations of libraries to chains of aliases relative to the entry library, e.g. "common.".
+	prefixes map[string]string
+	declared map[shapeKey]namedType
+}
+
+// newNamedTypeResolver creates a resolver for the libraries reachable from the entry library. The entry library
+// may be nil, then only references are resolved.
+func newNamedTypeResolver(entry *Library) *namedTypeResolver {
+	r := &namedTypeResolver{
+		libs:     make(map[string]*Library),
+		prefixes: make(map[string]string),
+		declared: make(map[shapeKey]namedType),
+	}
+	if entry != nil {
+		r.collect(entry, "")
+	}
+	return r
+}
+
+func (r *namedTypeResolver) collect(lib *Library, prefix string) {
+	if _, ok := r.libs[lib.Location]; ok {
+		return
+	}
+	r.libs[lib.Location] = lib
+	r.prefixes[lib.Location] = prefix
+	if lib.Types != nil {
+		for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+			if key := keyOf(pair.Value); r.declared[key].shape == nil {
+				r.declared[key] = namedType{location: lib.Location, name: pair.Key, shape: pair.Value}
+			}
+		}
+	}
+	if lib.Uses == nil {
+		return
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			r.collect(pair.Value.Link, prefix+pair.Key+".")
+		}
+	}
+}
+
+// library returns the indexed library at the location.
+func (r *namedTypeResolver) library(location string) (*Library, bool) {
+	lib, ok := r.libs[location]
+	return lib, ok
+}
+
+// prefix returns the chain of library aliases of the library at the location.
+func (r *namedTypeResolver) prefix(location string) string {
+	return r.prefixes[location]
+}
+
+// declaration returns the named type if the shape is declared in one of the indexed libraries.
+func (r *namedTypeResolver) declaration(base *BaseShape) (namedType, bool) {
+	if base == nil {
+		return namedType{}, false
+	}
+	nt, ok := r.declared[keyOf(base)]
+	return nt, ok
+}
+
+// referenced returns the named type the shape refers to by its type label. The label is resolved relative to
+// the fragment the shape is defined in.
+func (r *namedTypeResolver) referenced(base *BaseShape) (namedType, bool) {
+	if base == nil || base.TypeLabel == "" || base.raml == nil {
+		return namedType{}, false
+	}
+	ref, err := base.raml.GetReferencedType(base.TypeLabel, base.Location)
+	if err != nil || ref == nil || ref == base {
+		return namedType{}, false
+	}
+	if nt, ok := r.declared[keyOf(ref)]; ok {
+		return nt, true
+	}
+	_, name, found := CutReferenceName(base.TypeLabel)
+	if !found {
+		name = base.TypeLabel
+	}
+	return namedType{location: ref.Location, name: name, shape: ref}, true
+}
+
+// named returns the named type the shape is, aliases or refers to without adding facets.
+func (r *namedTypeResolver) named(base *BaseShape) (namedType, bool) {
+	if base == nil {
+		return namedType{}, false
+	}
+	if nt, ok := r.declaration(base); ok {
+		return nt, true
+	}
+	if base.Alias != nil {
+		return r.named(base.Alias)
+	}
+	if ref := referencedNamedType(base); ref != nil {
+		return r.named(ref)
+	}
+	return namedType{}, false
+}
+
+// reference returns the named type the shape can be replaced with in generated code. Objects refer to the named
+// type only if they do not declare additional properties.
+func (r *namedTypeResolver) reference(base *BaseShape) (namedType, bool) {
+	nt, ok := r.referenced(base)
+	if !ok {
+		return namedType{}, false
+	}
+	if obj, isObject := base.Shape.(*ObjectShape); isObject {
+		refObj, isRefObject := nt.shape.Shape.(*ObjectShape)
+		if !isRefObject || !sameProperties(obj, refObj) {
+			return namedType{}, false
+		}
+	}
+	return nt, true
+}
+
+// declNames allocates unique names of declarations. A named type reserves its name before its declaration is
+// generated: the first declaration made for it takes the reserved name, the nested anonymous declarations get
+// unique names derived from hints.
+type declNames struct {
+	taken map[string]struct{}
+	// hint is the name of the next anonymous declaration.
+	hint string
+	// pending is the reserved name of the named type whose declaration is not made yet.
+	pending string
+}
+
+func newDeclNames() declNames {
+	return declNames{taken: make(map[string]struct{})}
+}
+
+// unique takes and returns a name that is not taken yet. The name is prefixed by the chain of library aliases
+// and then numbered on collisions.
+func (n *declNames) unique(name string, prefix string) string {
+	if n.take(name) {
+		return name
+	}
+	if prefix != "" {
+		name = goIdent(prefix) + name
+		if n.take(name) {
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		if candidate := name + strconv.Itoa(i); n.take(candidate) {
+			return candidate
+		}
+	}
+}
+
+func (n *declNames) take(name string) bool {
+	if _, ok := n.taken[name]; ok {
+		return false
+	}
+	n.taken[name] = struct{}{}
+	return true
+}
+
+// reserve reserves the name of the named type and makes it the name of the next declaration.
+// It returns a function that restores the previous state.
+func (n *declNames) reserve(name string) func() {
+	prevHint, prevPending := n.hint, n.pending
+	n.hint, n.pending = name, name
+	return func() {
+		n.hint, n.pending = prevHint, prevPending
+	}
+}
+
+// next returns the name of the next declaration.
+func (n *declNames) next() string {
+	if n.hint == n.pending {
+		n.pending = ""
+		return n.hint
+	}
+	return n.unique(n.hint, "")
+}
+
+// withHint evaluates f with the name of the next anonymous declaration.
+func withHint[T any](n *declNames, hint string, f func() T) T {
+	prevHint := n.hint
+	n.hint = hint
+	defer func() {
+		n.hint = prevHint
+	}()
+	return f()
+}
+
+// sameProperties reports whether the objects declare the same set of properties.
+func sameProperties(a, b *ObjectShape) bool {
+	if a.Properties == nil || b.Properties == nil {
+		return a.Properties == b.Properties
+	}
+	if a.Properties.Len() != b.Properties.Len() {
+		return false
+	}
+	for pair := a.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := b.Properties.Get(pair.Key); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -42,6 +42,7 @@ func (n *Node) String() string {
 func (r *RAML) makeIncludedNode(node *yaml.Node, location string) (*Node, error) {
 	baseDir := filepath.Dir(location)
 	fragmentPath := filepath.Join(baseDir, node.Value)
+	r.addDependency(location, fragmentPath)
 	rdr, err := ReadRawFile(fragmentPath)
 	if err != nil {
 		return nil, StacktraceNewWrapped("include: read raw file", err, location, WithNodePosition(node),
@@ -111,6 +112,8 @@ func (r *RAML) makeRootNode(node *yaml.Node, location string) (*Node, error) {
 }
 
 func (r *RAML) makeYamlNode(node *yaml.Node, location string) (*Node, error) {
+	// NOTE: Includes of nested nodes are read by yamlNodeToDataNode.
+	r.addIncludes(node, location)
 	data, err := yamlNodeToDataNode(node, location, false)
 	if err != nil {
 		return nil, StacktraceNewWrapped("yaml node to data node", err, location, WithNodePosition(node))
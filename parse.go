@@ -88,7 +88,9 @@ func (r *RAML) decodeDataType(f io.Reader, path string) (*DataType, error) {
 	baseDir := filepath.Dir(dt.Location)
 	for pair := dt.Uses.Oldest(); pair != nil; pair = pair.Next() {
 		include := pair.Value
-		sublib, err := r.parseLibrary(filepath.Join(baseDir, include.Value))
+		includePath := filepath.Join(baseDir, include.Value)
+		r.addDependency(path, includePath)
+		sublib, err := r.parseLibrary(includePath)
 		if err != nil {
 			return nil, StacktraceNewWrapped("parse library", err, dt.Location,
 				stacktrace.WithType(StacktraceTypeParsing))
@@ -196,7 +198,9 @@ func (r *RAML) decodeLibrary(f io.Reader, path string) (*Library, error) {
 	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
 		include := pair.Value
 
-		sublib, err := r.parseLibrary(filepath.Join(baseDir, include.Value))
+		includePath := filepath.Join(baseDir, include.Value)
+		r.addDependency(path, includePath)
+		sublib, err := r.parseLibrary(includePath)
 		if err != nil {
 			se := StacktraceNewWrapped("parse uses library", err, path,
 				stacktrace.WithType(StacktraceTypeParsing), stacktrace.WithPosition(&include.Position))
@@ -312,6 +316,9 @@ func (r *RAML) ParseFromPath(path string, opts ...ParseOpt) error {
 		opt.Apply(pOpts)
 	}
 
+	if abs, errAbs := filepath.Abs(path); errAbs == nil {
+		r.addDependency("", abs)
+	}
 	f, err := openFragmentFile(path)
 	if err != nil {
 		return StacktraceNewWrapped("open fragment file", err, path,
@@ -0,0 +1,753 @@
+package raml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+const (
+	// DefaultProtoFieldAnnotation is the annotation that sets the field number of a property.
+	DefaultProtoFieldAnnotation = "proto.field"
+
+	protoMaxFieldNumber       = 1<<29 - 1
+	protoReservedNumbersStart = 19000
+	protoReservedNumbersEnd   = 19999
+
+	protoImportTimestamp = "google/protobuf/timestamp.proto"
+	protoImportStruct    = "google/protobuf/struct.proto"
+)
+
+// ProtoDiagnostic describes a shape that cannot be represented in protobuf exactly.
+type ProtoDiagnostic struct {
+	// Path is the path to the shape, e.g. "Order.labels".
+	Path    string
+	Message string
+
+	Location string
+	stacktrace.Position
+}
+
+func (d ProtoDiagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.Location, d.Line, d.Column, d.Path, d.Message)
+}
+
+type ProtoConverterOptions struct {
+	packageName     string
+	fieldAnnotation string
+}
+
+type ProtoConverterOpt interface {
+	apply(*ProtoConverterOptions)
+}
+
+type optProtoPackage struct{ name string }
+
+func (o optProtoPackage) apply(c *ProtoConverterOptions) { c.packageName = o.name }
+
+// WithProtoPackage sets the package of the generated .proto file.
+func WithProtoPackage(name string) ProtoConverterOpt {
+	return optProtoPackage{name}
+}
+
+type optProtoFieldAnnotation struct{ name string }
+
+func (o optProtoFieldAnnotation) apply(c *ProtoConverterOptions) { c.fieldAnnotation = o.name }
+
+// WithProtoFieldAnnotation sets the name of the integer annotation that sets field numbers of properties.
+// DefaultProtoFieldAnnotation is used by default.
+func WithProtoFieldAnnotation(name string) ProtoConverterOpt {
+	return optProtoFieldAnnotation{name}
+}
+
+// ProtoConverter converts the unwrapped types of a RAML library to a proto3 schema.
+//
+// Objects become messages, unions become oneofs, string enums become enums, arrays become repeated
+// fields, integer formats map to int32 and int64 (sint32 and sint64 for negative minimums), date and
+// datetime types map to google.protobuf.Timestamp. Field numbers are assigned in the order of properties
+// unless set by the field annotation, so annotating properties keeps numbers stable across changes.
+// Shapes that cannot be represented exactly, such as pattern properties or any, are reported
+// as diagnostics.
+type ProtoConverter struct {
+	ShapeVisitor[string]
+
+	opts ProtoConverterOptions
+
+	resolver *namedTypeResolver
+	names    declNames
+	decls    map[string]string
+	order    []string
+	typeKeys map[string]string
+	imports  map[string]struct{}
+	// inProgress maps IDs of objects being declared to their message names.
+	inProgress map[int64]string
+
+	// path is the path to the current shape for diagnostics.
+	path  string
+	diags []ProtoDiagnostic
+	err   error
+}
+
+func NewProtoConverter(opt ...ProtoConverterOpt) *ProtoConverter {
+	c := &ProtoConverter{opts: ProtoConverterOptions{fieldAnnotation: DefaultProtoFieldAnnotation}}
+	for _, o := range opt {
+		o.apply(&c.opts)
+	}
+	return c
+}
+
+// Convert converts all types of the library and the types they reference to a .proto file.
+// The library must be unwrapped.
+func (c *ProtoConverter) Convert(lib *Library) ([]byte, []ProtoDiagnostic, error) {
+	c.resolver = newNamedTypeResolver(lib)
+	c.names = newDeclNames()
+	c.decls = make(map[string]string)
+	c.order = nil
+	c.typeKeys = make(map[string]string)
+	c.imports = make(map[string]struct{})
+	c.inProgress = make(map[int64]string)
+	c.diags = nil
+	c.err = nil
+
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.IsUnwrapped() {
+			return nil, nil, fmt.Errorf("type %s must be unwrapped", pair.Key)
+		}
+		c.declareNamed(namedType{location: lib.Location, name: pair.Key, shape: pair.Value})
+		if c.err != nil {
+			return nil, nil, c.err
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by raml gen proto. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n")
+	if c.opts.packageName != "" {
+		fmt.Fprintf(&b, "\npackage %s;\n", c.opts.packageName)
+	}
+	if len(c.imports) > 0 {
+		imports := make([]string, 0, len(c.imports))
+		for imp := range c.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		b.WriteString("\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&b, "import %q;\n", imp)
+		}
+	}
+	for _, name := range c.order {
+		b.WriteString("\n")
+		b.WriteString(c.decls[name])
+	}
+	return []byte(b.String()), c.diags, nil
+}
+
+func (c *ProtoConverter) diagnose(base *BaseShape, format string, args ...any) {
+	c.diags = append(c.diags, ProtoDiagnostic{
+		Path:     c.path,
+		Message:  fmt.Sprintf(format, args...),
+		Location: base.Location,
+		Position: base.Position,
+	})
+}
+
+func (c *ProtoConverter) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// declareNamed declares the named type and returns the field type that refers to it.
+func (c *ProtoConverter) declareNamed(nt namedType) string {
+	key := nt.location + "#" + nt.name
+	if typ, ok := c.typeKeys[key]; ok {
+		return typ
+	}
+	prefix := c.resolver.prefix(nt.location)
+	protoName := c.names.unique(goIdent(nt.name), prefix)
+	c.typeKeys[key] = protoName
+
+	restore := c.names.reserve(protoName)
+	prevPath := c.path
+	c.path = prefix + nt.name
+	defer func() {
+		restore()
+		c.path = prevPath
+	}()
+
+	typ := c.Visit(nt.shape.Shape)
+	// Scalars and arrays are not declared, references to them are replaced with their field types.
+	c.typeKeys[key] = typ
+	return typ
+}
+
+func (c *ProtoConverter) addDecl(name, code string) {
+	if _, ok := c.decls[name]; !ok {
+		c.order = append(c.order, name)
+	}
+	c.decls[name] = code
+}
+
+// typeExpr returns the field type of the shape.
+func (c *ProtoConverter) typeExpr(base *BaseShape) string {
+	if name, ok := c.inProgress[base.ID]; ok {
+		return name
+	}
+	if typ, ok := c.referencedType(base); ok {
+		return typ
+	}
+	return c.Visit(base.Shape)
+}
+
+// withContext evaluates f with the name of the next anonymous declaration and the path to the shape.
+func (c *ProtoConverter) withContext(hint, segment string, f func() string) string {
+	prevPath := c.path
+	c.path += segment
+	defer func() {
+		c.path = prevPath
+	}()
+	return withHint(&c.names, hint, f)
+}
+
+// referencedType returns the field type of the named type the shape refers to.
+func (c *ProtoConverter) referencedType(base *BaseShape) (string, bool) {
+	nt, ok := c.resolver.reference(base)
+	if !ok {
+		return "", false
+	}
+	return c.declareNamed(nt), true
+}
+
+func (c *ProtoConverter) Visit(s Shape) string {
+	switch shapeType := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(shapeType)
+	case *ArrayShape:
+		return c.VisitArrayShape(shapeType)
+	case *StringShape:
+		return c.VisitStringShape(shapeType)
+	case *NumberShape:
+		return c.VisitNumberShape(shapeType)
+	case *IntegerShape:
+		return c.VisitIntegerShape(shapeType)
+	case *BooleanShape:
+		return c.VisitBooleanShape(shapeType)
+	case *FileShape:
+		return c.VisitFileShape(shapeType)
+	case *UnionShape:
+		return c.VisitUnionShape(shapeType)
+	case *NilShape:
+		return c.VisitNilShape(shapeType)
+	case *AnyShape:
+		return c.VisitAnyShape(shapeType)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(shapeType)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(shapeType)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(shapeType)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(shapeType)
+	case *JSONShape:
+		return c.VisitJSONShape(shapeType)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(shapeType)
+	default:
+		return c.VisitAnyShape(&AnyShape{BaseShape: s.Base()})
+	}
+}
+
+// protoFieldNumbers allocates field numbers of a message.
+type protoFieldNumbers struct {
+	used map[int]struct{}
+	next int
+}
+
+func (n *protoFieldNumbers) reserve(number int) error {
+	if number < 1 || number > protoMaxFieldNumber {
+		return fmt.Errorf("field number %d is out of range", number)
+	}
+	if number >= protoReservedNumbersStart && number <= protoReservedNumbersEnd {
+		return fmt.Errorf("field number %d is reserved by protobuf", number)
+	}
+	if _, ok := n.used[number]; ok {
+		return fmt.Errorf("field number %d is used more than once", number)
+	}
+	n.used[number] = struct{}{}
+	return nil
+}
+
+func (n *protoFieldNumbers) alloc() int {
+	for {
+		n.next++
+		if n.next >= protoReservedNumbersStart && n.next <= protoReservedNumbersEnd {
+			n.next = protoReservedNumbersEnd
+			continue
+		}
+		if _, ok := n.used[n.next]; !ok {
+			n.used[n.next] = struct{}{}
+			return n.next
+		}
+	}
+}
+
+// fieldNumber returns the field number set by the field annotation.
+func (c *ProtoConverter) fieldNumber(base *BaseShape) (int, bool, error) {
+	if base.CustomDomainProperties == nil {
+		return 0, false, nil
+	}
+	de, ok := base.CustomDomainProperties.Get(c.opts.fieldAnnotation)
+	if !ok || de.Extension == nil {
+		return 0, false, nil
+	}
+	i, err := toBigInt(de.Extension.Value)
+	if err != nil || !i.IsInt64() {
+		return 0, false, fmt.Errorf("annotation (%s) must be an integer", c.opts.fieldAnnotation)
+	}
+	return int(i.Int64()), true, nil
+}
+
+// protoField is a field of a message, members of a oneof are fields of the oneof.
+type protoField struct {
+	name string
+	// jsonName is the property name, it is empty for oneof members.
+	jsonName string
+	typ      string
+	number   int
+	optional bool
+	members  []*protoField
+	base     *BaseShape
+}
+
+func (c *ProtoConverter) VisitObjectShape(s *ObjectShape) string {
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		return c.mapType(s)
+	}
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		c.diagnose(s.Base(), "pattern properties are not supported and are omitted")
+	}
+
+	name := c.names.next()
+	c.inProgress[s.ID] = name
+	c.addDecl(name, "")
+	defer delete(c.inProgress, s.ID)
+
+	numbers := &protoFieldNumbers{used: make(map[int]struct{})}
+	fields := make([]*protoField, 0, s.Properties.Len())
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		field := c.field(name, prop)
+		number, ok, err := c.fieldNumber(prop.Base)
+		if err != nil {
+			c.fail(fmt.Errorf("%s.%s: %w", c.path, prop.Name, err))
+			return name
+		}
+		if ok {
+			// NOTE: Members of a oneof get consecutive numbers starting from the annotated one.
+			numbered := field.members
+			if len(numbered) == 0 {
+				numbered = []*protoField{field}
+			}
+			for i, f := range numbered {
+				if err = numbers.reserve(number + i); err != nil {
+					c.fail(fmt.Errorf("%s.%s: %w", c.path, prop.Name, err))
+					return name
+				}
+				f.number = number + i
+			}
+		}
+		fields = append(fields, field)
+	}
+	c.renameOneofMembers(fields)
+
+	var b strings.Builder
+	b.WriteString(protoComment(s.Base().Description, ""))
+	fmt.Fprintf(&b, "message %s {\n", name)
+	for _, f := range fields {
+		if len(f.members) == 0 {
+			if f.number == 0 {
+				f.number = numbers.alloc()
+			}
+			b.WriteString(protoFieldLine(f, "  "))
+			continue
+		}
+		b.WriteString(protoComment(f.base.Description, "  "))
+		fmt.Fprintf(&b, "  oneof %s {\n", f.name)
+		for _, m := range f.members {
+			if m.number == 0 {
+				m.number = numbers.alloc()
+			}
+			b.WriteString(protoFieldLine(m, "    "))
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	c.addDecl(name, b.String())
+	return name
+}
+
+// renameOneofMembers prefixes the names of oneof members that collide with other fields or oneofs of the message
+// by the name of the oneof, since they share the namespace of the message.
+func (c *ProtoConverter) renameOneofMembers(fields []*protoField) {
+	taken := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		taken[f.name] = struct{}{}
+	}
+	for _, f := range fields {
+		for _, m := range f.members {
+			name := m.name
+			if _, ok := taken[name]; ok {
+				name = f.name + "_" + m.name
+				for i := 2; ; i++ {
+					if _, found := taken[name]; !found {
+						break
+					}
+					name = f.name + "_" + m.name + "_" + strconv.Itoa(i)
+				}
+				c.diagnose(m.base, "oneof member %s collides with another field and is renamed to %s", m.name, name)
+			}
+			taken[name] = struct{}{}
+			m.name = name
+		}
+	}
+}
+
+// field converts the property to a field. Properties of union types become oneofs.
+func (c *ProtoConverter) field(message string, prop Property) *protoField {
+	field := &protoField{
+		name:     protoFieldName(prop.Name),
+		jsonName: prop.Name,
+		base:     prop.Base,
+		optional: !prop.Required,
+	}
+	base := prop.Base
+	if rs, ok := base.Shape.(*RecursiveShape); ok && base.TypeLabel == "" {
+		base = rs.Head
+	}
+	hint := message + goIdent(prop.Name)
+	if union, ok := base.Shape.(*UnionShape); ok {
+		if _, isNamed := c.referencedType(base); !isNamed {
+			members, nullable := nonNilMembers(union)
+			if nullable {
+				field.optional = true
+			}
+			if len(members) > 1 {
+				c.withContext(hint, "."+prop.Name, func() string {
+					field.members = c.oneofMembers(members)
+					return ""
+				})
+				return field
+			}
+			if len(members) == 1 {
+				base = members[0]
+			}
+		}
+	}
+	field.typ = c.withContext(hint, "."+prop.Name, func() string {
+		return c.typeExpr(base)
+	})
+	if isProtoCollection(field.typ) {
+		field.optional = false
+	}
+	return field
+}
+
+// oneofMembers converts union members to oneof fields. Repeated and map members are wrapped in messages.
+func (c *ProtoConverter) oneofMembers(members []*BaseShape) []*protoField {
+	fields := make([]*protoField, 0, len(members))
+	names := make(map[string]struct{}, len(members))
+	hint := c.names.hint
+	for i, m := range members {
+		memberName := m.TypeLabel
+		if memberName == "" {
+			memberName = m.Type
+		}
+		if memberName == "" {
+			memberName = "value" + strconv.Itoa(i+1)
+		}
+		typ := c.withContext(hint+goIdent(memberName), "|"+strconv.Itoa(i), func() string {
+			return c.wrapCollection(c.typeExpr(m), m)
+		})
+		fieldName := protoFieldName(memberName)
+		if _, ok := names[fieldName]; ok {
+			fieldName += "_" + strconv.Itoa(i+1)
+		}
+		names[fieldName] = struct{}{}
+		fields = append(fields, &protoField{name: fieldName, typ: typ, base: m})
+	}
+	return fields
+}
+
+// wrapCollection wraps repeated and map types in a message since they cannot be nested.
+func (c *ProtoConverter) wrapCollection(typ string, base *BaseShape) string {
+	if !isProtoCollection(typ) {
+		return typ
+	}
+	name := c.names.unique(c.names.hint, "")
+	c.addDecl(name, protoComment(base.Description, "")+
+		fmt.Sprintf("message %s {\n  %s values = 1;\n}\n", name, typ))
+	return name
+}
+
+func (c *ProtoConverter) mapType(s *ObjectShape) string {
+	if s.PatternProperties == nil || s.PatternProperties.Len() == 0 {
+		c.imports[protoImportStruct] = struct{}{}
+		return "google.protobuf.Struct"
+	}
+	if s.PatternProperties.Len() > 1 {
+		c.diagnose(s.Base(), "multiple pattern properties are not supported, represented as google.protobuf.Struct")
+		c.imports[protoImportStruct] = struct{}{}
+		return "google.protobuf.Struct"
+	}
+	pp := s.PatternProperties.Oldest().Value
+	c.diagnose(s.Base(), "pattern property %s is represented as map without the key pattern", pp.Pattern.String())
+	value := c.withContext(c.names.hint+"Value", "./"+pp.Pattern.String()+"/", func() string {
+		return c.wrapCollection(c.typeExpr(pp.Base), pp.Base)
+	})
+	return "map<string, " + value + ">"
+}
+
+func (c *ProtoConverter) VisitArrayShape(s *ArrayShape) string {
+	if s.Items == nil {
+		c.imports[protoImportStruct] = struct{}{}
+		return "repeated google.protobuf.Value"
+	}
+	item := c.withContext(c.names.hint+"Item", "[]", func() string {
+		return c.wrapCollection(c.typeExpr(s.Items), s.Items)
+	})
+	return "repeated " + item
+}
+
+func (c *ProtoConverter) VisitUnionShape(s *UnionShape) string {
+	members, _ := nonNilMembers(s)
+	switch len(members) {
+	case 0:
+		return c.VisitNilShape(&NilShape{BaseShape: s.Base()})
+	case 1:
+		// Nil is represented by the absence of the field.
+		return c.withContext(c.names.hint, "|0", func() string {
+			return c.typeExpr(members[0])
+		})
+	}
+	name := c.names.next()
+	c.addDecl(name, "")
+	fields := c.oneofMembers(members)
+	var b strings.Builder
+	b.WriteString(protoComment(s.Base().Description, ""))
+	fmt.Fprintf(&b, "message %s {\n  oneof value {\n", name)
+	for i, f := range fields {
+		f.number = i + 1
+		b.WriteString(protoFieldLine(f, "    "))
+	}
+	b.WriteString("  }\n}\n")
+	c.addDecl(name, b.String())
+	return name
+}
+
+func nonNilMembers(s *UnionShape) ([]*BaseShape, bool) {
+	members := make([]*BaseShape, 0, len(s.AnyOf))
+	nullable := false
+	for _, item := range s.AnyOf {
+		if _, ok := item.Shape.(*NilShape); ok {
+			nullable = true
+			continue
+		}
+		members = append(members, item)
+	}
+	return members, nullable
+}
+
+func (c *ProtoConverter) VisitStringShape(s *StringShape) string {
+	if len(s.Enum) == 0 {
+		return "string"
+	}
+	name := c.names.next()
+	prefix := protoEnumValueName(name) + "_"
+	var b strings.Builder
+	b.WriteString(protoComment(s.Base().Description, ""))
+	fmt.Fprintf(&b, "enum %s {\n  %sUNSPECIFIED = 0;\n", name, prefix)
+	values := map[string]struct{}{prefix + "UNSPECIFIED": {}}
+	for i, e := range s.Enum {
+		value := prefix + protoEnumValueName(fmt.Sprint(e.Value))
+		if _, ok := values[value]; ok {
+			value = prefix + "VALUE_" + strconv.Itoa(i+1)
+		}
+		values[value] = struct{}{}
+		fmt.Fprintf(&b, "  %s = %d;\n", value, i+1)
+	}
+	b.WriteString("}\n")
+	c.addDecl(name, b.String())
+	return name
+}
+
+func (c *ProtoConverter) VisitIntegerShape(s *IntegerShape) string {
+	if len(s.Enum) > 0 {
+		c.diagnose(s.Base(), "integer enums are not supported, represented as integer")
+	}
+	signed := s.Minimum != nil && s.Minimum.Sign() < 0
+	bits := "64"
+	if s.Format != nil && SetOfIntegerFormats[*s.Format] <= SetOfIntegerFormats["int32"] {
+		bits = "32"
+	}
+	if signed {
+		return "sint" + bits
+	}
+	return "int" + bits
+}
+
+func (c *ProtoConverter) VisitNumberShape(s *NumberShape) string {
+	if len(s.Enum) > 0 {
+		c.diagnose(s.Base(), "number enums are not supported, represented as number")
+	}
+	if s.Format != nil && *s.Format == "float" {
+		return "float"
+	}
+	return "double"
+}
+
+func (c *ProtoConverter) VisitBooleanShape(_ *BooleanShape) string {
+	return "bool"
+}
+
+func (c *ProtoConverter) VisitFileShape(_ *FileShape) string {
+	return "bytes"
+}
+
+func (c *ProtoConverter) VisitNilShape(_ *NilShape) string {
+	c.imports[protoImportStruct] = struct{}{}
+	return "google.protobuf.NullValue"
+}
+
+func (c *ProtoConverter) VisitAnyShape(s *AnyShape) string {
+	c.diagnose(s.Base(), "any is represented as google.protobuf.Value")
+	c.imports[protoImportStruct] = struct{}{}
+	return "google.protobuf.Value"
+}
+
+func (c *ProtoConverter) VisitDateTimeShape(s *DateTimeShape) string {
+	if s.Format != nil && *s.Format == DateTimeFormatRFC2616 {
+		c.diagnose(s.Base(), "rfc2616 format is represented as google.protobuf.Timestamp")
+	}
+	c.imports[protoImportTimestamp] = struct{}{}
+	return "google.protobuf.Timestamp"
+}
+
+func (c *ProtoConverter) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) string {
+	c.diagnose(s.Base(), "datetime-only is represented as google.protobuf.Timestamp in UTC")
+	c.imports[protoImportTimestamp] = struct{}{}
+	return "google.protobuf.Timestamp"
+}
+
+func (c *ProtoConverter) VisitDateOnlyShape(_ *DateOnlyShape) string {
+	c.imports[protoImportTimestamp] = struct{}{}
+	return "google.protobuf.Timestamp"
+}
+
+func (c *ProtoConverter) VisitTimeOnlyShape(_ *TimeOnlyShape) string {
+	return "string"
+}
+
+func (c *ProtoConverter) VisitJSONShape(s *JSONShape) string {
+	c.diagnose(s.Base(), "JSON schema is represented as google.protobuf.Value")
+	c.imports[protoImportStruct] = struct{}{}
+	return "google.protobuf.Value"
+}
+
+func (c *ProtoConverter) VisitRecursiveShape(s *RecursiveShape) string {
+	return c.typeExpr(s.Head)
+}
+
+func isProtoCollection(typ string) bool {
+	return strings.HasPrefix(typ, "repeated ") || strings.HasPrefix(typ, "map<")
+}
+
+func protoFieldLine(f *protoField, indent string) string {
+	label := ""
+	if f.optional {
+		label = "optional "
+	}
+	var options []string
+	if f.jsonName != "" && !protoJSONNameMatches(f.name, f.jsonName) {
+		options = append(options, "json_name = "+strconv.Quote(f.jsonName))
+	}
+	opts := ""
+	if len(options) > 0 {
+		opts = " [" + strings.Join(options, ", ") + "]"
+	}
+	return protoComment(f.base.Description, indent) +
+		fmt.Sprintf("%s%s%s %s = %d%s;\n", indent, label, f.typ, f.name, f.number, opts)
+}
+
+// protoJSONNameMatches reports whether the default JSON name of the proto field is the property name.
+func protoJSONNameMatches(fieldName, propName string) bool {
+	var b strings.Builder
+	upper := false
+	for _, r := range fieldName {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String() == propName
+}
+
+// protoFieldName converts the RAML name to a snake case field name.
+func protoFieldName(name string) string {
+	snake := lowerCamelToSnake(name)
+	if snake == "" || unicode.IsDigit([]rune(snake)[0]) {
+		snake = "f_" + snake
+	}
+	return snake
+}
+
+func lowerCamelToSnake(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func protoEnumValueName(name string) string {
+	return strings.ToUpper(protoFieldName(name))
+}
+
+func protoComment(description *string, indent string) string {
+	if description == nil || *description == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(*description), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			b.WriteString(indent + "//\n")
+		} else {
+			b.WriteString(indent + "// " + line + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,220 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoConverter_Convert(t *testing.T) {
+	files := map[string]string{
+		"common.raml": `#%RAML 1.0 Library
+types:
+  Status:
+    type: string
+    enum: [active, disabled]
+`,
+		"proto.raml": `#%RAML 1.0 Library
+annotationTypes:
+  field: integer
+`,
+		"library.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+  proto: proto.raml
+types:
+  Cat:
+    properties:
+      lives:
+        type: integer
+        format: int8
+  Dog:
+    properties:
+      good: boolean
+  Animal: Cat | Dog
+  Node:
+    properties:
+      value: string
+      next?: Node
+  Order:
+    description: An order.
+    properties:
+      orderID:
+        type: integer
+        (proto.field): 5
+      status: common.Status
+      created: datetime
+      day: date-only
+      delta:
+        type: integer
+        format: int32
+        minimum: -10
+      pets: Animal[]
+      pet:
+        type: Cat | Dog
+        (proto.field): 10
+      owner?: nil | Cat
+      matrix:
+        type: array
+        items: number[]
+      labels:
+        properties:
+          /^[a-z]+$/: string
+      payload?: any
+`,
+	}
+	lib := parseTestLibrary(t, files, "library.raml", OptWithUnwrap())
+
+	out, diags, err := NewProtoConverter(WithProtoPackage("pets.v1")).Convert(lib)
+	require.NoError(t, err)
+	want := `// Code generated by raml gen proto. DO NOT EDIT.
+
+syntax = "proto3";
+
+package pets.v1;
+
+import "google/protobuf/struct.proto";
+import "google/protobuf/timestamp.proto";
+
+message Cat {
+  int32 lives = 1;
+}
+
+message Dog {
+  bool good = 1;
+}
+
+message Animal {
+  oneof value {
+    Cat cat = 1;
+    Dog dog = 2;
+  }
+}
+
+message Node {
+  string value = 1;
+  optional Node next = 2;
+}
+
+// An order.
+message Order {
+  int64 order_id = 5 [json_name = "orderID"];
+  Status status = 1;
+  google.protobuf.Timestamp created = 2;
+  google.protobuf.Timestamp day = 3;
+  sint32 delta = 4;
+  repeated Animal pets = 6;
+  oneof pet {
+    Cat cat = 10;
+    Dog dog = 11;
+  }
+  optional Cat owner = 7;
+  repeated OrderMatrixItem matrix = 8;
+  map<string, string> labels = 9;
+  optional google.protobuf.Value payload = 12;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+  STATUS_DISABLED = 2;
+}
+
+message OrderMatrixItem {
+  repeated double values = 1;
+}
+`
+	require.Equal(t, want, string(out))
+	require.Len(t, diags, 2)
+	require.Equal(t, "Order.labels", diags[0].Path)
+	require.Contains(t, diags[0].Message, "pattern property")
+	require.Equal(t, "Order.payload", diags[1].Path)
+	require.Contains(t, diags[1].String(), "any is represented as google.protobuf.Value")
+
+	again, _, err := NewProtoConverter(WithProtoPackage("pets.v1")).Convert(lib)
+	require.NoError(t, err)
+	require.Equal(t, string(out), string(again))
+}
+
+func TestProtoConverter_ConvertFieldNumberErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		wantErr    string
+	}{
+		{
+			name:       "duplicate",
+			properties: "      a:\n        type: string\n        (proto.field): 1\n      b:\n        type: string\n        (proto.field): 1\n",
+			wantErr:    "field number 1 is used more than once",
+		},
+		{
+			name: "oneof members",
+			properties: "      a:\n        type: boolean | string\n        (proto.field): 1\n" +
+				"      b:\n        type: string\n        (proto.field): 2\n",
+			wantErr: "field number 2 is used more than once",
+		},
+		{
+			name:       "reserved",
+			properties: "      a:\n        type: string\n        (proto.field): 19000\n",
+			wantErr:    "field number 19000 is reserved by protobuf",
+		},
+		{
+			name:       "out of range",
+			properties: "      a:\n        type: string\n        (proto.field): 0\n",
+			wantErr:    "field number 0 is out of range",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := parseTestLibrary(t, map[string]string{
+				"proto.raml":   "#%RAML 1.0 Library\nannotationTypes:\n  field: integer\n",
+				"library.raml": "#%RAML 1.0 Library\nuses:\n  proto: proto.raml\ntypes:\n  A:\n    properties:\n" + tt.properties,
+			}, "library.raml", OptWithUnwrap())
+			_, _, err := NewProtoConverter().Convert(lib)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProtoConverter_ConvertOneofNames(t *testing.T) {
+	lib := parseTestLibrary(t, map[string]string{
+		"library.raml": `#%RAML 1.0 Library
+types:
+  Cat:
+    properties:
+      lives: integer
+  Dog:
+    properties:
+      good: boolean
+  Owner:
+    properties:
+      cat: string
+      pet: Cat | Dog
+`,
+	}, "library.raml", OptWithUnwrap())
+
+	out, diags, err := NewProtoConverter().Convert(lib)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `message Owner {
+  string cat = 1;
+  oneof pet {
+    Cat pet_cat = 2;
+    Dog dog = 3;
+  }
+}`)
+	require.Len(t, diags, 1)
+	require.Contains(t, diags[0].Message, "oneof member cat collides with another field and is renamed to pet_cat")
+}
+
+func TestLowerCamelToSnake(t *testing.T) {
+	tests := map[string]string{
+		"orderID":    "order_id",
+		"createdAt":  "created_at",
+		"HTTPServer": "http_server",
+		"x-id":       "x_id",
+		"name":       "name",
+	}
+	for in, want := range tests {
+		require.Equal(t, want, lowerCamelToSnake(in), in)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"container/list"
 	"context"
 	"fmt"
+	"path/filepath"
 	"reflect"
+
+	"gopkg.in/yaml.v3"
 )
 
 type HookKey string
@@ -17,6 +20,8 @@ type RAML struct {
 	fragmentAnnotationTypes map[string]map[string]*BaseShape
 	// entryPoint is a Library, NamedExample or DataType fragment that is used as an entry point for the resolution.
 	entryPoint Fragment
+	// dependencies maps locations of files to files they use or include. The entry point is a dependency of "".
+	dependencies map[string]map[string]struct{}
 	// basePath   string
 
 	// May be reused for both validation and resolution.
@@ -144,6 +149,45 @@ func New(ctx context.Context) *RAML {
 	}
 }
 
+// GetFiles returns sorted paths of all files read during parsing: the entry point, libraries of "uses",
+// fragments and included files. Files that failed to be read are included as well, so the list describes
+// the dependency closure even if parsing failed.
+func (r *RAML) GetFiles() []string {
+	files := make(map[string]struct{})
+	for location, deps := range r.dependencies {
+		if location != "" {
+			files[location] = struct{}{}
+		}
+		for dep := range deps {
+			files[dep] = struct{}{}
+		}
+	}
+	return sortedKeys(files)
+}
+
+// addDependency records that the file at the location uses or includes the file at the path.
+func (r *RAML) addDependency(location string, path string) {
+	if r.dependencies == nil {
+		r.dependencies = make(map[string]map[string]struct{})
+	}
+	deps, ok := r.dependencies[location]
+	if !ok {
+		deps = make(map[string]struct{})
+		r.dependencies[location] = deps
+	}
+	deps[path] = struct{}{}
+}
+
+// addIncludes records the files included by the YAML node and its children.
+func (r *RAML) addIncludes(node *yaml.Node, location string) {
+	if node.Tag == TagInclude {
+		r.addDependency(location, filepath.Join(filepath.Dir(location), node.Value))
+	}
+	for _, child := range node.Content {
+		r.addIncludes(child, location)
+	}
+}
+
 // Shapes returns all shapes.
 func (r *RAML) GetShapes() []*BaseShape {
 	return r.shapes
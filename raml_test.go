@@ -4,9 +4,11 @@ import (
 	"container/list"
 	"context"
 	"errors"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
@@ -1477,3 +1479,46 @@ func TestRAML_ClearHooks(t *testing.T) {
 		})
 	}
 }
+
+func TestRAML_GetFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+  missing: missing.raml
+types:
+  Order:
+    type: !include order.raml
+`,
+		"common.raml": `#%RAML 1.0 Library
+types:
+  Id:
+    type: string
+    examples: !include id-examples.raml
+`,
+		"order.raml": `#%RAML 1.0 DataType
+type: object
+properties:
+  id: string
+example: !include order.json
+`,
+		"id-examples.raml": `#%RAML 1.0 NamedExample
+first: a1
+`,
+		"order.json": `{"id": "a1"}`,
+	}
+	writeTestFiles(t, dir, files)
+
+	r, err := ParseFromPath(filepath.Join(dir, "main.raml"))
+	require.Error(t, err)
+	want := []string{
+		filepath.Join(dir, "common.raml"),
+		filepath.Join(dir, "id-examples.raml"),
+		filepath.Join(dir, "main.raml"),
+		filepath.Join(dir, "missing.raml"),
+		filepath.Join(dir, "order.json"),
+		filepath.Join(dir, "order.raml"),
+	}
+	require.Equal(t, want, r.GetFiles())
+}
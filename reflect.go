@@ -0,0 +1,283 @@
+package raml
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	typeTime          = reflect.TypeOf(time.Time{})
+	typeBigInt        = reflect.TypeOf(big.Int{})
+	typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// structField describes a struct field that is visible to validation.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structInfo contains reflection metadata of a struct type.
+type structInfo struct {
+	fields []structField
+}
+
+// structInfoCache caches structInfo per reflect.Type.
+var structInfoCache sync.Map
+
+// getStructInfo returns cached reflection metadata of the struct type.
+func getStructInfo(t reflect.Type) *structInfo {
+	if si, ok := structInfoCache.Load(t); ok {
+		return si.(*structInfo)
+	}
+	si := &structInfo{fields: collectStructFields(t, nil, make(map[reflect.Type]struct{}))}
+	actual, _ := structInfoCache.LoadOrStore(t, si)
+	return actual.(*structInfo)
+}
+
+// collectStructFields collects fields of the struct type according to encoding/json rules.
+// Fields of embedded structs without explicit name are promoted to the parent struct
+// unless the parent struct defines a field with the same name.
+func collectStructFields(t reflect.Type, index []int, visited map[reflect.Type]struct{}) []structField {
+	if _, ok := visited[t]; ok {
+		return nil
+	}
+	visited[t] = struct{}{}
+	defer delete(visited, t)
+
+	var fields []structField
+	var promoted []structField
+	names := make(map[string]struct{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != typeTime && ft != typeBigInt {
+				promoted = append(promoted, collectStructFields(ft, fieldIndex, visited)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = struct{}{}
+		fields = append(fields, structField{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	for _, pf := range promoted {
+		if _, ok := names[pf.name]; ok {
+			continue
+		}
+		names[pf.name] = struct{}{}
+		fields = append(fields, pf)
+	}
+	return fields
+}
+
+// isEmptyValue reports whether the value is considered empty by the "omitempty" option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// fieldByIndex returns the nested field by index. The second value is false if the field is
+// unreachable because of a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// structToMap converts the struct value to a map of field values keyed by JSON names.
+// Field values are not converted, they are converted lazily on validation.
+func structToMap(v reflect.Value) map[string]interface{} {
+	si := getStructInfo(v.Type())
+	m := make(map[string]interface{}, len(si.fields))
+	for _, f := range si.fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		m[f.name] = fv.Interface()
+	}
+	return m
+}
+
+// toValidationValue converts an arbitrary Go value to a value that can be validated by shapes.
+//
+// Generic values (map[string]interface{}, []interface{} and primitives) are returned as is.
+// Other values are converted in a shallow manner, following the encoding/json conventions:
+//   - Structs are converted to map[string]interface{} using "json" tags. Fields with "omitempty" option
+//     and empty values are omitted, which makes pointer fields with "omitempty" optional.
+//   - Typed slices and arrays are converted to []interface{}, []byte is converted to base64 string.
+//   - Maps with string keys are converted to map[string]interface{}.
+//   - Pointers and interfaces are dereferenced, nil pointers are converted to nil.
+//   - Named scalar types are converted to their underlying primitive types.
+//   - time.Time, json.Number and *big.Int are kept as is and are handled by the scalar shapes.
+func toValidationValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil, string, bool, int, uint, float64, map[string]interface{}, []interface{}, time.Time, json.Number:
+		return v, nil
+	case *big.Int:
+		if val == nil {
+			return nil, nil
+		}
+		return v, nil
+	}
+	return reflectValidationValue(reflect.ValueOf(v))
+}
+
+//nolint:gocyclo,cyclop // Contains a flat switch over reflect kinds.
+func reflectValidationValue(rv reflect.Value) (interface{}, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type() == reflect.PointerTo(typeBigInt) {
+			return rv.Interface(), nil
+		}
+		// NOTE: Marshalers with pointer receivers must be checked before the dereference, the same way as
+		// encoding/json uses them for addressable values.
+		if rv.Kind() == reflect.Pointer && implementsMarshaler(rv.Type()) && !implementsMarshaler(rv.Type().Elem()) {
+			return marshalerValidationValue(rv)
+		}
+		rv = rv.Elem()
+	}
+
+	t := rv.Type()
+	switch {
+	case t == typeTime:
+		return rv.Interface(), nil
+	case t == typeBigInt:
+		i := rv.Interface().(big.Int)
+		return &i, nil
+	case implementsMarshaler(t):
+		return marshalerValidationValue(rv)
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		if t == reflect.TypeOf(json.Number("")) {
+			return json.Number(rv.String()), nil
+		}
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uint(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+		return sliceToInterfaces(rv), nil
+	case reflect.Array:
+		return sliceToInterfaces(rv), nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, nil
+	case reflect.Struct:
+		return structToMap(rv), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func sliceToInterfaces(rv reflect.Value) []interface{} {
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	return t.Implements(typeJSONMarshaler) || t.Implements(typeTextMarshaler)
+}
+
+// marshalerValidationValue converts the value using its json.Marshaler or encoding.TextMarshaler implementation.
+func marshalerValidationValue(rv reflect.Value) (interface{}, error) {
+	if rv.Type().Implements(typeJSONMarshaler) {
+		return marshaledValidationValue(rv)
+	}
+	text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("marshal text: %w", err)
+	}
+	return string(text), nil
+}
+
+// marshaledValidationValue converts the value that implements json.Marshaler by marshaling it to JSON.
+func marshaledValidationValue(rv reflect.Value) (interface{}, error) {
+	data, err := rv.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal json: %w", err)
+	}
+	var out interface{}
+	if err = json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal json: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,318 @@
+package raml
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type reflectTestStatus string
+
+type reflectTestEmbedded struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Owner     string    `json:"owner"`
+}
+
+type reflectTestItem struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type reflectTestOrder struct {
+	reflectTestEmbedded
+	ID       json.Number       `json:"id"`
+	Status   reflectTestStatus `json:"status"`
+	Owner    string            `json:"owner_override,omitempty"`
+	Comment  *string           `json:"comment,omitempty"`
+	Items    []reflectTestItem `json:"items"`
+	Tags     map[string]uint8  `json:"tags,omitempty"`
+	Amount   *big.Int          `json:"amount,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+type reflectTestText struct{}
+
+func (reflectTestText) MarshalText() ([]byte, error) { return []byte("text"), nil }
+
+type reflectTestPointerJSON struct{ value int }
+
+func (m *reflectTestPointerJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"value": m.value})
+}
+
+type reflectTestPointerText struct{}
+
+func (*reflectTestPointerText) MarshalText() ([]byte, error) { return []byte("pointer text"), nil }
+
+func Test_toValidationValue(t *testing.T) {
+	comment := "comment"
+	type args struct {
+		v interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "generic value is returned as is",
+			args: args{v: map[string]interface{}{"a": 1}},
+			want: map[string]interface{}{"a": 1},
+		},
+		{
+			name: "nil pointer",
+			args: args{v: (*string)(nil)},
+			want: nil,
+		},
+		{
+			name: "pointer to string",
+			args: args{v: &comment},
+			want: "comment",
+		},
+		{
+			name: "named string type",
+			args: args{v: reflectTestStatus("active")},
+			want: "active",
+		},
+		{
+			name: "sized integers",
+			args: args{v: []int8{1, 2}},
+			want: []interface{}{int8(1), int8(2)},
+		},
+		{
+			name: "unsigned integer",
+			args: args{v: uint16(7)},
+			want: uint(7),
+		},
+		{
+			name: "float32",
+			args: args{v: float32(0.5)},
+			want: float64(0.5),
+		},
+		{
+			name: "bytes are encoded as base64",
+			args: args{v: []byte("abc")},
+			want: "YWJj",
+		},
+		{
+			name: "nil slice",
+			args: args{v: []string(nil)},
+			want: nil,
+		},
+		{
+			name: "array",
+			args: args{v: [2]string{"a", "b"}},
+			want: []interface{}{"a", "b"},
+		},
+		{
+			name: "typed map",
+			args: args{v: map[reflectTestStatus]int{"a": 1}},
+			want: map[string]interface{}{"a": 1},
+		},
+		{
+			name:    "map with non-string keys",
+			args:    args{v: map[int]string{1: "a"}},
+			wantErr: true,
+		},
+		{
+			name: "big.Int value",
+			args: args{v: *big.NewInt(5)},
+			want: big.NewInt(5),
+		},
+		{
+			name: "text marshaler",
+			args: args{v: reflectTestText{}},
+			want: "text",
+		},
+		{
+			name: "json marshaler with pointer receiver",
+			args: args{v: &reflectTestPointerJSON{value: 1}},
+			want: map[string]interface{}{"value": float64(1)},
+		},
+		{
+			name: "text marshaler with pointer receiver",
+			args: args{v: &reflectTestPointerText{}},
+			want: "pointer text",
+		},
+		{
+			name: "nil pointer with pointer receiver marshaler",
+			args: args{v: (*reflectTestPointerText)(nil)},
+			want: nil,
+		},
+		{
+			name: "pointer to time is dereferenced",
+			args: args{v: &time.Time{}},
+			want: time.Time{},
+		},
+		{
+			name:    "unsupported type",
+			args:    args{v: make(chan int)},
+			wantErr: true,
+		},
+		{
+			name: "struct",
+			args: args{v: reflectTestOrder{
+				reflectTestEmbedded: reflectTestEmbedded{Owner: "embedded"},
+				ID:                  "1",
+				Status:              "active",
+				Comment:             &comment,
+				Internal:            "internal",
+				hidden:              "hidden",
+			}},
+			want: map[string]interface{}{
+				"createdAt": time.Time{},
+				"owner":     "embedded",
+				"id":        json.Number("1"),
+				"status":    reflectTestStatus("active"),
+				"comment":   &comment,
+				"items":     []reflectTestItem(nil),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toValidationValue(tt.args.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("toValidationValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toValidationValue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getStructInfo(t *testing.T) {
+	typ := reflect.TypeOf(reflectTestOrder{})
+	first := getStructInfo(typ)
+	second := getStructInfo(typ)
+	require.Same(t, first, second)
+
+	names := make([]string, len(first.fields))
+	for i, f := range first.fields {
+		names[i] = f.name
+	}
+	require.Equal(t, []string{
+		"id", "status", "owner_override", "comment", "items", "tags", "amount", "createdAt", "owner",
+	}, names)
+}
+
+func TestBaseShape_Validate_GoValues(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Item:
+    properties:
+      name:
+        type: string
+        minLength: 1
+      count:
+        type: integer
+        minimum: 1
+  Order:
+    properties:
+      id: integer
+      status:
+        enum: [active, closed]
+      owner: string
+      createdAt: datetime
+      comment?: string
+      items:
+        type: Item[]
+        minItems: 1
+      tags?:
+        type: object
+        properties:
+          //: integer
+      amount?:
+        type: integer
+        maximum: 1000
+`
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	r, err := ParseFromString(content, "library.raml", workDir, OptWithUnwrap())
+	require.NoError(t, err)
+	lib, ok := r.EntryPoint().(*Library)
+	require.True(t, ok)
+	order, ok := lib.Types.Get("Order")
+	require.True(t, ok)
+
+	valid := reflectTestOrder{
+		reflectTestEmbedded: reflectTestEmbedded{CreatedAt: time.Now(), Owner: "me"},
+		ID:                  "10",
+		Status:              "active",
+		Items:               []reflectTestItem{{Name: "item", Count: 1}},
+		Tags:                map[string]uint8{"a": 1},
+		Amount:              big.NewInt(5),
+	}
+
+	tests := []struct {
+		name    string
+		v       func() interface{}
+		wantErr bool
+	}{
+		{
+			name: "valid struct",
+			v:    func() interface{} { return valid },
+		},
+		{
+			name: "valid pointer to struct",
+			v:    func() interface{} { return &valid },
+		},
+		{
+			name: "invalid enum of named string type",
+			v: func() interface{} {
+				o := valid
+				o.Status = "unknown"
+				return o
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid nested item",
+			v: func() interface{} {
+				o := valid
+				o.Items = []reflectTestItem{{Name: "", Count: 1}}
+				return o
+			},
+			wantErr: true,
+		},
+		{
+			name: "nil required array",
+			v: func() interface{} {
+				o := valid
+				o.Items = nil
+				return o
+			},
+			wantErr: true,
+		},
+		{
+			name: "big integer exceeds maximum",
+			v: func() interface{} {
+				o := valid
+				o.Amount = big.NewInt(1001)
+				return o
+			},
+			wantErr: true,
+		},
+		{
+			name:    "nil pointer to struct",
+			v:       func() interface{} { return (*reflectTestOrder)(nil) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := order.Validate(tt.v()); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package raml
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"regexp"
 	"time"
@@ -108,8 +110,9 @@ func (s *IntegerShape) alias(source Shape) (Shape, error) {
 	return s, nil
 }
 
-func (s *IntegerShape) validate(v interface{}, _ string) error {
-	var val big.Int
+// toBigInt converts the integer value to big.Int.
+func toBigInt(v interface{}) (*big.Int, error) {
+	val := new(big.Int)
 	switch v := v.(type) {
 	case int:
 		val.SetInt64(int64(v))
@@ -117,9 +120,37 @@ func (s *IntegerShape) validate(v interface{}, _ string) error {
 		val.SetUint64(uint64(v))
 	// json unmarshals numbers as float64
 	case float64:
-		val.SetInt64(int64(v))
+		// NOTE: Floats are converted exactly, so that large values are not truncated to int64 and values
+		// with a fraction are rejected the same way as json.Number values are.
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid integer value %v", v)
+		}
+		f := new(big.Float).SetFloat64(v)
+		if !f.IsInt() {
+			return nil, fmt.Errorf("invalid integer value %v", v)
+		}
+		f.Int(val)
+	case json.Number:
+		if _, ok := val.SetString(v.String(), 10); !ok {
+			// Fall back to float notation, e.g. "1e3" or "10.0".
+			f, isFloat := new(big.Float).SetString(v.String())
+			if !isFloat || !f.IsInt() {
+				return nil, fmt.Errorf("invalid integer value %s", v.String())
+			}
+			f.Int(val)
+		}
+	case *big.Int:
+		val.Set(v)
 	default:
-		return fmt.Errorf("invalid type, got %T, expected int, uint or float64", v)
+		return nil, fmt.Errorf("invalid type, got %T, expected int, uint, float64, json.Number or *big.Int", v)
+	}
+	return val, nil
+}
+
+func (s *IntegerShape) validate(v interface{}, _ string) error {
+	val, err := toBigInt(v)
+	if err != nil {
+		return err
 	}
 
 	if s.Minimum != nil && val.Cmp(s.Minimum) < 0 {
@@ -333,18 +364,34 @@ func (s *NumberShape) alias(source Shape) (Shape, error) {
 	return s, nil
 }
 
-func (s *NumberShape) validate(v interface{}, _ string) error {
-	var val float64
+// toFloat64 converts the number value to float64.
+func toFloat64(v interface{}) (float64, error) {
 	switch v := v.(type) {
 	// go-yaml unmarshals integers as int
 	case int:
-		val = float64(v)
+		return float64(v), nil
 	case uint:
-		val = float64(v)
+		return float64(v), nil
 	case float64:
-		val = v
+		return v, nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid number value %s", v.String())
+		}
+		return f, nil
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f, nil
 	default:
-		return fmt.Errorf("invalid type, got %T, expected int, uint, float64", v)
+		return 0, fmt.Errorf("invalid type, got %T, expected int, uint, float64, json.Number or *big.Int", v)
+	}
+}
+
+func (s *NumberShape) validate(v interface{}, _ string) error {
+	val, err := toFloat64(v)
+	if err != nil {
+		return err
 	}
 
 	if s.Minimum != nil && val < *s.Minimum {
@@ -954,9 +1001,13 @@ func (s *DateTimeShape) alias(source Shape) (Shape, error) {
 }
 
 func (s *DateTimeShape) validate(v interface{}, _ string) error {
+	// time.Time is valid regardless of the format since the format applies only to serialized values.
+	if _, ok := v.(time.Time); ok {
+		return nil
+	}
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
 	if s.Format == nil {
@@ -1058,9 +1109,12 @@ func (s *DateTimeOnlyShape) alias(source Shape) (Shape, error) {
 }
 
 func (s *DateTimeOnlyShape) validate(v interface{}, _ string) error {
+	if _, ok := v.(time.Time); ok {
+		return nil
+	}
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
 	if _, err := time.Parse(DateTime, i); err != nil {
@@ -1131,9 +1185,12 @@ func (s *DateOnlyShape) alias(source Shape) (Shape, error) {
 }
 
 func (s *DateOnlyShape) validate(v interface{}, _ string) error {
+	if _, ok := v.(time.Time); ok {
+		return nil
+	}
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
 	if _, err := time.Parse(time.DateOnly, i); err != nil {
@@ -1204,9 +1261,12 @@ func (s *TimeOnlyShape) alias(source Shape) (Shape, error) {
 }
 
 func (s *TimeOnlyShape) validate(v interface{}, _ string) error {
+	if _, ok := v.(time.Time); ok {
+		return nil
+	}
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
 	if _, err := time.Parse(time.TimeOnly, i); err != nil {
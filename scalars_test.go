@@ -3,10 +3,13 @@ package raml
 import (
 	"container/list"
 	"context"
+	"encoding/json"
+	"math"
 	"math/big"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 	"gopkg.in/yaml.v3"
@@ -221,6 +224,95 @@ func TestIntegerShape_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid json.Number",
+			fields: fields{
+				BaseShape: &BaseShape{},
+				IntegerFacets: IntegerFacets{
+					Maximum: big.NewInt(100),
+				},
+			},
+			args: args{
+				v: json.Number("42"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid json.Number in float notation",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: json.Number("1e3"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "json.Number with fraction",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: json.Number("1.5"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "float64 with fraction",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: 1.5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "float64 NaN",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: math.NaN(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "float64 infinity",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: math.Inf(1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "float64 greater than int64 range",
+			fields: fields{
+				BaseShape: &BaseShape{},
+				IntegerFacets: IntegerFacets{
+					Minimum: big.NewInt(0),
+				},
+			},
+			args: args{
+				v: 1e20,
+			},
+			wantErr: false,
+		},
+		{
+			name: "*big.Int greater than maximum",
+			fields: fields{
+				BaseShape: &BaseShape{},
+				IntegerFacets: IntegerFacets{
+					Maximum: big.NewInt(100),
+				},
+			},
+			args: args{
+				v: big.NewInt(101),
+			},
+			wantErr: true,
+		},
 		{
 			name: "nil value",
 			fields: fields{
@@ -3963,6 +4055,16 @@ func TestDateOnlyShape_Validate(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
+		{
+			name: "time.Time value",
+			fields: fields{
+				BaseShape: &BaseShape{},
+			},
+			args: args{
+				v: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
 		{
 			name: "positive case",
 			fields: fields{
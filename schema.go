@@ -12,6 +12,9 @@ import (
 // Version is the JSON Schema version.
 const JSONSchemaVersion = "http://json-schema.org/draft-07/schema"
 
+// JSONSchemaVersion202012 is the JSON Schema 2020-12 version.
+const JSONSchemaVersion202012 = "https://json-schema.org/draft/2020-12/schema"
+
 type Copyable[T any] interface {
 	// DeepCopy creates a deep copy of the JSON Schema object.
 	DeepCopy() T
@@ -38,6 +41,8 @@ type JSONSchemaGeneric[T Copyable[T]] struct {
 	// http://json-schema.org/latest/json-schema-validation.html#rfc.section.5.26
 	// RFC draft-wright-json-schema-validation-00, section 5.26
 	Definitions map[string]T `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	// Defs hold schema definitions in JSON Schema 2020-12.
+	Defs map[string]T `json:"$defs,omitempty" yaml:"$defs,omitempty"`
 
 	AllOf []T `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	AnyOf []T `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
@@ -54,6 +59,9 @@ type JSONSchemaGeneric[T Copyable[T]] struct {
 	PatternProperties    *orderedmap.OrderedMap[string, T] `json:"patternProperties,omitempty" yaml:"patternProperties,omitempty"`
 	AdditionalProperties *bool                             `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 	PropertyNames        T                                 `json:"propertyNames,omitempty" yaml:"propertyNames,omitempty"`
+	// UnevaluatedProperties is used instead of additionalProperties in JSON Schema 2020-12
+	// to close objects composed from several schemas.
+	UnevaluatedProperties *bool `json:"unevaluatedProperties,omitempty" yaml:"unevaluatedProperties,omitempty"`
 
 	Type             string      `json:"type,omitempty" yaml:"type,omitempty"`
 	Enum             []any       `json:"enum,omitempty" yaml:"enum,omitempty"`
@@ -81,6 +89,10 @@ type JSONSchemaGeneric[T Copyable[T]] struct {
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	Default     any    `json:"default,omitempty" yaml:"default,omitempty"`
 	Examples    []any  `json:"examples,omitempty" yaml:"examples,omitempty"`
+
+	// Nullable and Example are keywords of the OpenAPI 3.0 schema object.
+	Nullable *bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Example  any   `json:"example,omitempty" yaml:"example,omitempty"`
 }
 
 func (js *JSONSchemaGeneric[T]) ShallowCopy() *JSONSchemaGeneric[T] {
@@ -162,6 +174,13 @@ func (js *JSONSchemaGeneric[T]) DeepCopy() *JSONSchemaGeneric[T] {
 		}
 	}
 
+	if len(js.Defs) > 0 {
+		newJs.Defs = make(map[string]T, len(js.Defs))
+		for k, v := range js.Defs {
+			newJs.Defs[k] = v.DeepCopy()
+		}
+	}
+
 	return newJs
 }
 
@@ -266,6 +285,12 @@ func (js *JSONSchemaGeneric[T]) Map() map[string]any {
 	if len(js.Examples) > 0 {
 		out["examples"] = js.Examples
 	}
+	if b := boolPtr(js.Nullable); b != nil {
+		out["nullable"] = b
+	}
+	if js.Example != nil {
+		out["example"] = js.Example
+	}
 
 	if len(js.Definitions) > 0 {
 		defs := make(map[string]any, len(js.Definitions))
@@ -274,6 +299,13 @@ func (js *JSONSchemaGeneric[T]) Map() map[string]any {
 		}
 		out["definitions"] = defs
 	}
+	if len(js.Defs) > 0 {
+		defs := make(map[string]any, len(js.Defs))
+		for k, v := range js.Defs {
+			defs[k] = v.Map()
+		}
+		out["$defs"] = defs
+	}
 
 	if len(js.AllOf) > 0 {
 		arr := make([]any, len(js.AllOf))
@@ -335,6 +367,9 @@ func (js *JSONSchemaGeneric[T]) Map() map[string]any {
 	if b := boolPtr(js.AdditionalProperties); b != nil {
 		out["additionalProperties"] = b
 	}
+	if b := boolPtr(js.UnevaluatedProperties); b != nil {
+		out["unevaluatedProperties"] = b
+	}
 	v = js.PropertyNames.Map()
 	if v != nil {
 		out["propertyNames"] = v
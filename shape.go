@@ -117,8 +117,17 @@ func (s *BaseShape) SetShape(shape Shape) {
 	s.Shape = shape
 }
 
+// Validate validates the value against the shape.
+//
+// Besides generic values produced by JSON and YAML decoders, the value can be an arbitrary Go value,
+// such as a struct with "json" tags, a typed slice or map, a pointer, time.Time, json.Number or *big.Int.
+// See toValidationValue for conversion rules.
 func (s *BaseShape) Validate(v interface{}) error {
-	return s.Shape.validate(v, "$")
+	val, err := toValidationValue(v)
+	if err != nil {
+		return fmt.Errorf("convert value: %w", err)
+	}
+	return s.Shape.validate(val, "$")
 }
 
 const HookBeforeBaseShapeInherit = "BaseShape.Inherit"
@@ -404,6 +413,24 @@ func (s *BaseShape) String() string {
 	return r
 }
 
+// referencedNamedType returns the named type the shape refers to without adding any facets.
+func referencedNamedType(base *BaseShape) *BaseShape {
+	if base.TypeLabel == "" || base.raml == nil {
+		return nil
+	}
+	ref, err := base.raml.GetReferencedType(base.TypeLabel, base.Location)
+	if err != nil || ref == base || ref.ID == base.ID || !ref.IsUnwrapped() {
+		return nil
+	}
+	// Types that inherit the named type may override its facets and are described in place.
+	for _, parent := range base.Inherits {
+		if parent.ID == ref.ID {
+			return nil
+		}
+	}
+	return ref
+}
+
 // Examples represents a collection of examples.
 type Examples struct {
 	ID  string
@@ -521,6 +548,45 @@ func identifyShapeType(shapeFacets []*yaml.Node) (string, error) {
 	return t, nil
 }
 
+// shapeKind returns the RAML type name of the kind of the shape.
+func shapeKind(shape Shape) string {
+	switch shape.(type) {
+	case *ObjectShape:
+		return TypeObject
+	case *ArrayShape:
+		return TypeArray
+	case *UnionShape:
+		return TypeUnion
+	case *StringShape:
+		return TypeString
+	case *IntegerShape:
+		return TypeInteger
+	case *NumberShape:
+		return TypeNumber
+	case *BooleanShape:
+		return TypeBoolean
+	case *FileShape:
+		return TypeFile
+	case *DateTimeShape:
+		return TypeDatetime
+	case *DateTimeOnlyShape:
+		return TypeDatetimeOnly
+	case *DateOnlyShape:
+		return TypeDateOnly
+	case *TimeOnlyShape:
+		return TypeTimeOnly
+	case *NilShape:
+		return TypeNil
+	case *AnyShape:
+		return TypeAny
+	case *JSONShape:
+		return TypeJSON
+	case *RecursiveShape:
+		return TypeRecursive
+	}
+	return fmt.Sprintf("%T", shape)
+}
+
 func (r *RAML) MakeRecursiveShape(headBase *BaseShape) *BaseShape {
 	recursiveBase := r.MakeBaseShape(headBase.Name, headBase.Location, headBase.Position)
 	recursiveBase.Name = headBase.Name
@@ -671,7 +737,9 @@ func (r *RAML) makeShapeType(
 			}
 		case TagInclude:
 			baseDir := filepath.Dir(location)
-			dt, errParse := r.parseDataType(filepath.Join(baseDir, shapeTypeNode.Value))
+			dtPath := filepath.Join(baseDir, shapeTypeNode.Value)
+			r.addDependency(location, dtPath)
+			dt, errParse := r.parseDataType(dtPath)
 			if errParse != nil {
 				return "", nil, StacktraceNewWrapped("parse data", errParse, location,
 					WithNodePosition(shapeTypeNode))
@@ -774,7 +842,9 @@ func (s *BaseShape) decodeExamples(valueNode *yaml.Node) error {
 	}
 	if valueNode.Kind == yaml.ScalarNode && valueNode.Tag == "!include" {
 		baseDir := filepath.Dir(s.Location)
-		n, err := s.raml.parseNamedExample(filepath.Join(baseDir, valueNode.Value))
+		nePath := filepath.Join(baseDir, valueNode.Value)
+		s.raml.addDependency(s.Location, nePath)
+		n, err := s.raml.parseNamedExample(nePath)
 		if err != nil {
 			return StacktraceNewWrapped("parse named example", err, s.Location,
 				WithNodePosition(valueNode))
@@ -0,0 +1,484 @@
+package raml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// StreamError describes a value that does not conform to the shape found during streaming validation.
+type StreamError struct {
+	// Record is the zero-based index of the JSON document in the stream. It is always 0 for a single document.
+	Record int
+	// Path is the JSON path of the value, e.g. "$.items[2].name".
+	Path string
+	// Offset is the input byte offset right after the value.
+	Offset int64
+	Err    error
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %v", e.Path, e.Offset, e.Err)
+}
+
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamValidationError contains all violations found during streaming validation.
+type StreamValidationError struct {
+	Errors []*StreamError
+	// Truncated is true if more violations were found than allowed by OptStreamMaxErrors.
+	Truncated bool
+}
+
+func (e *StreamValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, se := range e.Errors {
+		msgs[i] = se.Error()
+	}
+	msg := strings.Join(msgs, "; ")
+	if e.Truncated {
+		msg += "; too many errors"
+	}
+	return msg
+}
+
+type streamOptions struct {
+	maxErrors int
+}
+
+type StreamOpt interface {
+	Apply(*streamOptions)
+}
+
+type streamOptMaxErrors int
+
+func (o streamOptMaxErrors) Apply(opt *streamOptions) {
+	opt.maxErrors = int(o)
+}
+
+// OptStreamMaxErrors limits the number of collected violations. Validation stops as soon as the limit is
+// exceeded. Zero means no limit.
+func OptStreamMaxErrors(n int) StreamOpt {
+	return streamOptMaxErrors(n)
+}
+
+// ValidateStream validates a single JSON document read from r against the shape without decoding
+// the whole document into memory.
+//
+// Objects and arrays are validated token by token, array items are validated one at a time and
+// only the current JSON path is kept in memory. Values are buffered only when a shape requires the whole
+// value at once: union members, array items with "uniqueItems" facet and properties matching
+// several pattern properties.
+//
+// The shape must be unwrapped. Violations are returned as *StreamValidationError.
+func (s *BaseShape) ValidateStream(r io.Reader, opts ...StreamOpt) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	sv := newStreamValidator(dec, opts...)
+	if err := sv.validateDocument(s); err != nil {
+		return err
+	}
+	if sv.truncated {
+		return sv.result()
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return fmt.Errorf("read token at offset %d: %w", dec.InputOffset(), err)
+		}
+		return fmt.Errorf("unexpected data after top-level value at offset %d", dec.InputOffset())
+	}
+	return sv.result()
+}
+
+// ValidateDecoder validates the next JSON value read from dec against the shape in a streaming manner.
+// See ValidateStream for details. The decoder should be configured with UseNumber to preserve big integers.
+func (s *BaseShape) ValidateDecoder(dec *json.Decoder, opts ...StreamOpt) error {
+	sv := newStreamValidator(dec, opts...)
+	if err := sv.validateDocument(s); err != nil {
+		return err
+	}
+	return sv.result()
+}
+
+// ValidateNDJSON validates every JSON document of a newline-delimited JSON stream against the shape.
+// Documents are validated one at a time in a streaming manner, see ValidateStream for details.
+// StreamError.Record contains the index of the document the violation belongs to.
+func (s *BaseShape) ValidateNDJSON(r io.Reader, opts ...StreamOpt) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	sv := newStreamValidator(dec, opts...)
+	for ; dec.More(); sv.record++ {
+		if err := sv.validateDocument(s); err != nil {
+			return fmt.Errorf("record %d: %w", sv.record, err)
+		}
+		if sv.truncated {
+			return sv.result()
+		}
+	}
+	// More reports false on both EOF and malformed input, the latter is reported by Token.
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return fmt.Errorf("record %d: read token at offset %d: %w", sv.record, dec.InputOffset(), err)
+		}
+		return fmt.Errorf("record %d: unexpected token at offset %d", sv.record, dec.InputOffset())
+	}
+	return sv.result()
+}
+
+// errStreamMaxErrors stops validation when the maximum number of errors is exceeded.
+var errStreamMaxErrors = errors.New("too many errors")
+
+type streamValidator struct {
+	dec       *json.Decoder
+	maxErrors int
+	record    int
+	path      []string
+	errs      []*StreamError
+	truncated bool
+}
+
+func newStreamValidator(dec *json.Decoder, opts ...StreamOpt) *streamValidator {
+	sOpts := &streamOptions{}
+	for _, opt := range opts {
+		opt.Apply(sOpts)
+	}
+	return &streamValidator{dec: dec, maxErrors: sOpts.maxErrors, path: []string{"$"}}
+}
+
+// validateDocument validates the next top-level value. The validation is interrupted without error
+// when the maximum number of errors is exceeded.
+func (sv *streamValidator) validateDocument(shape *BaseShape) error {
+	sv.path = sv.path[:1]
+	err := sv.validateValue(shape)
+	if errors.Is(err, errStreamMaxErrors) {
+		return nil
+	}
+	return err
+}
+
+func (sv *streamValidator) result() error {
+	if len(sv.errs) == 0 {
+		return nil
+	}
+	return &StreamValidationError{Errors: sv.errs, Truncated: sv.truncated}
+}
+
+func (sv *streamValidator) ctxPath() string {
+	return strings.Join(sv.path, "")
+}
+
+// report records the violation of the current value. It returns errStreamMaxErrors if validation must stop.
+func (sv *streamValidator) report(err error) error {
+	if err == nil {
+		return nil
+	}
+	if sv.maxErrors > 0 && len(sv.errs) >= sv.maxErrors {
+		sv.truncated = true
+		return errStreamMaxErrors
+	}
+	sv.errs = append(sv.errs, &StreamError{
+		Record: sv.record,
+		Path:   sv.ctxPath(),
+		Offset: sv.dec.InputOffset(),
+		Err:    err,
+	})
+	return nil
+}
+
+func (sv *streamValidator) token() (json.Token, error) {
+	tok, err := sv.dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("read token at offset %d: %w", sv.dec.InputOffset(), err)
+	}
+	return tok, nil
+}
+
+func (sv *streamValidator) validateValue(shape *BaseShape) error {
+	tok, err := sv.token()
+	if err != nil {
+		return err
+	}
+	return sv.validateToken(shape, tok)
+}
+
+func (sv *streamValidator) validateToken(shape *BaseShape, tok json.Token) error {
+	if shape == nil {
+		return sv.skip(tok)
+	}
+	switch s := shape.Shape.(type) {
+	case *RecursiveShape:
+		return sv.validateToken(s.Head, tok)
+	case *AnyShape, *JSONShape, *UnknownShape:
+		// These shapes accept any value.
+		return sv.skip(tok)
+	case *UnionShape:
+		return sv.validateBuffered(shape, tok)
+	case *ObjectShape:
+		if tok == json.Delim('{') {
+			return sv.validateObject(s)
+		}
+	case *ArrayShape:
+		if tok == json.Delim('[') {
+			return sv.validateArray(s)
+		}
+	}
+	if d, ok := tok.(json.Delim); ok {
+		// Composite value where a scalar is expected does not need to be buffered.
+		if err := sv.skip(d); err != nil {
+			return err
+		}
+		got := "object"
+		if d == json.Delim('[') {
+			got = "array"
+		}
+		return sv.report(fmt.Errorf("invalid type, got %s, expected %s", got, shape.Type))
+	}
+	return sv.report(shape.Shape.validate(tok, sv.ctxPath()))
+}
+
+// validateBuffered reads the whole value into memory and validates it by the shape.
+func (sv *streamValidator) validateBuffered(shape *BaseShape, tok json.Token) error {
+	v, err := sv.readValue(tok)
+	if err != nil {
+		return err
+	}
+	return sv.report(shape.Shape.validate(v, sv.ctxPath()))
+}
+
+func (sv *streamValidator) validateObject(s *ObjectShape) error {
+	var count uint64
+	seen := make(map[string]struct{})
+	for sv.dec.More() {
+		tok, err := sv.token()
+		if err != nil {
+			return err
+		}
+		k, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected object key token %v at offset %d", tok, sv.dec.InputOffset())
+		}
+		count++
+		sv.path = append(sv.path, "."+k)
+		if err = sv.validateProperty(s, k); err != nil {
+			return err
+		}
+		sv.path = sv.path[:len(sv.path)-1]
+		if s.Properties != nil {
+			if p, present := s.Properties.Get(k); present && p.Required {
+				seen[k] = struct{}{}
+			}
+		}
+	}
+	// Consume the closing delimiter.
+	if _, err := sv.token(); err != nil {
+		return err
+	}
+
+	if s.MinProperties != nil && count < *s.MinProperties {
+		if err := sv.report(fmt.Errorf("object must have at least %d properties", *s.MinProperties)); err != nil {
+			return err
+		}
+	}
+	if s.MaxProperties != nil && count > *s.MaxProperties {
+		if err := sv.report(fmt.Errorf("object must have not more than %d properties", *s.MaxProperties)); err != nil {
+			return err
+		}
+	}
+	if s.Properties == nil {
+		return nil
+	}
+	var missing []string
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := seen[pair.Key]; pair.Value.Required && !ok {
+			missing = append(missing, pair.Key)
+		}
+	}
+	if len(missing) > 0 {
+		return sv.report(fmt.Errorf("missing required properties: %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+func (sv *streamValidator) validateProperty(s *ObjectShape, k string) error {
+	// Explicitly defined properties have priority over pattern properties.
+	if s.Properties != nil {
+		if p, present := s.Properties.Get(k); present {
+			return sv.validateValue(p.Base)
+		}
+	}
+	tok, err := sv.token()
+	if err != nil {
+		return err
+	}
+	if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+		if err = sv.skip(tok); err != nil {
+			return err
+		}
+		return sv.report(fmt.Errorf("unexpected additional property \"%s\"", k))
+	}
+	if s.PatternProperties == nil {
+		return sv.skip(tok)
+	}
+	var matched []PatternProperty
+	for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Pattern.MatchString(k) {
+			matched = append(matched, pair.Value)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return sv.skip(tok)
+	case 1:
+		return sv.validateToken(matched[0].Base, tok)
+	default:
+		// The value must be buffered since it is validated by several pattern properties.
+		v, err := sv.readValue(tok)
+		if err != nil {
+			return err
+		}
+		_, err = s.validatePatternProperty(k, v, sv.ctxPath())
+		return sv.report(err)
+	}
+}
+
+func (sv *streamValidator) validateArray(s *ArrayShape) error {
+	var count uint64
+	validateUniqueItems := s.UniqueItems != nil && *s.UniqueItems
+	uniqueItems := make(map[uint64]struct{})
+	duplicates := false
+	for ; sv.dec.More(); count++ {
+		sv.path = append(sv.path, "["+strconv.FormatUint(count, 10)+"]")
+		if err := sv.validateItem(s, validateUniqueItems, uniqueItems, &duplicates); err != nil {
+			return err
+		}
+		sv.path = sv.path[:len(sv.path)-1]
+	}
+	// Consume the closing delimiter.
+	if _, err := sv.token(); err != nil {
+		return err
+	}
+
+	if s.MinItems != nil && count < *s.MinItems {
+		if err := sv.report(fmt.Errorf("array must have at least %d items", *s.MinItems)); err != nil {
+			return err
+		}
+	}
+	if s.MaxItems != nil && count > *s.MaxItems {
+		if err := sv.report(fmt.Errorf("array must have not more than %d items", *s.MaxItems)); err != nil {
+			return err
+		}
+	}
+	if duplicates {
+		return sv.report(fmt.Errorf("array contains duplicate items"))
+	}
+	return nil
+}
+
+func (sv *streamValidator) validateItem(
+	s *ArrayShape,
+	validateUniqueItems bool,
+	uniqueItems map[uint64]struct{},
+	duplicates *bool,
+) error {
+	if !validateUniqueItems {
+		return sv.validateValue(s.Items)
+	}
+	// Only hashes of items are kept to detect duplicates.
+	tok, err := sv.token()
+	if err != nil {
+		return err
+	}
+	item, err := sv.readValue(tok)
+	if err != nil {
+		return err
+	}
+	if s.Items != nil {
+		if err = sv.report(s.Items.Shape.validate(item, sv.ctxPath())); err != nil {
+			return err
+		}
+	}
+	itemHash, err := hashInterfaceFast(item)
+	if err != nil {
+		return sv.report(fmt.Errorf("hash array item: %w", err))
+	}
+	if _, ok := uniqueItems[itemHash]; ok {
+		*duplicates = true
+	}
+	uniqueItems[itemHash] = struct{}{}
+	return nil
+}
+
+// readValue reads the value starting with the token into memory.
+func (sv *streamValidator) readValue(tok json.Token) (interface{}, error) {
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]interface{})
+		for sv.dec.More() {
+			kt, err := sv.token()
+			if err != nil {
+				return nil, err
+			}
+			k, ok := kt.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key token %v at offset %d", kt, sv.dec.InputOffset())
+			}
+			vt, err := sv.token()
+			if err != nil {
+				return nil, err
+			}
+			if m[k], err = sv.readValue(vt); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := sv.token(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case json.Delim('['):
+		items := make([]interface{}, 0)
+		for sv.dec.More() {
+			it, err := sv.token()
+			if err != nil {
+				return nil, err
+			}
+			item, err := sv.readValue(it)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if _, err := sv.token(); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	return tok, nil
+}
+
+// skip consumes the rest of the value starting with the token.
+func (sv *streamValidator) skip(tok json.Token) error {
+	if _, ok := tok.(json.Delim); !ok {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		t, err := sv.token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
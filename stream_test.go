@@ -0,0 +1,171 @@
+package raml
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseStreamTestType(t *testing.T, name string) *BaseShape {
+	t.Helper()
+	content := `#%RAML 1.0 Library
+types:
+  Item:
+    properties:
+      name:
+        type: string
+        minLength: 1
+      count:
+        type: integer
+        minimum: 1
+  Node:
+    properties:
+      value: integer
+      children?: Node[]
+  Order:
+    additionalProperties: false
+    properties:
+      id: integer
+      status:
+        enum: [active, closed]
+      note?: string | nil
+      node?: Node
+      tags?:
+        type: string[]
+        uniqueItems: true
+      items:
+        type: Item[]
+        minItems: 1
+        maxItems: 3
+`
+	lib := parseTestLibrary(t, map[string]string{"library.raml": content}, "library.raml", OptWithUnwrap())
+	shape, ok := lib.Types.Get(name)
+	require.True(t, ok)
+	return shape
+}
+
+func TestBaseShape_ValidateStream(t *testing.T) {
+	order := parseStreamTestType(t, "Order")
+
+	type want struct {
+		path   string
+		offset int64
+	}
+	tests := []struct {
+		name    string
+		data    string
+		opts    []StreamOpt
+		want    []want
+		wantErr bool
+	}{
+		{
+			name: "valid document",
+			data: `{"id": 1, "status": "active", "note": null, "tags": ["a", "b"],
+				"node": {"value": 1, "children": [{"value": 2}]},
+				"items": [{"name": "a", "count": 1}]}`,
+		},
+		{
+			name: "invalid nested values",
+			data: `{"id": 1.5, "status": "active", "items": [{"name": "a", "count": 1}, {"name": "", "count": 0}]}`,
+			want: []want{
+				{path: "$.id", offset: 10},
+				{path: "$.items[1].name", offset: 80},
+				{path: "$.items[1].count", offset: 92},
+			},
+		},
+		{
+			name: "object level violations",
+			data: `{"id": 1, "extra": {"a": [1]}, "items": [], "tags": ["a", "a"]}`,
+			want: []want{
+				{path: "$.extra", offset: 29},
+				{path: "$.items", offset: 42},
+				{path: "$.tags", offset: 62},
+				{path: "$", offset: 63},
+			},
+		},
+		{
+			name: "union and recursion",
+			data: `{"id": 1, "status": "active", "note": 1, "node": {"value": 1, "children": [{"value": "x"}]},
+				"items": [{"name": "a", "count": 1}]}`,
+			want: []want{
+				{path: "$.note", offset: 39},
+				{path: "$.node.children[0].value", offset: 88},
+			},
+		},
+		{
+			name: "composite value instead of scalar",
+			data: `{"id": [1, 2], "status": "active", "items": [{"name": "a", "count": 1}]}`,
+			want: []want{{path: "$.id", offset: 13}},
+		},
+		{
+			name: "max errors",
+			data: `{"id": "1", "status": "unknown", "items": []}`,
+			opts: []StreamOpt{OptStreamMaxErrors(1)},
+			want: []want{{path: "$.id", offset: 10}},
+		},
+		{
+			name:    "malformed document",
+			data:    `{"id": 1, "status": `,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			data:    `{"id": 1, "status": "active", "items": [{"name": "a", "count": 1}]} {}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := order.ValidateStream(strings.NewReader(tt.data), tt.opts...)
+			var verr *StreamValidationError
+			if tt.wantErr {
+				require.Error(t, err)
+				require.False(t, errors.As(err, &verr))
+				return
+			}
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorAs(t, err, &verr)
+			got := make([]want, len(verr.Errors))
+			for i, se := range verr.Errors {
+				got[i] = want{path: se.Path, offset: se.Offset}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBaseShape_ValidateNDJSON(t *testing.T) {
+	item := parseStreamTestType(t, "Item")
+	data := `{"name": "a", "count": 1}
+{"name": "b", "count": 0}
+{"name": "", "count": 2}
+`
+	err := item.ValidateNDJSON(strings.NewReader(data))
+	var verr *StreamValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Errors, 2)
+	require.Equal(t, 1, verr.Errors[0].Record)
+	require.Equal(t, "$.count", verr.Errors[0].Path)
+	require.Equal(t, 2, verr.Errors[1].Record)
+	require.Equal(t, "$.name", verr.Errors[1].Path)
+
+	require.NoError(t, item.ValidateNDJSON(strings.NewReader(`{"name": "a", "count": 1}`+"\n")))
+	require.Error(t, item.ValidateNDJSON(strings.NewReader(`{"name": "a", "count": 1}`+"\n{")))
+}
+
+func TestBaseShape_ValidateDecoder(t *testing.T) {
+	item := parseStreamTestType(t, "Item")
+	dec := json.NewDecoder(strings.NewReader(`[{"name": "a", "count": 1}, {"name": "b", "count": 0}]`))
+	dec.UseNumber()
+	_, err := dec.Token()
+	require.NoError(t, err)
+
+	require.NoError(t, item.ValidateDecoder(dec))
+	require.Error(t, item.ValidateDecoder(dec))
+}
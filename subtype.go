@@ -0,0 +1,408 @@
+package raml
+
+import (
+	"fmt"
+	"math/big"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// IsSubtypeOf returns true if every value valid for the shape is also valid for the other shape.
+// See CheckSubtype for details.
+func (s *BaseShape) IsSubtypeOf(other *BaseShape) bool {
+	return CheckSubtype(s, other) == nil
+}
+
+// CheckSubtype checks that every value valid for the sub shape is also valid for the super shape.
+// The returned error describes the first found incompatibility, the path of the error starts with "$".
+//
+// The check is conservative: a nil error guarantees compatibility, while an error may be reported for some
+// compatible shapes, e.g. strings with different but equivalent patterns. Recursive shapes are assumed to be
+// compatible when the same pair of shapes is reached again. The shapes must be unwrapped.
+func CheckSubtype(sub, super *BaseShape) error {
+	c := &subtypeChecker{inProgress: make(map[[2]*BaseShape]struct{})}
+	return c.check(sub, super, "$")
+}
+
+type subtypeChecker struct {
+	// inProgress prevents infinite recursion on recursive shapes. Shapes are compared by identity since IDs
+	// are unique only within a RAML and shapes may come from different parses.
+	inProgress map[[2]*BaseShape]struct{}
+}
+
+func subtypeErrorf(path string, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+}
+
+func (c *subtypeChecker) check(sub, super *BaseShape, path string) error {
+	if rs, ok := sub.Shape.(*RecursiveShape); ok {
+		sub = rs.Head
+	}
+	if rs, ok := super.Shape.(*RecursiveShape); ok {
+		super = rs.Head
+	}
+	if sub == super {
+		return nil
+	}
+	if _, ok := super.Shape.(*AnyShape); ok {
+		return nil
+	}
+	key := [2]*BaseShape{sub, super}
+	if _, ok := c.inProgress[key]; ok {
+		return nil
+	}
+	c.inProgress[key] = struct{}{}
+	defer delete(c.inProgress, key)
+
+	// A value of an enumeration is one of the enum values, so it is enough to validate them.
+	if enum := shapeEnum(sub); enum != nil {
+		for _, v := range enum {
+			if err := super.Validate(v.Value); err != nil {
+				return subtypeErrorf(path, "enum value %v is not valid: %v", v.Value, err)
+			}
+		}
+		return nil
+	}
+	if subUnion, ok := sub.Shape.(*UnionShape); ok {
+		for _, member := range subUnion.AnyOf {
+			if err := c.check(member, super, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if shapeEnum(super) != nil {
+		return subtypeErrorf(path, "enum is required")
+	}
+	if superUnion, ok := super.Shape.(*UnionShape); ok {
+		for _, member := range superUnion.AnyOf {
+			if c.check(sub, member, path) == nil {
+				return nil
+			}
+		}
+		return subtypeErrorf(path, "%s does not match any member of union %s", sub.Type, super.Type)
+	}
+	return c.checkShape(sub, super, path)
+}
+
+func (c *subtypeChecker) checkShape(sub, super *BaseShape, path string) error {
+	switch superShape := super.Shape.(type) {
+	case *ObjectShape:
+		if subShape, ok := sub.Shape.(*ObjectShape); ok {
+			return c.checkObject(sub, subShape, super, superShape, path)
+		}
+	case *ArrayShape:
+		if subShape, ok := sub.Shape.(*ArrayShape); ok {
+			return c.checkArray(subShape, superShape, path)
+		}
+	case *StringShape:
+		if subShape, ok := sub.Shape.(*StringShape); ok {
+			return checkString(subShape, superShape, path)
+		}
+	case *IntegerShape:
+		if subShape, ok := sub.Shape.(*IntegerShape); ok {
+			return checkInteger(subShape, superShape, path)
+		}
+	case *NumberShape:
+		switch subShape := sub.Shape.(type) {
+		case *NumberShape:
+			return checkNumber(subShape, superShape, path)
+		case *IntegerShape:
+			return checkIntegerNumber(subShape, superShape, path)
+		}
+	case *FileShape:
+		if subShape, ok := sub.Shape.(*FileShape); ok {
+			return checkFile(subShape, superShape, path)
+		}
+	case *DateTimeShape:
+		if subShape, ok := sub.Shape.(*DateTimeShape); ok {
+			subFormat, superFormat := dateTimeFormat(subShape.Format), dateTimeFormat(superShape.Format)
+			if subFormat != superFormat {
+				return subtypeErrorf(path, "format %s is not %s", subFormat, superFormat)
+			}
+			return nil
+		}
+	case *BooleanShape:
+		if _, ok := sub.Shape.(*BooleanShape); ok {
+			return nil
+		}
+	case *DateOnlyShape:
+		if _, ok := sub.Shape.(*DateOnlyShape); ok {
+			return nil
+		}
+	case *TimeOnlyShape:
+		if _, ok := sub.Shape.(*TimeOnlyShape); ok {
+			return nil
+		}
+	case *DateTimeOnlyShape:
+		if _, ok := sub.Shape.(*DateTimeOnlyShape); ok {
+			return nil
+		}
+	case *NilShape:
+		if _, ok := sub.Shape.(*NilShape); ok {
+			return nil
+		}
+	}
+	return subtypeErrorf(path, "type %s is not compatible with %s", sub.Type, super.Type)
+}
+
+func (c *subtypeChecker) checkObject(
+	subBase *BaseShape, sub *ObjectShape, superBase *BaseShape, super *ObjectShape, path string,
+) error {
+	subClosed := sub.AdditionalProperties != nil && !*sub.AdditionalProperties
+	superClosed := super.AdditionalProperties != nil && !*super.AdditionalProperties
+	if superClosed && !subClosed {
+		return subtypeErrorf(path, "additional properties are not allowed")
+	}
+
+	for pair := super.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		name, superProp := pair.Key, pair.Value
+		propPath := path + "." + name
+		subProp, ok := orderedGet(sub.Properties, name)
+		switch {
+		case ok:
+			if superProp.Required && !subProp.Required {
+				return subtypeErrorf(propPath, "property is required")
+			}
+			if err := c.check(subProp.Base, superProp.Base, propPath); err != nil {
+				return err
+			}
+		case superProp.Required:
+			return subtypeErrorf(propPath, "property is required")
+		case !subClosed:
+			// The property may still come as an additional property of the sub shape.
+			if err := c.checkAdditional(sub, name, superProp.Base, propPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	for pair := sub.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		name, subProp := pair.Key, pair.Value
+		if _, ok := orderedGet(super.Properties, name); ok {
+			continue
+		}
+		propPath := path + "." + name
+		if superClosed {
+			return subtypeErrorf(propPath, "additional property is not allowed")
+		}
+		if err := c.checkPatternProperty(subProp.Base, super, name, propPath); err != nil {
+			return err
+		}
+	}
+
+	if !subClosed {
+		// Additional properties of the sub shape may match any pattern property of the super shape.
+		for pair := super.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			pattern, superProp := pair.Key, pair.Value
+			if isAnyShape(superProp.Base) {
+				continue
+			}
+			propPath := path + "." + pattern
+			if _, ok := orderedGet(sub.PatternProperties, pattern); !ok {
+				return subtypeErrorf(propPath, "pattern property is required")
+			}
+			for subPair := sub.PatternProperties.Oldest(); subPair != nil; subPair = subPair.Next() {
+				if err := c.check(subPair.Value.Base, superProp.Base, propPath); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := checkBounds("Properties", uintBound(sub.MinProperties), uintBound(sub.MaxProperties),
+		uintBound(super.MinProperties), uintBound(super.MaxProperties), path); err != nil {
+		return err
+	}
+
+	if super.Discriminator != nil {
+		if sub.Discriminator == nil || *sub.Discriminator != *super.Discriminator {
+			return subtypeErrorf(path, "discriminator %s is required", *super.Discriminator)
+		}
+		subValue, superValue := discriminatorValueOf(subBase, sub), discriminatorValueOf(superBase, super)
+		if fmt.Sprint(subValue) != fmt.Sprint(superValue) {
+			return subtypeErrorf(path, "discriminator value %v is not %v", subValue, superValue)
+		}
+	}
+	return nil
+}
+
+// checkAdditional checks that the additional property of the open sub shape is valid for the super property.
+func (c *subtypeChecker) checkAdditional(sub *ObjectShape, name string, super *BaseShape, path string) error {
+	if isAnyShape(super) {
+		return nil
+	}
+	matched := false
+	for pair := sub.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.Pattern.MatchString(name) {
+			continue
+		}
+		matched = true
+		if err := c.check(pair.Value.Base, super, path); err != nil {
+			return err
+		}
+	}
+	if !matched {
+		return subtypeErrorf(path, "property may have any value as an additional property")
+	}
+	return nil
+}
+
+// checkPatternProperty checks that the property of the sub shape is valid for the matching pattern properties
+// of the super shape.
+func (c *subtypeChecker) checkPatternProperty(sub *BaseShape, super *ObjectShape, name string, path string) error {
+	matched := false
+	for pair := super.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.Pattern.MatchString(name) {
+			continue
+		}
+		matched = true
+		if c.check(sub, pair.Value.Base, path) == nil {
+			return nil
+		}
+	}
+	if matched {
+		return subtypeErrorf(path, "property does not match any pattern property")
+	}
+	return nil
+}
+
+func (c *subtypeChecker) checkArray(sub, super *ArrayShape, path string) error {
+	if err := checkBounds("Items", uintBound(sub.MinItems), uintBound(sub.MaxItems),
+		uintBound(super.MinItems), uintBound(super.MaxItems), path); err != nil {
+		return err
+	}
+	if super.UniqueItems != nil && *super.UniqueItems && (sub.UniqueItems == nil || !*sub.UniqueItems) {
+		return subtypeErrorf(path, "items must be unique")
+	}
+	switch {
+	case super.Items == nil || isAnyShape(super.Items):
+		return nil
+	case sub.Items == nil:
+		return subtypeErrorf(path+"[]", "items type is required")
+	default:
+		return c.check(sub.Items, super.Items, path+"[]")
+	}
+}
+
+func checkString(sub, super *StringShape, path string) error {
+	if err := checkBounds("Length", uintBound(sub.MinLength), uintBound(sub.MaxLength),
+		uintBound(super.MinLength), uintBound(super.MaxLength), path); err != nil {
+		return err
+	}
+	if super.Pattern != nil && (sub.Pattern == nil || sub.Pattern.String() != super.Pattern.String()) {
+		return subtypeErrorf(path, "pattern %s is required", super.Pattern.String())
+	}
+	return nil
+}
+
+func checkInteger(sub, super *IntegerShape, path string) error {
+	if integerFormatSize(sub.Format) > integerFormatSize(super.Format) {
+		return subtypeErrorf(path, "format %s is required", *super.Format)
+	}
+	if err := checkBounds("", intBound(sub.Minimum), intBound(sub.Maximum),
+		intBound(super.Minimum), intBound(super.Maximum), path); err != nil {
+		return err
+	}
+	return checkMultipleOf(integerMultipleOf(sub.MultipleOf), super.MultipleOf, path)
+}
+
+func checkIntegerNumber(sub *IntegerShape, super *NumberShape, path string) error {
+	if err := checkBounds("", intBound(sub.Minimum), intBound(sub.Maximum),
+		floatBound(super.Minimum), floatBound(super.Maximum), path); err != nil {
+		return err
+	}
+	return checkMultipleOf(integerMultipleOf(sub.MultipleOf), super.MultipleOf, path)
+}
+
+// integerMultipleOf returns the multipleOf facet of an integer. Integers are always multiples of one.
+func integerMultipleOf(v *float64) *float64 {
+	if v != nil {
+		return v
+	}
+	one := 1.0
+	return &one
+}
+
+func checkNumber(sub, super *NumberShape, path string) error {
+	if super.Format != nil && *super.Format == "float" && (sub.Format == nil || *sub.Format != "float") {
+		return subtypeErrorf(path, "format float is required")
+	}
+	if err := checkBounds("", floatBound(sub.Minimum), floatBound(sub.Maximum),
+		floatBound(super.Minimum), floatBound(super.Maximum), path); err != nil {
+		return err
+	}
+	return checkMultipleOf(sub.MultipleOf, super.MultipleOf, path)
+}
+
+func checkFile(sub, super *FileShape, path string) error {
+	if err := checkBounds("Length", uintBound(sub.MinLength), uintBound(sub.MaxLength),
+		uintBound(super.MinLength), uintBound(super.MaxLength), path); err != nil {
+		return err
+	}
+	if super.FileTypes != nil && (sub.FileTypes == nil || !isCompatibleEnum(super.FileTypes, sub.FileTypes)) {
+		return subtypeErrorf(path, "fileTypes must be a subset of (%s)", super.FileTypes.String())
+	}
+	return nil
+}
+
+// checkMultipleOf checks that every multiple of the sub facet is a multiple of the super facet.
+func checkMultipleOf(sub, super *float64, path string) error {
+	if super == nil {
+		return nil
+	}
+	if sub == nil || !isMultiple(*sub, *super) {
+		return subtypeErrorf(path, "multipleOf %v is required", *super)
+	}
+	return nil
+}
+
+// checkBounds checks that the range of the sub shape lies within the range of the super shape.
+// The suffix is appended to "min" and "max" to get facet names, e.g. "Length" for "minLength".
+func checkBounds(suffix string, subMin, subMax, superMin, superMax *big.Float, path string) error {
+	minName, maxName := "min"+suffix, "max"+suffix
+	if suffix == "" {
+		minName, maxName = "minimum", "maximum"
+	}
+	if superMin != nil && (subMin == nil || subMin.Cmp(superMin) < 0) {
+		return subtypeErrorf(path, "%s %s is required", minName, superMin.String())
+	}
+	if superMax != nil && (subMax == nil || subMax.Cmp(superMax) > 0) {
+		return subtypeErrorf(path, "%s %s is required", maxName, superMax.String())
+	}
+	return nil
+}
+
+// shapeEnum returns the enum facet of the shape if the shape has one.
+func shapeEnum(base *BaseShape) Nodes {
+	switch s := base.Shape.(type) {
+	case *StringShape:
+		return s.Enum
+	case *IntegerShape:
+		return s.Enum
+	case *NumberShape:
+		return s.Enum
+	case *BooleanShape:
+		return s.Enum
+	case *UnionShape:
+		return s.Enum
+	}
+	return nil
+}
+
+func isAnyShape(base *BaseShape) bool {
+	if rs, ok := base.Shape.(*RecursiveShape); ok {
+		base = rs.Head
+	}
+	_, ok := base.Shape.(*AnyShape)
+	return ok
+}
+
+// orderedGet returns the value of the key, the map may be nil.
+func orderedGet[V any](m *orderedmap.OrderedMap[string, V], key string) (V, bool) {
+	if m == nil {
+		var zero V
+		return zero, false
+	}
+	return m.Get(key)
+}
@@ -0,0 +1,165 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSubtype(t *testing.T) {
+	content := `#%RAML 1.0 Library
+types:
+  Name:
+    type: string
+    maxLength: 100
+  ShortName:
+    type: string
+    minLength: 1
+    maxLength: 50
+  Code:
+    type: string
+    pattern: ^[A-Z]+$
+  Status:
+    enum: [active, disabled]
+  ActiveStatus:
+    enum: [active]
+  Count:
+    type: integer
+    format: int32
+    minimum: 0
+  SmallCount:
+    type: integer
+    format: int8
+    minimum: 1
+    maximum: 10
+  Even:
+    type: integer
+    multipleOf: 2
+  Ratio:
+    type: number
+    minimum: 0
+    maximum: 100
+  Base:
+    properties:
+      id: integer
+      note?: string
+  Closed:
+    type: Base
+    additionalProperties: false
+  Extended:
+    type: Base
+    properties:
+      note: string
+      extra?: boolean
+  Loose:
+    properties:
+      id?: integer
+  Typed:
+    properties:
+      id: integer
+      /^x-/: string
+  Tags:
+    type: string[]
+    minItems: 1
+    uniqueItems: true
+  Names:
+    type: ShortName[]
+    minItems: 1
+    maxItems: 3
+    uniqueItems: true
+  Cat:
+    properties:
+      lives: integer
+  Dog:
+    properties:
+      good: boolean
+  Pet: Cat | Dog
+  MaybeName: Name | nil
+  Node:
+    properties:
+      value: string
+      next?: Node
+  ShortNode:
+    properties:
+      value: ShortName
+      next?: ShortNode
+  Anything: any
+  Empty: object
+`
+	r, err := ParseFromString(content, "library.raml", t.TempDir(), OptWithUnwrap())
+	require.NoError(t, err)
+	lib := r.EntryPoint().(*Library)
+	get := func(name string) *BaseShape {
+		s, ok := lib.Types.Get(name)
+		require.True(t, ok, name)
+		return s
+	}
+
+	tests := []struct {
+		sub, super string
+		wantErr    string
+	}{
+		{sub: "ShortName", super: "Name"},
+		{sub: "Name", super: "ShortName", wantErr: "$: minLength 1 is required"},
+		{sub: "Code", super: "Name", wantErr: "$: maxLength 100 is required"},
+		{sub: "Name", super: "Code", wantErr: "$: pattern ^[A-Z]+$ is required"},
+		{sub: "ActiveStatus", super: "Status"},
+		{sub: "ActiveStatus", super: "Name"},
+		{sub: "Status", super: "ActiveStatus", wantErr: `$: enum value disabled is not valid`},
+		{sub: "Name", super: "Status", wantErr: "$: enum is required"},
+		{sub: "SmallCount", super: "Count"},
+		{sub: "Count", super: "SmallCount", wantErr: "$: format int8 is required"},
+		{sub: "SmallCount", super: "Ratio"},
+		{sub: "Ratio", super: "Count", wantErr: "$: type number is not compatible with integer"},
+		{sub: "Count", super: "Even", wantErr: "$: multipleOf 2 is required"},
+		{sub: "Closed", super: "Base"},
+		{sub: "Extended", super: "Base"},
+		{sub: "Base", super: "Closed", wantErr: "$: additional properties are not allowed"},
+		{sub: "Loose", super: "Base", wantErr: "$.id: property is required"},
+		{sub: "Base", super: "Loose"},
+		{sub: "Typed", super: "Base", wantErr: "$.note: property may have any value as an additional property"},
+		{sub: "Base", super: "Typed", wantErr: "$./^x-/: pattern property is required"},
+		{sub: "Names", super: "Tags"},
+		{sub: "Tags", super: "Names", wantErr: "$: maxItems 3 is required"},
+		{sub: "Cat", super: "Pet"},
+		{sub: "Pet", super: "Cat", wantErr: "$.lives: property is required"},
+		{sub: "Name", super: "MaybeName"},
+		{sub: "MaybeName", super: "Name", wantErr: "$: type nil is not compatible with string"},
+		{sub: "ShortNode", super: "Node"},
+		{sub: "Node", super: "ShortNode", wantErr: "$.value: minLength 1 is required"},
+		{sub: "Base", super: "Empty"},
+		{sub: "Empty", super: "Base", wantErr: "$.id: property is required"},
+		{sub: "Pet", super: "Anything"},
+		{sub: "Anything", super: "Name", wantErr: "$: type any is not compatible with string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sub+"<:"+tt.super, func(t *testing.T) {
+			err := CheckSubtype(get(tt.sub), get(tt.super))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				require.True(t, get(tt.sub).IsSubtypeOf(get(tt.super)))
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+			require.False(t, get(tt.sub).IsSubtypeOf(get(tt.super)))
+		})
+	}
+}
+
+func TestCheckSubtype_DifferentParses(t *testing.T) {
+	parse := func(content string, name string) *BaseShape {
+		r, err := ParseFromString(content, name, t.TempDir(), OptWithUnwrap())
+		require.NoError(t, err)
+		s, ok := r.EntryPoint().(*Library).Types.Get("A")
+		require.True(t, ok)
+		return s
+	}
+	oldShape := parse("#%RAML 1.0 Library\ntypes:\n  A: string\n", "o.raml")
+	newShape := parse("#%RAML 1.0 Library\ntypes:\n  A: integer\n", "n.raml")
+	// NOTE: IDs are unique per RAML, so the shapes of different parses may have the same ID.
+	require.Equal(t, oldShape.ID, newShape.ID)
+
+	require.ErrorContains(t, CheckSubtype(oldShape, newShape), "$: type string is not compatible with integer")
+	require.False(t, oldShape.IsSubtypeOf(newShape))
+	require.True(t, oldShape.IsSubtypeOf(oldShape))
+}
@@ -0,0 +1,516 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tsIdentifier matches property names that do not need quotes in TypeScript.
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+type TSModuleResolver func(from, to string) string
+
+type TSGeneratorOptions struct {
+	resolveModule TSModuleResolver
+}
+
+type TSGeneratorOpt interface {
+	apply(*TSGeneratorOptions)
+}
+
+type optTSModuleResolver struct{ f TSModuleResolver }
+
+func (o optTSModuleResolver) apply(c *TSGeneratorOptions) { c.resolveModule = o.f }
+
+// WithTSModuleResolver sets the function that returns the module specifier of the used library location
+// imported by the generated module of the library location. By default, the relative path without
+// the file extension is used.
+func WithTSModuleResolver(f TSModuleResolver) TSGeneratorOpt {
+	return optTSModuleResolver{f}
+}
+
+// TSGenerator generates TypeScript type definitions from the unwrapped types of a RAML library.
+//
+// The output is valid both as a .d.ts and as a .ts file. Objects become interfaces, unions become
+// TypeScript unions with nil mapped to null, discriminator properties become literal types of
+// discriminator values, so that unions of discriminated types are tagged unions. Enums and patterns
+// of alternative literals become unions of literal types. Display names and descriptions become JSDoc.
+// Types of used libraries are referenced through namespace imports named after the library aliases.
+type TSGenerator struct {
+	ShapeVisitor[string]
+
+	opts TSGeneratorOptions
+
+	entry    *Library
+	resolver *namedTypeResolver
+	// imports contains locations of the libraries whose types are referenced.
+	imports map[string]struct{}
+	// inProgress contains IDs of shapes being generated to break recursion.
+	inProgress map[int64]struct{}
+	indent     int
+}
+
+func NewTSGenerator(opt ...TSGeneratorOpt) *TSGenerator {
+	g := &TSGenerator{opts: TSGeneratorOptions{resolveModule: relativeTSModule}}
+	for _, o := range opt {
+		o.apply(&g.opts)
+	}
+	return g
+}
+
+// relativeTSModule returns the relative path from the "from" library to the "to" library without the extension.
+func relativeTSModule(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// Generate generates the TypeScript module for the types of the library. The library must be unwrapped.
+func (g *TSGenerator) Generate(lib *Library) ([]byte, error) {
+	g.entry = lib
+	g.resolver = newNamedTypeResolver(lib)
+	g.imports = make(map[string]struct{})
+	g.inProgress = make(map[int64]struct{})
+	g.indent = 0
+
+	var body strings.Builder
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		if !pair.Value.IsUnwrapped() {
+			return nil, fmt.Errorf("type %s must be unwrapped", pair.Key)
+		}
+		body.WriteString("\n")
+		body.WriteString(g.declaration(pair.Key, pair.Value))
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated by raml gen ts. DO NOT EDIT.\n")
+	imports := make([]string, 0, len(g.imports))
+	for location := range g.imports {
+		imports = append(imports, location)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return g.namespace(imports[i]) < g.namespace(imports[j])
+	})
+	if len(imports) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, location := range imports {
+		fmt.Fprintf(&buf, "import type * as %s from %s;\n", g.namespace(location),
+			tsString(g.opts.resolveModule(lib.Location, location)))
+	}
+	buf.WriteString(body.String())
+	return []byte(buf.String()), nil
+}
+
+// namespace returns the import namespace of the library. Libraries used by used libraries are named by the chain
+// of aliases.
+func (g *TSGenerator) namespace(location string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(g.resolver.prefix(location), "."), ".", "_")
+}
+
+func (g *TSGenerator) declaration(name string, base *BaseShape) string {
+	doc := tsDoc(base, "")
+	obj, ok := base.Shape.(*ObjectShape)
+	if !ok || obj.Properties == nil || obj.Properties.Len() == 0 || obj.PatternProperties != nil {
+		return fmt.Sprintf("%sexport type %s = %s;\n", doc, name, g.Visit(base.Shape))
+	}
+
+	g.inProgress[base.ID] = struct{}{}
+	defer delete(g.inProgress, base.ID)
+
+	extends := ""
+	var parent *ObjectShape
+	nt, found := g.resolver.referenced(base)
+	var ref string
+	if found {
+		ref, found = g.reference(nt)
+	}
+	if found {
+		refShape := nt.shape
+		if parentObj, isObject := refShape.Shape.(*ObjectShape); isObject && isSubset(parentObj, obj) {
+			extends = " extends " + ref
+			parent = parentObj
+			if base.Description != nil && refShape.Description != nil && *base.Description == *refShape.Description {
+				// Skip the description inherited by unwrapping.
+				doc = tsComment(base.DisplayName, nil, "")
+			}
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sexport interface %s%s {\n", doc, name, extends)
+	literal := tsDiscriminatorLiteral(base, obj, parent)
+	g.indent++
+	for pair := obj.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		typ := g.typeExpr(prop.Base)
+		if obj.Discriminator != nil && prop.Name == *obj.Discriminator && literal != "" {
+			typ = literal
+		}
+		if parent != nil {
+			parentProp, found := parent.Properties.Get(prop.Name)
+			if found && parentProp.Required == prop.Required && g.typeExpr(parentProp.Base) == typ {
+				continue
+			}
+		}
+		b.WriteString(g.property(prop, typ))
+	}
+	g.indent--
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// isSubset reports whether all properties of the parent are defined in the child.
+func isSubset(parent, child *ObjectShape) bool {
+	if parent.Properties == nil {
+		return true
+	}
+	if child.Properties == nil {
+		return false
+	}
+	for pair := parent.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if _, ok := child.Properties.Get(pair.Key); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tsDiscriminatorLiteral returns the literal type of the discriminator value of the named object.
+// Types that declare the discriminator without a discriminator value keep the declared type, so that
+// they can be used as base types of discriminated types.
+func tsDiscriminatorLiteral(base *BaseShape, obj *ObjectShape, parent *ObjectShape) string {
+	if obj.Discriminator == nil {
+		return ""
+	}
+	if obj.DiscriminatorValue == nil && (parent == nil || parent.Discriminator == nil) {
+		return ""
+	}
+	return tsLiteral(discriminatorValueOf(base, obj))
+}
+
+func (g *TSGenerator) property(prop Property, typ string) string {
+	indent := strings.Repeat("  ", g.indent)
+	name := prop.Name
+	if !tsIdentifier.MatchString(name) {
+		name = tsString(name)
+	}
+	optional := ""
+	if !prop.Required {
+		optional = "?"
+	}
+	return fmt.Sprintf("%s%s%s%s: %s;\n", tsDoc(prop.Base, indent), indent, name, optional, typ)
+}
+
+// typeExpr returns the TypeScript type expression of the shape.
+func (g *TSGenerator) typeExpr(base *BaseShape) string {
+	if ref, ok := g.referencedType(base); ok {
+		return ref
+	}
+	if _, ok := g.inProgress[base.ID]; ok {
+		return "unknown"
+	}
+	g.inProgress[base.ID] = struct{}{}
+	defer delete(g.inProgress, base.ID)
+	return g.Visit(base.Shape)
+}
+
+// referencedType returns the reference to the named type the shape refers to.
+func (g *TSGenerator) referencedType(base *BaseShape) (string, bool) {
+	nt, ok := g.resolver.reference(base)
+	if !ok {
+		return "", false
+	}
+	return g.reference(nt)
+}
+
+// reference returns the reference to the named type in the generated module. Types of used libraries are
+// referenced through namespace imports.
+func (g *TSGenerator) reference(nt namedType) (string, bool) {
+	if _, ok := g.resolver.library(nt.location); !ok {
+		return "", false
+	}
+	if nt.location == g.entry.Location {
+		return nt.name, true
+	}
+	g.imports[nt.location] = struct{}{}
+	return g.namespace(nt.location) + "." + nt.name, true
+}
+
+func (g *TSGenerator) Visit(s Shape) string {
+	switch shapeType := s.(type) {
+	case *ObjectShape:
+		return g.VisitObjectShape(shapeType)
+	case *ArrayShape:
+		return g.VisitArrayShape(shapeType)
+	case *StringShape:
+		return g.VisitStringShape(shapeType)
+	case *NumberShape:
+		return g.VisitNumberShape(shapeType)
+	case *IntegerShape:
+		return g.VisitIntegerShape(shapeType)
+	case *BooleanShape:
+		return g.VisitBooleanShape(shapeType)
+	case *FileShape:
+		return g.VisitFileShape(shapeType)
+	case *UnionShape:
+		return g.VisitUnionShape(shapeType)
+	case *NilShape:
+		return g.VisitNilShape(shapeType)
+	case *AnyShape:
+		return g.VisitAnyShape(shapeType)
+	case *DateTimeShape:
+		return g.VisitDateTimeShape(shapeType)
+	case *DateTimeOnlyShape:
+		return g.VisitDateTimeOnlyShape(shapeType)
+	case *DateOnlyShape:
+		return g.VisitDateOnlyShape(shapeType)
+	case *TimeOnlyShape:
+		return g.VisitTimeOnlyShape(shapeType)
+	case *JSONShape:
+		return g.VisitJSONShape(shapeType)
+	case *RecursiveShape:
+		return g.VisitRecursiveShape(shapeType)
+	default:
+		return "unknown"
+	}
+}
+
+func (g *TSGenerator) VisitObjectShape(s *ObjectShape) string {
+	var index []string
+	if s.PatternProperties != nil {
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			index = append(index, g.typeExpr(pair.Value.Base))
+		}
+	}
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		if len(index) == 0 {
+			return "Record<string, unknown>"
+		}
+		return "Record<string, " + tsUnion(index) + ">"
+	}
+
+	indent := strings.Repeat("  ", g.indent)
+	g.indent++
+	var b strings.Builder
+	b.WriteString("{\n")
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		b.WriteString(g.property(pair.Value, g.typeExpr(pair.Value.Base)))
+	}
+	if len(index) > 0 {
+		// Index signatures must be compatible with all properties.
+		fmt.Fprintf(&b, "%s[key: string]: unknown;\n", strings.Repeat("  ", g.indent))
+	}
+	g.indent--
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func (g *TSGenerator) VisitArrayShape(s *ArrayShape) string {
+	if s.Items == nil {
+		return "unknown[]"
+	}
+	item := g.typeExpr(s.Items)
+	if strings.Contains(item, " | ") || strings.Contains(item, " & ") {
+		item = "(" + item + ")"
+	}
+	return item + "[]"
+}
+
+func (g *TSGenerator) VisitUnionShape(s *UnionShape) string {
+	members := make([]string, 0, len(s.AnyOf))
+	for _, item := range s.AnyOf {
+		members = append(members, g.typeExpr(item))
+	}
+	return tsUnion(members)
+}
+
+func (g *TSGenerator) VisitStringShape(s *StringShape) string {
+	if len(s.Enum) > 0 {
+		return tsEnum(s.Enum)
+	}
+	if s.Pattern != nil {
+		if literals, ok := tsPatternLiterals(s.Pattern.String()); ok {
+			return tsUnion(literals)
+		}
+	}
+	return "string"
+}
+
+func (g *TSGenerator) VisitIntegerShape(s *IntegerShape) string {
+	if len(s.Enum) > 0 {
+		return tsEnum(s.Enum)
+	}
+	return "number"
+}
+
+func (g *TSGenerator) VisitNumberShape(s *NumberShape) string {
+	if len(s.Enum) > 0 {
+		return tsEnum(s.Enum)
+	}
+	return "number"
+}
+
+func (g *TSGenerator) VisitBooleanShape(s *BooleanShape) string {
+	if len(s.Enum) > 0 {
+		return tsEnum(s.Enum)
+	}
+	return "boolean"
+}
+
+func (g *TSGenerator) VisitFileShape(_ *FileShape) string {
+	return "string"
+}
+
+func (g *TSGenerator) VisitNilShape(_ *NilShape) string {
+	return "null"
+}
+
+func (g *TSGenerator) VisitAnyShape(_ *AnyShape) string {
+	return "unknown"
+}
+
+func (g *TSGenerator) VisitDateTimeShape(_ *DateTimeShape) string {
+	return "string"
+}
+
+func (g *TSGenerator) VisitDateTimeOnlyShape(_ *DateTimeOnlyShape) string {
+	return "string"
+}
+
+func (g *TSGenerator) VisitDateOnlyShape(_ *DateOnlyShape) string {
+	return "string"
+}
+
+func (g *TSGenerator) VisitTimeOnlyShape(_ *TimeOnlyShape) string {
+	return "string"
+}
+
+func (g *TSGenerator) VisitJSONShape(_ *JSONShape) string {
+	return "unknown"
+}
+
+func (g *TSGenerator) VisitRecursiveShape(s *RecursiveShape) string {
+	if ref, ok := g.referencedType(s.Head); ok {
+		return ref
+	}
+	return g.typeExpr(s.Head)
+}
+
+// tsUnion joins the members into a union type without duplicates.
+func tsUnion(members []string) string {
+	seen := make(map[string]struct{}, len(members))
+	unique := make([]string, 0, len(members))
+	for _, m := range members {
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		unique = append(unique, m)
+	}
+	return strings.Join(unique, " | ")
+}
+
+func tsEnum(enum Nodes) string {
+	literals := make([]string, len(enum))
+	for i, e := range enum {
+		literals[i] = tsLiteral(e.Value)
+	}
+	return tsUnion(literals)
+}
+
+func tsLiteral(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return tsString(fmt.Sprint(v))
+	}
+	return string(b)
+}
+
+func tsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// tsPatternLiterals returns string literals of a pattern that matches a fixed set of strings,
+// such as "^(asc|desc)$".
+func tsPatternLiterals(pattern string) ([]string, bool) {
+	if !strings.HasPrefix(pattern, "^") || !strings.HasSuffix(pattern, "$") {
+		return nil, false
+	}
+	pattern = pattern[1 : len(pattern)-1]
+	// NOTE: Anchors bind tighter than alternation, "^asc|desc$" means "(^asc)|(desc$)". Alternatives are
+	// accepted only inside a single group, an ungrouped alternation is rejected as an unescaped "|" below.
+	alts := []string{pattern}
+	if strings.HasPrefix(pattern, "(") && strings.HasSuffix(pattern, ")") {
+		alts = strings.Split(strings.TrimPrefix(pattern[1:len(pattern)-1], "?:"), "|")
+	}
+	var literals []string
+	for _, alt := range alts {
+		var b strings.Builder
+		escaped := false
+		for _, r := range alt {
+			switch {
+			case escaped:
+				if !strings.ContainsRune(`\.^$|?*+()[]{}/-`, r) {
+					return nil, false
+				}
+				b.WriteRune(r)
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case strings.ContainsRune(`.^$|?*+()[]{}`, r):
+				return nil, false
+			default:
+				b.WriteRune(r)
+			}
+		}
+		if escaped {
+			return nil, false
+		}
+		literals = append(literals, tsString(b.String()))
+	}
+	return literals, true
+}
+
+// tsDoc returns the JSDoc comment with the display name and the description of the shape.
+func tsDoc(base *BaseShape, indent string) string {
+	return tsComment(base.DisplayName, base.Description, indent)
+}
+
+func tsComment(displayName, description *string, indent string) string {
+	var lines []string
+	if displayName != nil && *displayName != "" {
+		lines = append(lines, strings.Split(strings.TrimSpace(*displayName), "\n")...)
+	}
+	if description != nil && *description != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(strings.TrimSpace(*description), "\n")...)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		line = strings.ReplaceAll(strings.TrimRight(line, " \t"), "*/", "*\\/")
+		if line == "" {
+			b.WriteString(indent + " *\n")
+		} else {
+			b.WriteString(indent + " * " + line + "\n")
+		}
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
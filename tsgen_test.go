@@ -0,0 +1,158 @@
+package raml
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSGenerator_Generate(t *testing.T) {
+	common := `#%RAML 1.0 Library
+types:
+  Status:
+    type: string
+    enum: [active, disabled]
+`
+	library := `#%RAML 1.0 Library
+uses:
+  common: common.raml
+types:
+  Pet:
+    displayName: A pet
+    description: Any pet.
+    discriminator: kind
+    properties:
+      kind: string
+      name: string
+  Cat:
+    type: Pet
+    properties:
+      lives: integer
+  Dog:
+    type: Pet
+    discriminatorValue: doggo
+    properties:
+      good: boolean
+  Animal: Cat | Dog
+  Sort:
+    type: string
+    pattern: ^(asc|desc)$
+  Code:
+    type: string
+    pattern: ^[a-z]+$
+  Node:
+    properties:
+      value: string
+      next?: Node
+  Order:
+    properties:
+      status: common.Status
+      "x-id": integer
+      pets: (Cat | Dog)[]
+      owner: nil | Owner
+      labels:
+        properties:
+          /.*/: string
+      extra:
+        description: Extra data.
+        properties:
+          weight?: number
+  Owner:
+    properties:
+      name: string
+`
+	lib := parseTestLibrary(t, map[string]string{"common.raml": common, "library.raml": library}, "library.raml",
+		OptWithUnwrap())
+
+	out, err := NewTSGenerator().Generate(lib)
+	require.NoError(t, err)
+	want := `// Code generated by raml gen ts. DO NOT EDIT.
+
+import type * as common from "./common";
+
+/**
+ * A pet
+ *
+ * Any pet.
+ */
+export interface Pet {
+  kind: string;
+  name: string;
+}
+
+export interface Cat extends Pet {
+  lives: number;
+  kind: "Cat";
+}
+
+export interface Dog extends Pet {
+  good: boolean;
+  kind: "doggo";
+}
+
+export type Animal = Cat | Dog;
+
+export type Sort = "asc" | "desc";
+
+export type Code = string;
+
+export interface Node {
+  value: string;
+  next?: Node;
+}
+
+export interface Order {
+  status: common.Status;
+  "x-id": number;
+  pets: (Cat | Dog)[];
+  owner: null | Owner;
+  labels: Record<string, string>;
+  /**
+   * Extra data.
+   */
+  extra: {
+    weight?: number;
+  };
+}
+
+export interface Owner {
+  name: string;
+}
+`
+	require.Equal(t, want, string(out))
+
+	commonOut, err := NewTSGenerator().Generate(lib.Uses.Value("common").Link)
+	require.NoError(t, err)
+	require.Contains(t, string(commonOut), `export type Status = "active" | "disabled";`)
+
+	resolved, err := NewTSGenerator(WithTSModuleResolver(func(_, to string) string {
+		return "@types/" + filepath.Base(to)
+	})).Generate(lib)
+	require.NoError(t, err)
+	require.Contains(t, string(resolved), `import type * as common from "@types/common.raml";`)
+}
+
+func TestTSPatternLiterals(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+		ok      bool
+	}{
+		{pattern: "^(asc|desc)$", want: []string{`"asc"`, `"desc"`}, ok: true},
+		{pattern: "^(?:a|b)$", want: []string{`"a"`, `"b"`}, ok: true},
+		{pattern: `^v1\.0$`, want: []string{`"v1.0"`}, ok: true},
+		{pattern: "^[a-z]+$"},
+		{pattern: "asc|desc"},
+		{pattern: "^asc|desc$"},
+		{pattern: "^(asc)|(desc)$"},
+		{pattern: `^a\d$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, ok := tsPatternLiterals(tt.pattern)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
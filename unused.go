@@ -0,0 +1,294 @@
+package raml
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/acronis/go-stacktrace"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// UnusedType is a type or an annotation type declared in a library that no entrypoint reaches.
+type UnusedType struct {
+	Name string `json:"name"`
+	// AnnotationType is true if the unused declaration is an annotation type.
+	AnnotationType bool `json:"annotationType,omitempty"`
+
+	Location string `json:"location"`
+	stacktrace.Position
+}
+
+// UnusedUse is an alias of the "uses" section that no type expression or annotation of the library refers to.
+type UnusedUse struct {
+	Alias string `json:"alias"`
+	// Target is the location of the library the alias points to.
+	Target string `json:"target"`
+
+	Location string `json:"location"`
+	stacktrace.Position
+}
+
+// UnusedReport is the result of FindUnused.
+type UnusedReport struct {
+	Types []UnusedType `json:"types"`
+	Uses  []UnusedUse  `json:"uses"`
+}
+
+// Empty returns true if nothing unused has been found.
+func (r *UnusedReport) Empty() bool {
+	return len(r.Types) == 0 && len(r.Uses) == 0
+}
+
+// aliasRefRe matches the library alias of a qualified name in a type expression or an annotation name.
+var aliasRefRe = regexp.MustCompile(`([A-Za-z0-9_-]+)\.[A-Za-z0-9_-]+`)
+
+type unusedShapeKey struct {
+	raml *RAML
+	id   int64
+}
+
+type unusedLibraryKey struct {
+	raml     *RAML
+	location string
+}
+
+type unusedDeclKey struct {
+	location   string
+	name       string
+	annotation bool
+}
+
+// unusedFinder builds the reference graph of the libraries reachable from the entrypoints.
+type unusedFinder struct {
+	libraries map[unusedLibraryKey]*Library
+	declared  map[unusedShapeKey]unusedDeclKey
+	decls     map[unusedDeclKey]*BaseShape
+	reached   map[unusedDeclKey]struct{}
+	visited   map[*BaseShape]struct{}
+	// aliases contains the library aliases referenced by the shapes and the annotations of every file.
+	aliases  map[unusedLibraryKey]map[string]struct{}
+	uses     map[string]UnusedUse
+	usedUses map[string]struct{}
+}
+
+// FindUnused reports the types and the annotation types that no entrypoint reaches and the "uses" aliases that are
+// never referenced. All types and annotation types of the entrypoints are considered to be used. Libraries are
+// collected by following the "uses" chain of the entrypoints across files.
+//
+// The entrypoints may belong to different RAML instances. In this case a declaration is reported only if none of
+// the entrypoints reaches it. Both wrapped and unwrapped models are supported.
+func FindUnused(entrypoints ...*Library) *UnusedReport {
+	f := &unusedFinder{
+		libraries: make(map[unusedLibraryKey]*Library),
+		declared:  make(map[unusedShapeKey]unusedDeclKey),
+		decls:     make(map[unusedDeclKey]*BaseShape),
+		reached:   make(map[unusedDeclKey]struct{}),
+		visited:   make(map[*BaseShape]struct{}),
+		aliases:   make(map[unusedLibraryKey]map[string]struct{}),
+		uses:      make(map[string]UnusedUse),
+		usedUses:  make(map[string]struct{}),
+	}
+	for _, lib := range entrypoints {
+		f.collect(lib)
+	}
+	for _, lib := range entrypoints {
+		if lib == nil {
+			continue
+		}
+		f.visitTypes(lib.Types)
+		f.visitTypes(lib.AnnotationTypes)
+		f.visitAnnotations(lib.CustomDomainProperties)
+	}
+	return f.report()
+}
+
+// collect registers the declarations and the "uses" aliases of the library and the libraries it uses.
+func (f *unusedFinder) collect(lib *Library) {
+	if lib == nil {
+		return
+	}
+	key := unusedLibraryKey{raml: lib.raml, location: lib.Location}
+	if _, ok := f.libraries[key]; ok {
+		return
+	}
+	f.libraries[key] = lib
+	f.collectDeclarations(lib, lib.Types, false)
+	f.collectDeclarations(lib, lib.AnnotationTypes, true)
+	if lib.Uses == nil {
+		return
+	}
+	aliases := f.referencedAliases(key)
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		link := pair.Value
+		useKey := lib.Location + "#" + pair.Key
+		if _, ok := aliases[pair.Key]; ok {
+			f.usedUses[useKey] = struct{}{}
+		} else if _, ok = f.uses[useKey]; !ok {
+			u := UnusedUse{Alias: pair.Key, Location: lib.Location, Position: link.Position}
+			if link.Link != nil {
+				u.Target = link.Link.Location
+			}
+			f.uses[useKey] = u
+		}
+		f.collect(link.Link)
+	}
+}
+
+func (f *unusedFinder) collectDeclarations(
+	lib *Library, types *orderedmap.OrderedMap[string, *BaseShape], annotation bool,
+) {
+	if types == nil {
+		return
+	}
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		decl := unusedDeclKey{location: lib.Location, name: pair.Key, annotation: annotation}
+		f.declared[unusedShapeKey{raml: lib.raml, id: pair.Value.ID}] = decl
+		if _, ok := f.decls[decl]; !ok {
+			f.decls[decl] = pair.Value
+		}
+	}
+}
+
+// referencedAliases returns the library aliases referenced in the file by type expressions and annotations.
+func (f *unusedFinder) referencedAliases(key unusedLibraryKey) map[string]struct{} {
+	if key.raml == nil {
+		return nil
+	}
+	if _, ok := f.aliases[key]; !ok {
+		// NOTE: Collect aliases of all files at once since every shape of the RAML instance is checked.
+		for _, base := range key.raml.GetShapes() {
+			f.addAliases(base.Location, key.raml, base.Type, base.TypeLabel)
+		}
+		for _, de := range key.raml.GetAllAnnotationsPtr() {
+			f.addAliases(de.Location, key.raml, de.Name)
+		}
+		if _, ok = f.aliases[key]; !ok {
+			f.aliases[key] = make(map[string]struct{})
+		}
+	}
+	return f.aliases[key]
+}
+
+func (f *unusedFinder) addAliases(location string, r *RAML, values ...string) {
+	key := unusedLibraryKey{raml: r, location: location}
+	aliases, ok := f.aliases[key]
+	if !ok {
+		aliases = make(map[string]struct{})
+		f.aliases[key] = aliases
+	}
+	for _, v := range values {
+		for _, m := range aliasRefRe.FindAllStringSubmatch(v, -1) {
+			aliases[m[1]] = struct{}{}
+		}
+	}
+}
+
+func (f *unusedFinder) visitTypes(types *orderedmap.OrderedMap[string, *BaseShape]) {
+	if types == nil {
+		return
+	}
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		f.visit(pair.Value)
+	}
+}
+
+func (f *unusedFinder) visitAnnotations(annotations *orderedmap.OrderedMap[string, *DomainExtension]) {
+	if annotations == nil {
+		return
+	}
+	for pair := annotations.Oldest(); pair != nil; pair = pair.Next() {
+		f.visit(pair.Value.DefinedBy)
+	}
+}
+
+// visit marks the declarations reachable from the shape.
+func (f *unusedFinder) visit(base *BaseShape) {
+	if base == nil {
+		return
+	}
+	if _, ok := f.visited[base]; ok {
+		return
+	}
+	f.visited[base] = struct{}{}
+	if decl, ok := f.declared[unusedShapeKey{raml: base.raml, id: base.ID}]; ok {
+		f.reached[decl] = struct{}{}
+	}
+
+	for _, parent := range base.Inherits {
+		f.visit(parent)
+	}
+	f.visit(base.Alias)
+	if base.Link != nil {
+		f.visit(base.Link.Shape)
+	}
+	if base.TypeLabel != "" && base.raml != nil {
+		if ref, err := base.raml.GetReferencedType(base.TypeLabel, base.Location); err == nil {
+			f.visit(ref)
+		}
+	}
+	if base.CustomShapeFacetDefinitions != nil {
+		for pair := base.CustomShapeFacetDefinitions.Oldest(); pair != nil; pair = pair.Next() {
+			f.visit(pair.Value.Base)
+		}
+	}
+	f.visitAnnotations(base.CustomDomainProperties)
+
+	switch s := base.Shape.(type) {
+	case *ObjectShape:
+		if s.Properties != nil {
+			for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				f.visit(pair.Value.Base)
+			}
+		}
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				f.visit(pair.Value.Base)
+			}
+		}
+	case *ArrayShape:
+		f.visit(s.Items)
+	case *UnionShape:
+		for _, member := range s.AnyOf {
+			f.visit(member)
+		}
+	case *RecursiveShape:
+		f.visit(s.Head)
+	}
+}
+
+func (f *unusedFinder) report() *UnusedReport {
+	res := &UnusedReport{Types: []UnusedType{}, Uses: []UnusedUse{}}
+	for decl, base := range f.decls {
+		if _, ok := f.reached[decl]; ok {
+			continue
+		}
+		res.Types = append(res.Types, UnusedType{
+			Name:           decl.name,
+			AnnotationType: decl.annotation,
+			Location:       decl.location,
+			Position:       base.Position,
+		})
+	}
+	for key, u := range f.uses {
+		if _, ok := f.usedUses[key]; !ok {
+			res.Uses = append(res.Uses, u)
+		}
+	}
+	sort.Slice(res.Types, func(i, j int) bool {
+		return unusedLess(res.Types[i].Location, res.Types[i].Position, res.Types[j].Location, res.Types[j].Position)
+	})
+	sort.Slice(res.Uses, func(i, j int) bool {
+		return unusedLess(res.Uses[i].Location, res.Uses[i].Position, res.Uses[j].Location, res.Uses[j].Position)
+	})
+	return res
+}
+
+func unusedLess(aLoc string, aPos stacktrace.Position, bLoc string, bPos stacktrace.Position) bool {
+	if aLoc != bLoc {
+		return aLoc < bLoc
+	}
+	if aPos.Line != bPos.Line {
+		return aPos.Line < bPos.Line
+	}
+	return aPos.Column < bPos.Column
+}
@@ -0,0 +1,136 @@
+package raml
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnused(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api.raml": `#%RAML 1.0 Library
+uses:
+  common: common.raml
+  stale: stale.raml
+types:
+  Order:
+    (common.audited):
+    properties:
+      customer: common.Customer
+      items: common.Item[]
+      status: common.Active | common.Closed
+      payload: !include payload.raml
+`,
+		"common.raml": `#%RAML 1.0 Library
+uses:
+  base: base.raml
+  extra: extra.raml
+annotationTypes:
+  audited: nil
+  deprecated: nil
+types:
+  Customer:
+    type: base.Entity
+    properties:
+      address: Address
+  Address:
+    properties:
+      city: string
+  Item: Product
+  Product:
+    properties:
+      sku: string
+  Active:
+    type: string
+  Closed:
+    type: string
+  Orphan:
+    properties:
+      self?: Orphan
+      address: Address
+`,
+		"base.raml": `#%RAML 1.0 Library
+types:
+  Entity:
+    properties:
+      id: Identifier
+  Identifier:
+    type: string
+    facets:
+      kind: Kind
+  Kind:
+    type: string
+  Unreached:
+    type: string
+`,
+		"extra.raml": `#%RAML 1.0 Library
+types:
+  Extra:
+    type: string
+`,
+		"stale.raml": `#%RAML 1.0 Library
+types:
+  Stale:
+    type: string
+`,
+		"payload.raml": `#%RAML 1.0 DataType
+uses:
+  base: base.raml
+type: object
+properties:
+  kind: base.Kind
+`,
+	}
+	writeTestFiles(t, dir, files)
+
+	summary := func(report *UnusedReport) []string {
+		var res []string
+		for _, u := range report.Types {
+			res = append(res, filepath.Base(u.Location)+" "+u.Name)
+		}
+		for _, u := range report.Uses {
+			res = append(res, filepath.Base(u.Location)+" uses "+u.Alias+" "+filepath.Base(u.Target))
+		}
+		return res
+	}
+	expected := []string{
+		"base.raml Unreached",
+		"common.raml deprecated",
+		"common.raml Orphan",
+		"extra.raml Extra",
+		"stale.raml Stale",
+		"api.raml uses stale stale.raml",
+		"common.raml uses extra extra.raml",
+	}
+
+	for _, unwrap := range []bool{false, true} {
+		var opts []ParseOpt
+		if unwrap {
+			opts = append(opts, OptWithUnwrap())
+		}
+		r, err := ParseFromPath(filepath.Join(dir, "api.raml"), opts...)
+		require.NoError(t, err)
+		report := FindUnused(r.EntryPoint().(*Library))
+		require.Equal(t, expected, summary(report), "unwrap: %v", unwrap)
+		require.True(t, report.Types[1].AnnotationType)
+		require.Equal(t, 7, report.Types[1].Line)
+	}
+
+	// A declaration is reported only if none of the entrypoints reaches it.
+	api, err := ParseFromPath(filepath.Join(dir, "api.raml"))
+	require.NoError(t, err)
+	stale, err := ParseFromPath(filepath.Join(dir, "stale.raml"))
+	require.NoError(t, err)
+	report := FindUnused(api.EntryPoint().(*Library), stale.EntryPoint().(*Library))
+	require.Equal(t, []string{
+		"base.raml Unreached",
+		"common.raml deprecated",
+		"common.raml Orphan",
+		"extra.raml Extra",
+		"api.raml uses stale stale.raml",
+		"common.raml uses extra extra.raml",
+	}, summary(report))
+	require.False(t, report.Empty())
+}